@@ -5,6 +5,14 @@ generate "provider" {
   contents  = <<EOF
 provider "aws" {
   region = "us-east-1"
+%{if get_env("TERRATEST_RUN_ID", "") != ""}
+  # Tag everything with the test run ID so leftovers from crashed test runs can be swept
+  default_tags {
+    tags = {
+      TerratestRunID = "${get_env("TERRATEST_RUN_ID", "")}"
+    }
+  }
+%{endif}
 }
 EOF
 }
@@ -0,0 +1,171 @@
+terraform {
+  required_version = ">= 1.1"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# DATA TIER EXAMPLE
+# ---------------------------------------------------------------------------------------------------------------------
+# This example composes a PostgreSQL database and a Redis cache behind an application security group:
+# - The app security group may reach PostgreSQL on 5432 and Redis on 6379
+# - Nothing else, including the data stores themselves, may reach either
+# ---------------------------------------------------------------------------------------------------------------------
+
+# ---------------------------------------------------------------------------------------------------------------------
+# USE THE DEFAULT VPC AND SUBNETS
+# To keep this example simple, we use the default VPC and subnets, but in real-world code, you'll want to use a
+# custom VPC.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_vpc" "default" {
+  default = true
+}
+
+data "aws_subnets" "default" {
+  filter {
+    name   = "vpc-id"
+    values = [data.aws_vpc.default.id]
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE THE APPLICATION SECURITY GROUP
+# In real-world usage, this is the security group of the ECS service or ASG running the app.
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "app_sg" {
+  source = "../../../modules/sg"
+
+  name   = "${var.name}-app"
+  vpc_id = data.aws_vpc.default.id
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# DEPLOY POSTGRESQL AND REDIS
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "postgresql" {
+  source = "../../../modules/postgresql"
+
+  name              = var.name
+  master_username   = var.master_username
+  master_password   = var.master_password
+  instance_class    = "db.t4g.micro"
+  allocated_storage = 20
+
+  vpc_id     = data.aws_vpc.default.id
+  subnet_ids = data.aws_subnets.default.ids
+
+  # Use minimal settings for testing
+  multi_az                     = false
+  backup_retention_period      = 0
+  deletion_protection          = false
+  skip_final_snapshot          = true
+  performance_insights_enabled = false
+
+  create_dashboard = var.create_dashboards
+
+  environment = "test"
+}
+
+module "redis" {
+  source = "../../../modules/redis"
+
+  name       = var.name
+  node_type  = "cache.t4g.micro"
+  vpc_id     = data.aws_vpc.default.id
+  subnet_ids = data.aws_subnets.default.ids
+
+  # Use minimal settings for testing
+  num_cache_clusters         = 1
+  automatic_failover_enabled = false
+  multi_az_enabled           = false
+  transit_encryption_enabled = false
+  snapshot_retention_limit   = 0
+
+  create_dashboard = var.create_dashboards
+
+  environment = "test"
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ALLOW THE APP, AND ONLY THE APP, TO REACH THE DATA STORES
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "allow_app_to_postgresql" {
+  source = "../../../modules/sg-rule"
+
+  security_group_id        = module.postgresql.db_security_group_id
+  from_port                = module.postgresql.port
+  to_port                  = module.postgresql.port
+  source_security_group_id = module.app_sg.id
+}
+
+module "allow_app_to_redis" {
+  source = "../../../modules/sg-rule"
+
+  security_group_id        = module.redis.redis_security_group_id
+  from_port                = module.redis.port
+  to_port                  = module.redis.port
+  source_security_group_id = module.app_sg.id
+}
+
+# The app's egress mirrors the data stores' ingress, so connections from the app security group can get out
+module "app_egress_to_postgresql" {
+  source = "../../../modules/sg-rule"
+
+  security_group_id        = module.app_sg.id
+  type                     = "egress"
+  from_port                = module.postgresql.port
+  to_port                  = module.postgresql.port
+  source_security_group_id = module.postgresql.db_security_group_id
+}
+
+module "app_egress_to_redis" {
+  source = "../../../modules/sg-rule"
+
+  security_group_id        = module.app_sg.id
+  type                     = "egress"
+  from_port                = module.redis.port
+  to_port                  = module.redis.port
+  source_security_group_id = module.redis.redis_security_group_id
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE AN OUTSIDER SECURITY GROUP
+# It may send traffic anywhere but neither data store lets it in, so tests can check connections from it are refused.
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "outsider_sg" {
+  source = "../../../modules/sg"
+
+  name   = "${var.name}-outsider"
+  vpc_id = data.aws_vpc.default.id
+}
+
+module "outsider_egress_all" {
+  source = "../../../modules/sg-rule"
+
+  security_group_id = module.outsider_sg.id
+  type              = "egress"
+  from_port         = 0
+  to_port           = 0
+  protocol          = "-1"
+  cidr_blocks       = ["0.0.0.0/0"]
+}
@@ -0,0 +1,69 @@
+output "app_security_group_id" {
+  description = "The ID of the application security group"
+  value       = module.app_sg.id
+}
+
+output "outsider_security_group_id" {
+  description = "The ID of a security group with unrestricted egress that neither data store allows in"
+  value       = module.outsider_sg.id
+}
+
+output "db_security_group_id" {
+  description = "The ID of the PostgreSQL security group"
+  value       = module.postgresql.db_security_group_id
+}
+
+output "redis_security_group_id" {
+  description = "The ID of the Redis security group"
+  value       = module.redis.redis_security_group_id
+}
+
+output "db_port" {
+  description = "The port PostgreSQL listens on"
+  value       = module.postgresql.port
+}
+
+output "redis_port" {
+  description = "The port Redis listens on"
+  value       = module.redis.port
+}
+
+output "db_address" {
+  description = "The hostname of the PostgreSQL instance"
+  value       = module.postgresql.address
+}
+
+output "redis_address" {
+  description = "The hostname of the Redis primary endpoint"
+  value       = module.redis.primary_endpoint_address
+}
+
+output "subnet_ids" {
+  description = "The subnets the data stores are deployed into"
+  value       = data.aws_subnets.default.ids
+}
+
+output "db_arn" {
+  description = "The ARN of the PostgreSQL instance"
+  value       = module.postgresql.arn
+}
+
+output "redis_replication_group_id" {
+  description = "The ID of the Redis replication group"
+  value       = module.redis.id
+}
+
+output "db_dashboard_name" {
+  description = "The name of the PostgreSQL CloudWatch dashboard, when create_dashboards is true"
+  value       = module.postgresql.dashboard_name
+}
+
+output "redis_dashboard_name" {
+  description = "The name of the Redis CloudWatch dashboard, when create_dashboards is true"
+  value       = module.redis.dashboard_name
+}
+
+output "redis_member_clusters" {
+  description = "The IDs of the cache clusters in the Redis replication group"
+  value       = module.redis.member_clusters
+}
@@ -0,0 +1,47 @@
+# ---------------------------------------------------------------------------------------------------------------------
+# REQUIRED VARIABLES
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "name" {
+  description = "The name used for the database, cache, and their security groups"
+  type        = string
+}
+
+variable "master_username" {
+  description = "The master username for the database"
+  type        = string
+}
+
+variable "master_password" {
+  description = "The master password for the database"
+  type        = string
+  sensitive   = true
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "aws_region" {
+  description = "The AWS region to deploy into"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
+
+variable "create_dashboards" {
+  description = "If true, create a CloudWatch dashboard for the database and one for the cache"
+  type        = bool
+  default     = false
+}
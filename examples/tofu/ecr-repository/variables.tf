@@ -33,3 +33,15 @@ variable "scan_on_push" {
   type        = bool
   default     = true
 }
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
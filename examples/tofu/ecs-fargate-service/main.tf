@@ -11,17 +11,24 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
 }
 
 module "ecs_service" {
   source = "../../../modules/ecs-fargate-service"
 
-  name = var.name
+  name            = var.name
+  allowed_regions = var.allowed_regions
 
   # Run the training/webapp Docker image from Docker Hub, a simple "Hello, World" web server
-  container_definitions = jsonencode([{
+  container_definitions = jsonencode([merge({
     name      = var.name
-    image     = "training/webapp"
+    image     = var.container_image
     essential = true
     memory    = local.memory
 
@@ -38,19 +45,146 @@ module "ecs_service" {
         value = "World"
       }
     ]
-  }])
+  }, var.container_command != null ? { command = var.container_command } : {})])
 
-  desired_count  = 2
+  desired_count  = var.desired_count
   cpu            = 256
-  memory         = local.memory
+  memory         = var.enable_firelens ? local.memory_with_log_router : local.memory
   container_port = local.container_port
   alb_port       = 80
 
+  deployment_minimum_healthy_percent = var.deployment_minimum_healthy_percent
+  enable_deployment_circuit_breaker  = var.enable_deployment_circuit_breaker
+  enable_rollback                    = var.enable_rollback
+
+  routed_path_patterns   = var.routed_path_patterns
+  default_fixed_response = var.default_fixed_response
+
+  health_check_path              = var.health_check_path
+  health_check_matcher           = var.health_check_matcher
+  health_check_healthy_threshold = var.health_check_healthy_threshold
+
+  health_check_command      = var.health_check_command
+  health_check_interval     = var.health_check_interval
+  health_check_timeout      = var.health_check_timeout
+  health_check_retries      = var.health_check_retries
+  health_check_start_period = var.health_check_start_period
+
+  # The bucket name is set in config, so whether access logs are enabled is known at plan time
+  access_logs_bucket = var.enable_access_logs ? aws_s3_bucket.access_logs[0].bucket : null
+  access_logs_prefix = var.access_logs_prefix
+
+  enable_https    = var.enable_https
+  certificate_arn = var.certificate_arn
+
+  secrets = var.secrets
+
+  create_dashboard = var.create_dashboard
+
+  enable_autoscaling     = var.enable_autoscaling
+  min_capacity           = var.min_capacity
+  max_capacity           = var.max_capacity
+  target_cpu_utilization = var.target_cpu_utilization
+  scale_out_cooldown     = var.scale_out_cooldown
+  scale_in_cooldown      = var.scale_in_cooldown
+
+  enable_container_insights = var.enable_container_insights
+
+  capacity_providers                 = var.capacity_providers
+  default_capacity_provider_strategy = var.default_capacity_provider_strategy
+  service_capacity_provider_strategy = var.service_capacity_provider_strategy
+
   # The image used for this example only supports X86_64.
   cpu_architecture = "X86_64"
+
+  # Route the app's logs through FluentBit to CloudWatch. Any FluentBit output plugin works here, e.g. datadog or
+  # splunk; CloudWatch is used so the example doesn't depend on a third-party account.
+  firelens_configuration = var.enable_firelens ? {
+    destination = "cloudwatch_logs"
+    options = {
+      region            = var.aws_region
+      log_group_name    = aws_cloudwatch_log_group.firelens[0].name
+      log_stream_prefix = "app-"
+    }
+  } : null
+  task_role_arn = var.enable_firelens ? aws_iam_role.firelens[0].arn : null
+
+  # Stand-in for a run-once setup step such as database migrations. The sleep makes the ordering observable: the app
+  # container stays PENDING until this exits successfully.
+  init_container_definition = var.enable_init_container ? jsonencode({
+    name    = "init"
+    image   = "public.ecr.aws/docker/library/busybox:stable"
+    command = ["sh", "-c", "echo 'Running one-time setup'; sleep 20; echo 'Setup complete'"]
+  }) : null
 }
 
 locals {
   container_port = 5000
   memory         = 512
+
+  # The log router's memory reservation doesn't fit alongside the app container in 512 MB
+  memory_with_log_router = 1024
+
+  firelens_log_group_name = "/ecs/${var.name}/firelens"
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE THE FIRELENS DESTINATION LOG GROUP AND AN IAM ROLE THAT LETS FLUENTBIT WRITE TO IT
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_log_group" "firelens" {
+  count             = var.enable_firelens ? 1 : 0
+  name              = local.firelens_log_group_name
+  retention_in_days = 1
+}
+
+resource "aws_iam_role" "firelens" {
+  count = var.enable_firelens ? 1 : 0
+  name  = "${var.name}-firelens"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Action = "sts:AssumeRole"
+        Effect = "Allow"
+        Principal = {
+          Service = "ecs-tasks.amazonaws.com"
+        }
+      }
+    ]
+  })
+}
+
+resource "aws_iam_role_policy" "firelens" {
+  count = var.enable_firelens ? 1 : 0
+  name  = "write-logs"
+  role  = aws_iam_role.firelens[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "logs:CreateLogStream",
+          "logs:DescribeLogStreams",
+          "logs:PutLogEvents",
+        ]
+        Resource = "${aws_cloudwatch_log_group.firelens[0].arn}:*"
+      }
+    ]
+  })
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A BUCKET FOR THE ALB ACCESS LOGS
+# The module attaches the bucket policy that lets ELB write to it. force_destroy lets the example be torn down with
+# the logs still in it.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_s3_bucket" "access_logs" {
+  count         = var.enable_access_logs ? 1 : 0
+  bucket        = "${var.name}-alb-logs"
+  force_destroy = true
 }
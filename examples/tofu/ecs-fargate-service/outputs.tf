@@ -2,6 +2,78 @@ output "url" {
   value = module.ecs_service.url
 }
 
+output "https_url" {
+  value = module.ecs_service.https_url
+}
+
 output "alb_dns_name" {
   value = module.ecs_service.alb_dns_name
 }
+
+output "access_logs_enabled" {
+  value = module.ecs_service.access_logs_enabled
+}
+
+output "access_logs_bucket" {
+  value = try(aws_s3_bucket.access_logs[0].bucket, null)
+}
+
+output "alb_listener_arn" {
+  value = module.ecs_service.alb_listener_arn
+}
+
+output "paused" {
+  value = module.ecs_service.paused
+}
+
+output "target_group_arn" {
+  value = module.ecs_service.target_group_arn
+}
+
+output "service_security_group_id" {
+  value = module.ecs_service.service_security_group_id
+}
+
+output "alb_security_group_id" {
+  value = module.ecs_service.alb_security_group_id
+}
+
+output "ecs_cluster_name" {
+  value = module.ecs_service.ecs_cluster_name
+}
+
+output "ecs_service_name" {
+  value = module.ecs_service.ecs_service_name
+}
+
+output "launch_type" {
+  value = module.ecs_service.launch_type
+}
+
+output "capacity_provider_strategy" {
+  value = module.ecs_service.capacity_provider_strategy
+}
+
+output "autoscaling_policy_name" {
+  value = module.ecs_service.autoscaling_policy_name
+}
+
+output "scaling_target_resource_id" {
+  value = module.ecs_service.scaling_target_resource_id
+}
+
+output "task_definition_arn" {
+  value = module.ecs_service.task_definition_arn
+}
+
+output "log_group_name" {
+  value = module.ecs_service.log_group_name
+}
+
+output "firelens_log_group_name" {
+  value = var.enable_firelens ? local.firelens_log_group_name : null
+}
+
+output "dashboard_name" {
+  value = module.ecs_service.dashboard_name
+}
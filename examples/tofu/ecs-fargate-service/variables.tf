@@ -18,3 +18,247 @@ variable "aws_region" {
   type        = string
   default     = "us-east-1"
 }
+
+variable "allowed_regions" {
+  description = "The AWS regions the service may be deployed to"
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+}
+
+variable "desired_count" {
+  description = "How many instances of the service to run"
+  type        = number
+  default     = 2
+}
+
+variable "deployment_minimum_healthy_percent" {
+  description = "The lower limit, as a percentage of desired_count, of running tasks that must remain healthy during a deployment"
+  type        = number
+  default     = 100
+}
+
+variable "container_image" {
+  description = "The Docker image to run in the app container"
+  type        = string
+  default     = "training/webapp"
+}
+
+variable "enable_deployment_circuit_breaker" {
+  description = "If set to true, ECS stops deployments whose tasks keep failing"
+  type        = bool
+  default     = false
+}
+
+variable "enable_rollback" {
+  description = "If set to true, a deployment stopped by the circuit breaker rolls back to the last completed deployment"
+  type        = bool
+  default     = true
+}
+
+variable "enable_container_insights" {
+  description = "If set to true, enable CloudWatch Container Insights on the cluster"
+  type        = bool
+  default     = false
+}
+
+variable "capacity_providers" {
+  description = "The capacity providers to associate with the cluster"
+  type        = list(string)
+  default     = ["FARGATE", "FARGATE_SPOT"]
+}
+
+variable "default_capacity_provider_strategy" {
+  description = "The cluster's default capacity provider strategy"
+  type = list(object({
+    capacity_provider = string
+    weight            = number
+    base              = number
+  }))
+  default = [
+    {
+      capacity_provider = "FARGATE"
+      weight            = 1
+      base              = 0
+    }
+  ]
+}
+
+variable "service_capacity_provider_strategy" {
+  description = "The capacity provider strategy for the service. If empty, the service uses the FARGATE launch type."
+  type = list(object({
+    capacity_provider = string
+    weight            = number
+    base              = number
+  }))
+  default = []
+}
+
+variable "enable_firelens" {
+  description = "If set to true, route the app's logs to CloudWatch through a FireLens (FluentBit) log router sidecar"
+  type        = bool
+  default     = false
+}
+
+variable "enable_init_container" {
+  description = "If set to true, run a short-lived init container that must exit successfully before the app container starts"
+  type        = bool
+  default     = false
+}
+
+variable "enable_autoscaling" {
+  description = "If set to true, scale the service on average CPU utilization"
+  type        = bool
+  default     = false
+}
+
+variable "min_capacity" {
+  description = "The fewest tasks autoscaling may scale in to"
+  type        = number
+  default     = 1
+}
+
+variable "max_capacity" {
+  description = "The most tasks autoscaling may scale out to"
+  type        = number
+  default     = 4
+}
+
+variable "target_cpu_utilization" {
+  description = "The average CPU utilization percentage autoscaling keeps the service near"
+  type        = number
+  default     = 70
+}
+
+variable "scale_out_cooldown" {
+  description = "Seconds after a scale-out before autoscaling may scale out again"
+  type        = number
+  default     = 60
+}
+
+variable "scale_in_cooldown" {
+  description = "Seconds after a scale-in before autoscaling may scale in again"
+  type        = number
+  default     = 300
+}
+
+variable "routed_path_patterns" {
+  description = "The path patterns the ALB forwards to the service; anything else gets default_fixed_response"
+  type        = list(string)
+  default     = ["*"]
+}
+
+variable "default_fixed_response" {
+  description = "The response the ALB returns for requests that match none of the routed_path_patterns"
+  type = object({
+    content_type = string
+    message_body = string
+    status_code  = number
+  })
+  default = {
+    content_type = "text/plain"
+    message_body = "404: page not found"
+    status_code  = 404
+  }
+}
+
+variable "health_check_path" {
+  description = "The path the ALB requests to check each target's health"
+  type        = string
+  default     = "/"
+}
+
+variable "health_check_matcher" {
+  description = "The response codes that count as a healthy target"
+  type        = string
+  default     = "200"
+}
+
+variable "health_check_healthy_threshold" {
+  description = "The number of consecutive successful ALB health checks before a target counts as healthy"
+  type        = number
+  default     = 2
+}
+
+variable "health_check_command" {
+  description = "The command ECS runs inside the app container to check its health. Leave empty for no container health check."
+  type        = list(string)
+  default     = []
+}
+
+variable "health_check_interval" {
+  description = "Seconds between container health checks"
+  type        = number
+  default     = 30
+}
+
+variable "health_check_timeout" {
+  description = "Seconds a container health check may take before it counts as a failure"
+  type        = number
+  default     = 5
+}
+
+variable "health_check_retries" {
+  description = "Consecutive failed container health checks before the container is marked unhealthy"
+  type        = number
+  default     = 3
+}
+
+variable "health_check_start_period" {
+  description = "Seconds after the container starts during which failed health checks don't count"
+  type        = number
+  default     = 0
+}
+
+variable "enable_access_logs" {
+  description = "If true, create a bucket and write the ALB's access logs to it"
+  type        = bool
+  default     = false
+}
+
+variable "access_logs_prefix" {
+  description = "Key prefix for the ALB access logs in the bucket"
+  type        = string
+  default     = "alb"
+}
+
+variable "enable_https" {
+  description = "If true and certificate_arn is set, serve HTTPS on 443 and redirect HTTP to it"
+  type        = bool
+  default     = false
+}
+
+variable "certificate_arn" {
+  description = "The ARN of the ACM certificate for the HTTPS listener"
+  type        = string
+  default     = null
+}
+
+variable "secrets" {
+  description = "Map of environment variable name to Secrets Manager secret ARN to inject into the app container"
+  type        = map(string)
+  default     = {}
+}
+
+variable "container_command" {
+  description = "If set, run this command in the app container instead of the web server, e.g. to exercise a failure mode. The container is limited to 512 MB."
+  type        = list(string)
+  default     = null
+}
+
+variable "create_dashboard" {
+  description = "If true, create a CloudWatch dashboard for the service"
+  type        = bool
+  default     = false
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
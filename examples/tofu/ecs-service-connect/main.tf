@@ -0,0 +1,124 @@
+terraform {
+  required_version = ">= 1.1"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE THE SHARED SERVICE CONNECT NAMESPACE
+# Created here rather than in either service so both can join it without depending on each other.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_service_discovery_http_namespace" "service_connect" {
+  name = var.name
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# DEPLOY THE BACKEND SERVICE
+# Reachable by other services in the namespace at http://backend:5000
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "backend" {
+  source = "../../../modules/ecs-fargate-service"
+
+  name = "${var.name}-be"
+
+  # Run the training/webapp Docker image from Docker Hub, a simple "Hello, World" web server
+  container_definitions = jsonencode([{
+    name      = "${var.name}-be"
+    image     = "training/webapp"
+    essential = true
+    memory    = local.memory
+
+    portMappings = [
+      {
+        name          = "http"
+        containerPort = local.container_port
+        appProtocol   = "http"
+      }
+    ]
+
+    Environment = [
+      {
+        name  = "PROVIDER"
+        value = "World"
+      }
+    ]
+  }])
+
+  desired_count  = 1
+  cpu            = 256
+  memory         = local.memory
+  container_port = local.container_port
+  alb_port       = 80
+
+  enable_service_connect        = true
+  service_connect_namespace_arn = aws_service_discovery_http_namespace.service_connect.arn
+  service_connect_port_name     = "http"
+  service_connect_dns_name      = local.backend_dns_name
+  service_connect_client_sg_ids = [module.frontend.service_security_group_id]
+
+  # The image used for this example only supports X86_64.
+  cpu_architecture = "X86_64"
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# DEPLOY THE FRONTEND SERVICE
+# An nginx proxy that forwards every request to the backend via its Service Connect alias
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "frontend" {
+  source = "../../../modules/ecs-fargate-service"
+
+  name = "${var.name}-fe"
+
+  container_definitions = jsonencode([{
+    name       = "${var.name}-fe"
+    image      = "nginx:alpine"
+    essential  = true
+    memory     = local.memory
+    entryPoint = ["sh", "-c"]
+    command = [
+      "echo 'server { listen 80; location / { proxy_pass http://${local.backend_dns_name}:${local.container_port}; } }' > /etc/nginx/conf.d/default.conf && exec nginx -g 'daemon off;'"
+    ]
+
+    portMappings = [
+      {
+        containerPort = 80
+      }
+    ]
+  }])
+
+  desired_count  = 1
+  cpu            = 256
+  memory         = local.memory
+  container_port = 80
+  alb_port       = 80
+
+  # Join the namespace as a client only
+  enable_service_connect        = true
+  service_connect_namespace_arn = aws_service_discovery_http_namespace.service_connect.arn
+
+  cpu_architecture = "X86_64"
+}
+
+locals {
+  backend_dns_name = "backend"
+  container_port   = 5000
+  memory           = 512
+}
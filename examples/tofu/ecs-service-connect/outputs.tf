@@ -0,0 +1,27 @@
+output "frontend_url" {
+  value = module.frontend.url
+}
+
+output "backend_url" {
+  value = module.backend.url
+}
+
+output "backend_cluster_name" {
+  value = module.backend.ecs_cluster_name
+}
+
+output "backend_service_name" {
+  value = module.backend.ecs_service_name
+}
+
+output "frontend_cluster_name" {
+  value = module.frontend.ecs_cluster_name
+}
+
+output "frontend_service_name" {
+  value = module.frontend.ecs_service_name
+}
+
+output "namespace_arn" {
+  value = aws_service_discovery_http_namespace.service_connect.arn
+}
@@ -0,0 +1,27 @@
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "name" {
+  description = "The name prefix for the namespace and both services"
+  type        = string
+  default     = "ecs-service-connect"
+}
+
+variable "aws_region" {
+  description = "The AWS region to deploy into"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
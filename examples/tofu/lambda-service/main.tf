@@ -11,6 +11,12 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
 }
 
 data "archive_file" "source_code" {
@@ -11,6 +11,12 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -37,27 +43,44 @@ data "aws_subnets" "default" {
 module "postgresql" {
   source = "../../../modules/postgresql"
 
-  name              = var.name
-  db_name           = var.db_name
-  master_username   = var.master_username
-  master_password   = var.master_password
-  instance_class    = var.instance_class
-  allocated_storage = var.allocated_storage
+  name                  = var.name
+  db_name               = var.db_name
+  master_username       = var.master_username
+  master_password       = var.master_password
+  instance_class        = var.instance_class
+  allocated_storage     = var.allocated_storage
+  max_allocated_storage = var.max_allocated_storage
+  engine_version        = var.engine_version
+
+  enable_logical_replication = var.enable_logical_replication
+  additional_databases       = var.additional_databases
+  statement_timeout          = var.statement_timeout
+
+  storage_encrypted = var.storage_encrypted
+  kms_key_arn       = var.kms_key_arn
+
+  manage_master_user_password         = var.manage_master_user_password
+  iam_database_authentication_enabled = var.iam_database_authentication_enabled
 
   vpc_id     = data.aws_vpc.default.id
-  subnet_ids = data.aws_subnets.default.ids
+  subnet_ids = var.subnet_ids != null ? var.subnet_ids : data.aws_subnets.default.ids
 
   # Use minimal settings for testing
-  multi_az                     = var.multi_az
-  backup_retention_period      = 0
-  deletion_protection          = false
-  skip_final_snapshot          = true
-  performance_insights_enabled = false
+  multi_az                = var.multi_az
+  backup_retention_period = var.backup_retention_period
+  read_replica_count      = var.read_replica_count
+  deletion_protection     = var.deletion_protection
+  skip_final_snapshot     = true
+
+  enabled_cloudwatch_logs_exports = var.enabled_cloudwatch_logs_exports
+
+  performance_insights_enabled          = var.performance_insights_enabled
+  performance_insights_retention_period = var.performance_insights_retention_period
 
   environment = "test"
 
-  tags = {
+  tags = merge({
     Environment = "test"
     ManagedBy   = "Terratest"
-  }
+  }, var.tags)
 }
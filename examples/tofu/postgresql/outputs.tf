@@ -13,6 +13,16 @@ output "port" {
   value       = module.postgresql.port
 }
 
+output "allocated_storage" {
+  description = "The storage (GB) allocated to the instance"
+  value       = module.postgresql.allocated_storage
+}
+
+output "max_allocated_storage" {
+  description = "The limit (GB) RDS can automatically scale storage to, or 0 when disabled"
+  value       = module.postgresql.max_allocated_storage
+}
+
 output "db_name" {
   description = "The name of the database"
   value       = module.postgresql.db_name
@@ -23,6 +33,16 @@ output "arn" {
   value       = module.postgresql.arn
 }
 
+output "replica_endpoints" {
+  description = "The connection endpoints (hostname:port) of the read replicas"
+  value       = module.postgresql.replica_endpoints
+}
+
+output "kms_key_id" {
+  description = "The ARN of the KMS key encrypting the instance's storage, or null when unencrypted"
+  value       = module.postgresql.kms_key_id
+}
+
 output "db_security_group_id" {
   description = "The ID of the security group"
   value       = module.postgresql.db_security_group_id
@@ -33,3 +53,28 @@ output "connection_string" {
   value       = module.postgresql.connection_string
   sensitive   = true
 }
+
+output "additional_databases" {
+  description = "The extra databases to create on the instance"
+  value       = module.postgresql.additional_databases
+}
+
+output "iam_auth_enabled" {
+  description = "Whether IAM database authentication is enabled"
+  value       = module.postgresql.iam_auth_enabled
+}
+
+output "cloudwatch_log_group_names" {
+  description = "The CloudWatch log group for each exported log type"
+  value       = module.postgresql.cloudwatch_log_group_names
+}
+
+output "performance_insights_enabled" {
+  description = "Whether Performance Insights is enabled"
+  value       = module.postgresql.performance_insights_enabled
+}
+
+output "master_user_secret_arn" {
+  description = "The ARN of the Secrets Manager secret holding the master user's credentials"
+  value       = module.postgresql.master_user_secret_arn
+}
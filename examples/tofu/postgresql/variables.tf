@@ -16,9 +16,22 @@ variable "master_username" {
 }
 
 variable "master_password" {
-  description = "The password for the master user"
+  description = "The password for the master user. Leave null when manage_master_user_password is true."
   type        = string
   sensitive   = true
+  default     = null
+}
+
+variable "manage_master_user_password" {
+  description = "Let RDS generate and rotate the master password in Secrets Manager"
+  type        = bool
+  default     = false
+}
+
+variable "iam_database_authentication_enabled" {
+  description = "Let users granted rds_iam log in with IAM auth tokens"
+  type        = bool
+  default     = false
 }
 
 variable "aws_region" {
@@ -39,8 +52,113 @@ variable "allocated_storage" {
   default     = 20
 }
 
+variable "max_allocated_storage" {
+  description = "The limit (GB) RDS can automatically scale storage to. Must be greater than allocated_storage, or 0 to disable."
+  type        = number
+  default     = 0
+}
+
+variable "storage_encrypted" {
+  description = "Whether to encrypt the instance's storage. Changing it replaces the instance."
+  type        = bool
+  default     = true
+}
+
+variable "kms_key_arn" {
+  description = "The ARN of a customer managed KMS key to encrypt with. If null, the AWS managed aws/rds key is used."
+  type        = string
+  default     = null
+}
+
 variable "multi_az" {
   description = "Whether Multi-AZ is enabled"
   type        = bool
   default     = false
 }
+
+variable "subnet_ids" {
+  description = "Subnet IDs to deploy into. If null, all subnets of the default VPC are used."
+  type        = list(string)
+  default     = null
+}
+
+variable "engine_version" {
+  description = "The PostgreSQL engine version"
+  type        = string
+  default     = "15.10"
+}
+
+variable "backup_retention_period" {
+  description = "Days to retain automated backups. 0 disables backups."
+  type        = number
+  default     = 0
+}
+
+variable "deletion_protection" {
+  description = "Whether the instance is protected from deletion. Off by default so tests can destroy it."
+  type        = bool
+  default     = false
+}
+
+variable "read_replica_count" {
+  description = "The number of read replicas to create. Requires backup_retention_period > 0."
+  type        = number
+  default     = 0
+}
+
+variable "enabled_cloudwatch_logs_exports" {
+  description = "The log types to export to CloudWatch Logs, e.g. [\"postgresql\", \"upgrade\"]"
+  type        = list(string)
+  default     = ["postgresql", "upgrade"]
+}
+
+variable "performance_insights_enabled" {
+  description = "Enable Performance Insights. Not supported on every instance class, e.g. db.t3.micro."
+  type        = bool
+  default     = false
+}
+
+variable "performance_insights_retention_period" {
+  description = "Days to retain Performance Insights data"
+  type        = number
+  default     = 7
+}
+
+variable "statement_timeout" {
+  description = "Cancel statements running longer than this many milliseconds. 0 disables the timeout."
+  type        = string
+  default     = "0"
+}
+
+variable "enable_logical_replication" {
+  description = "Set rds.logical_replication = 1, as required by RDS blue/green deployments"
+  type        = bool
+  default     = false
+}
+
+variable "additional_databases" {
+  description = "Extra databases to create on the instance, with an optional owner role for each"
+  type = list(object({
+    name  = string
+    owner = string
+  }))
+  default = []
+}
+
+variable "tags" {
+  description = "Extra tags to add to every resource"
+  type        = map(string)
+  default     = {}
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
@@ -11,6 +11,12 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -37,28 +43,44 @@ data "aws_subnets" "default" {
 module "redis" {
   source = "../../../modules/redis"
 
-  name       = var.name
-  node_type  = var.node_type
-  vpc_id     = data.aws_vpc.default.id
-  subnet_ids = data.aws_subnets.default.ids
+  name           = var.name
+  engine         = var.engine
+  engine_version = var.engine_version
+  node_type      = var.node_type
+  vpc_id         = data.aws_vpc.default.id
+  subnet_ids     = var.subnet_ids != null ? var.subnet_ids : data.aws_subnets.default.ids
 
   # Use minimal settings for testing
   num_cache_clusters         = var.num_cache_nodes
   automatic_failover_enabled = var.automatic_failover
   multi_az_enabled           = var.multi_az
+  appendonly                 = var.appendonly
+
+  parameter_group_overrides = var.parameter_group_overrides
+
+  cluster_mode_enabled    = var.cluster_mode_enabled
+  num_node_groups         = var.num_node_groups
+  replicas_per_node_group = var.replicas_per_node_group
 
   # Disable auth for simpler testing (enable in production)
   auth_token_enabled         = var.auth_token_enabled
-  transit_encryption_enabled = false
+  auth_token_secret_arn      = var.auth_token_secret_arn
+  transit_encryption_enabled = var.transit_encryption_enabled
   at_rest_encryption_enabled = true
 
-  # Testing: allow deletion without final snapshot
-  snapshot_retention_limit = 0
+  # Testing: backups are off unless a test is checking them
+  snapshot_retention_limit = var.snapshot_retention_limit
+  snapshot_window          = var.snapshot_window
+
+  enable_alarms = var.enable_alarms
+
+  # Testing: don't wait for the maintenance window to apply upgrades, unless a test is checking deferred changes
+  apply_immediately = var.apply_immediately
 
   environment = "test"
 
-  tags = {
+  tags = merge({
     Environment = "test"
     ManagedBy   = "Terratest"
-  }
+  }, var.tags)
 }
@@ -3,6 +3,26 @@ output "primary_endpoint_address" {
   value       = module.redis.primary_endpoint_address
 }
 
+output "reader_endpoint_address" {
+  description = "The address of the reader endpoint; empty for a single node"
+  value       = module.redis.reader_endpoint_address
+}
+
+output "configuration_endpoint_address" {
+  description = "The address of the configuration endpoint, in cluster mode"
+  value       = module.redis.configuration_endpoint_address
+}
+
+output "engine" {
+  description = "The cache engine running on the cluster"
+  value       = module.redis.engine
+}
+
+output "persistence_mode" {
+  description = "How writes are made durable (replicated or snapshot)"
+  value       = module.redis.persistence_mode
+}
+
 output "port" {
   description = "The port number on which Redis accepts connections"
   value       = module.redis.port
@@ -13,11 +33,26 @@ output "arn" {
   value       = module.redis.arn
 }
 
+output "tls_enabled" {
+  description = "Whether clients must connect with TLS"
+  value       = module.redis.tls_enabled
+}
+
+output "auth_token_secret_arn" {
+  description = "The ARN of the Secrets Manager secret holding the AUTH token"
+  value       = module.redis.auth_token_secret_arn
+}
+
 output "redis_security_group_id" {
   description = "The ID of the security group attached to the Redis cluster"
   value       = module.redis.redis_security_group_id
 }
 
+output "parameter_group_name" {
+  description = "The name of the parameter group the cluster uses"
+  value       = module.redis.parameter_group_name
+}
+
 output "redis_url" {
   description = "Redis connection URL for Django CACHES"
   value       = module.redis.redis_url
@@ -27,3 +62,18 @@ output "celery_broker_url" {
   description = "Redis connection URL for Celery broker"
   value       = module.redis.celery_broker_url
 }
+
+output "cpu_alarm_arn" {
+  description = "The ARN of the engine CPU alarm, when enable_alarms is true"
+  value       = module.redis.cpu_alarm_arn
+}
+
+output "memory_alarm_arn" {
+  description = "The ARN of the memory usage alarm, when enable_alarms is true"
+  value       = module.redis.memory_alarm_arn
+}
+
+output "eviction_alarm_arn" {
+  description = "The ARN of the evictions alarm, when enable_alarms is true"
+  value       = module.redis.eviction_alarm_arn
+}
@@ -16,7 +16,7 @@ variable "node_type" {
 }
 
 variable "num_cache_nodes" {
-  description = "Number of cache nodes"
+  description = "Total number of cache nodes including the primary, passed to the module's num_cache_clusters (e.g. 3 = 1 primary + 2 replicas)"
   type        = number
   default     = 1
 }
@@ -33,8 +33,110 @@ variable "multi_az" {
   default     = false
 }
 
+variable "cluster_mode_enabled" {
+  description = "Whether to shard the keyspace across num_node_groups node groups"
+  type        = bool
+  default     = false
+}
+
+variable "num_node_groups" {
+  description = "The number of shards when cluster_mode_enabled is true"
+  type        = number
+  default     = 2
+}
+
+variable "replicas_per_node_group" {
+  description = "The number of replicas in each shard when cluster_mode_enabled is true"
+  type        = number
+  default     = 1
+}
+
 variable "auth_token_enabled" {
   description = "Whether to enable Redis AUTH token"
   type        = bool
   default     = false
 }
+
+variable "auth_token_secret_arn" {
+  description = "The ARN of a Secrets Manager secret holding the AUTH token. Required when auth_token_enabled is true."
+  type        = string
+  default     = null
+}
+
+variable "transit_encryption_enabled" {
+  description = "Whether clients must connect with TLS"
+  type        = bool
+  default     = false
+}
+
+variable "engine" {
+  description = "The cache engine to run: redis or valkey"
+  type        = string
+  default     = "redis"
+}
+
+variable "engine_version" {
+  description = "The engine version to run. If null, the module picks a default for the engine."
+  type        = string
+  default     = null
+}
+
+variable "subnet_ids" {
+  description = "Subnet IDs to deploy into. If null, all subnets of the default VPC are used."
+  type        = list(string)
+  default     = null
+}
+
+variable "appendonly" {
+  description = "Whether to require replicated (AOF-equivalent) durability"
+  type        = bool
+  default     = false
+}
+
+variable "enable_alarms" {
+  description = "Whether to create the CPU, memory, and eviction alarms"
+  type        = bool
+  default     = false
+}
+
+variable "parameter_group_overrides" {
+  description = "Parameters to set in the module's parameter group, e.g. { \"maxmemory-policy\" = \"allkeys-lru\" }"
+  type        = map(string)
+  default     = {}
+}
+
+variable "snapshot_retention_limit" {
+  description = "The number of days to keep automatic snapshots. 0 disables backups."
+  type        = number
+  default     = 0
+}
+
+variable "snapshot_window" {
+  description = "The daily UTC window for automatic snapshots, e.g. 03:00-04:00. Must not overlap the maintenance window."
+  type        = string
+  default     = "03:00-04:00"
+}
+
+variable "apply_immediately" {
+  description = "Whether to apply changes now rather than in the maintenance window. On by default so tests don't wait for the window."
+  type        = bool
+  default     = true
+}
+
+variable "tags" {
+  description = "Extra tags to add to every resource"
+  type        = map(string)
+  default     = {}
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
@@ -11,12 +11,24 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
+}
+
+# Bucket names are global, so outside the default workspace the workspace is appended to the name. That lets dev and
+# staging workspaces of this configuration, with the same variables, deploy side by side.
+locals {
+  name = terraform.workspace == "default" ? var.name : "${var.name}-${terraform.workspace}"
 }
 
 module "s3_bucket" {
   source = "../../../modules/s3-bucket"
 
-  name = var.name
+  name = local.name
 
   # Do NOT copy this into product code. We only set this param to true here so that the automated tests can clean up.
   force_destroy = true
@@ -3,7 +3,7 @@
 # ---------------------------------------------------------------------------------------------------------------------
 
 variable "name" {
-  description = "The name of the S3 bucket"
+  description = "The name of the S3 bucket. In a workspace other than default, the workspace name is appended."
   type        = string
 }
 
@@ -16,3 +16,15 @@ variable "aws_region" {
   type        = string
   default     = "us-east-1"
 }
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
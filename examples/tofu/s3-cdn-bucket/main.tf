@@ -11,16 +11,25 @@ terraform {
 
 provider "aws" {
   region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
 # S3 CDN BUCKET EXAMPLE
 # ---------------------------------------------------------------------------------------------------------------------
 # This example creates an S3 bucket configured for CDN use with:
-# - Public read access (HTTPS only)
+# - Public read access (HTTPS only), or a CloudFront distribution reading the private bucket through Origin Access
+#   Control
 # - Static website hosting
 # - CORS configuration
 # - Server-side encryption
+# - Optional SQS notification on object creation
+# - Optional transfer acceleration for large uploads
 # ---------------------------------------------------------------------------------------------------------------------
 
 module "s3_cdn_bucket" {
@@ -28,17 +37,35 @@ module "s3_cdn_bucket" {
 
   name = var.name
 
-  # CDN configuration
-  block_public_access    = false
-  enable_public_read     = true
+  # CDN configuration. With CloudFront the bucket stays private and only the distribution can read it.
+  block_public_access    = var.enable_cloudfront
+  enable_public_read     = !var.enable_cloudfront
+  enable_cloudfront      = var.enable_cloudfront
   enable_website_hosting = true
   enable_cors            = true
 
+  # Deny plain HTTP requests. This also makes the HTTP-only website endpoint return 403s, so a CDN should fetch from
+  # bucket_regional_domain_name over HTTPS.
+  require_https = true
+
+  # Serve objects through the bucket policy only; uploads that try to set an ACL are rejected
+  object_ownership = "BucketOwnerEnforced"
+
   # Restrict CORS to test origins
   cors_allowed_origins = var.cors_allowed_origins
 
-  # CDN assets don't need versioning (use filename versioning instead)
-  enable_versioning = false
+  # CDN assets usually don't need versioning (use filename versioning instead)
+  enable_versioning = var.enable_versioning
+
+  # e.g. expire temporary uploads or move old assets to cheaper storage
+  lifecycle_rules = var.lifecycle_rules
+
+  # Large assets (video, bundles) can be uploaded through the accelerated endpoint
+  enable_transfer_acceleration = var.enable_transfer_acceleration
+
+  # Notify the test queue on upload (e.g., to drive image resizing)
+  notification_target_arn    = var.enable_event_notification ? aws_sqs_queue.notifications[0].arn : null
+  notification_filter_prefix = var.notification_filter_prefix
 
   # Do NOT copy this into product code. We only set this param to true here so that the automated tests can clean up.
   force_destroy = true
@@ -47,4 +74,42 @@ module "s3_cdn_bucket" {
     Environment = "test"
     Purpose     = "cdn-test"
   }
+
+  # The queue policy must exist before S3 validates the notification configuration
+  depends_on = [aws_sqs_queue_policy.notifications]
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# NOTIFICATION QUEUE
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_sqs_queue" "notifications" {
+  count = var.enable_event_notification ? 1 : 0
+  name  = "${var.name}-events"
+
+  tags = {
+    Environment = "test"
+    Purpose     = "cdn-test"
+  }
+}
+
+resource "aws_sqs_queue_policy" "notifications" {
+  count     = var.enable_event_notification ? 1 : 0
+  queue_url = aws_sqs_queue.notifications[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid       = "AllowS3Notifications"
+        Effect    = "Allow"
+        Principal = { Service = "s3.amazonaws.com" }
+        Action    = "sqs:SendMessage"
+        Resource  = aws_sqs_queue.notifications[0].arn
+        Condition = {
+          ArnLike = { "aws:SourceArn" = "arn:aws:s3:::${var.name}" }
+        }
+      }
+    ]
+  })
 }
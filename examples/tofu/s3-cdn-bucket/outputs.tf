@@ -8,6 +8,11 @@ output "bucket_arn" {
   value       = module.s3_cdn_bucket.arn
 }
 
+output "versioning_status" {
+  description = "The versioning status of the bucket"
+  value       = module.s3_cdn_bucket.versioning_status
+}
+
 output "website_endpoint" {
   description = "The website endpoint for the bucket"
   value       = module.s3_cdn_bucket.website_endpoint
@@ -22,3 +27,33 @@ output "bucket_regional_domain_name" {
   description = "The regional domain name of the bucket"
   value       = module.s3_cdn_bucket.bucket_regional_domain_name
 }
+
+output "notification_queue_url" {
+  description = "The URL of the SQS queue receiving bucket notifications (if enabled)"
+  value       = try(aws_sqs_queue.notifications[0].url, null)
+}
+
+output "notification_queue_arn" {
+  description = "The ARN of the SQS queue receiving bucket notifications (if enabled)"
+  value       = try(aws_sqs_queue.notifications[0].arn, null)
+}
+
+output "transfer_acceleration_endpoint" {
+  description = "The accelerated endpoint of the bucket (if transfer acceleration is enabled)"
+  value       = module.s3_cdn_bucket.transfer_acceleration_endpoint
+}
+
+output "cloudfront_domain_name" {
+  description = "The domain name of the CloudFront distribution (if enabled)"
+  value       = module.s3_cdn_bucket.cloudfront_domain_name
+}
+
+output "cloudfront_distribution_id" {
+  description = "The ID of the CloudFront distribution (if enabled)"
+  value       = module.s3_cdn_bucket.cloudfront_distribution_id
+}
+
+output "lifecycle_rule_count" {
+  description = "The number of lifecycle rules configured on the bucket"
+  value       = module.s3_cdn_bucket.lifecycle_rule_count
+}
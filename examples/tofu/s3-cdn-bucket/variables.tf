@@ -14,3 +14,60 @@ variable "cors_allowed_origins" {
   type        = list(string)
   default     = ["https://example.com"]
 }
+
+variable "enable_event_notification" {
+  description = "Create an SQS queue and notify it when objects are created in the bucket"
+  type        = bool
+  default     = false
+}
+
+variable "notification_filter_prefix" {
+  description = "Only notify for object keys starting with this prefix"
+  type        = string
+  default     = null
+}
+
+variable "enable_transfer_acceleration" {
+  description = "Enable S3 Transfer Acceleration on the bucket"
+  type        = bool
+  default     = false
+}
+
+variable "enable_cloudfront" {
+  description = "Serve the bucket through a CloudFront distribution and keep the bucket private"
+  type        = bool
+  default     = false
+}
+
+variable "enable_versioning" {
+  description = "Keep every version of each object. If false, versioning is suspended and overwrites replace the object."
+  type        = bool
+  default     = false
+}
+
+variable "lifecycle_rules" {
+  description = "Lifecycle rules for objects under a key prefix"
+  type = list(object({
+    id                                 = string
+    prefix                             = optional(string, "")
+    expiration_days                    = optional(number)
+    noncurrent_version_expiration_days = optional(number)
+    transitions = optional(list(object({
+      days          = number
+      storage_class = string
+    })), [])
+  }))
+  default = []
+}
+
+variable "terratest_run_id" {
+  description = "Set by automated tests to tag every resource with TerratestRunID for cleanup. Leave null otherwise."
+  type        = string
+  default     = null
+}
+
+variable "default_tags" {
+  description = "Tags the provider adds to every resource, e.g. org-wide cost allocation tags. Module tags override them on key conflicts."
+  type        = map(string)
+  default     = {}
+}
@@ -0,0 +1,89 @@
+terraform {
+  required_version = ">= 1.1"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.aws_region
+
+  # Tag everything with default_tags, plus the test run ID so leftovers from crashed test runs can be swept. Tags set
+  # on a resource override these on key conflicts.
+  default_tags {
+    tags = merge(var.default_tags, var.terratest_run_id != null ? { TerratestRunID = var.terratest_run_id } : {})
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A PRIVATE VPC WITH NO NAT GATEWAY
+# The default VPC's subnets are public, so this example creates its own VPC with private subnets and no internet or NAT
+# gateway. The VPC endpoints are the only way for workloads in it to reach AWS services.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_availability_zones" "available" {
+  state = "available"
+}
+
+resource "aws_vpc" "private" {
+  cidr_block           = var.vpc_cidr
+  enable_dns_support   = true
+  enable_dns_hostnames = true
+
+  tags = {
+    Name = var.name
+  }
+}
+
+resource "aws_subnet" "private" {
+  count = 2
+
+  vpc_id            = aws_vpc.private.id
+  cidr_block        = cidrsubnet(var.vpc_cidr, 8, count.index)
+  availability_zone = data.aws_availability_zones.available.names[count.index]
+
+  tags = {
+    Name = "${var.name}-private-${count.index}"
+  }
+}
+
+resource "aws_route_table" "private" {
+  vpc_id = aws_vpc.private.id
+
+  tags = {
+    Name = "${var.name}-private"
+  }
+}
+
+resource "aws_route_table_association" "private" {
+  count = length(aws_subnet.private)
+
+  subnet_id      = aws_subnet.private[count.index].id
+  route_table_id = aws_route_table.private.id
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# DEPLOY THE VPC ENDPOINTS
+# ---------------------------------------------------------------------------------------------------------------------
+
+module "vpc_endpoints" {
+  source = "../../../modules/vpc-endpoints"
+
+  name                    = var.name
+  vpc_id                  = aws_vpc.private.id
+  vpc_cidr                = aws_vpc.private.cidr_block
+  private_subnet_ids      = aws_subnet.private[*].id
+  private_route_table_ids = [aws_route_table.private.id]
+  aws_region              = var.aws_region
+
+  environment = "test"
+
+  tags = {
+    Environment = "test"
+    ManagedBy   = "Terratest"
+  }
+}
@@ -0,0 +1,19 @@
+output "vpc_id" {
+  description = "The ID of the private VPC"
+  value       = aws_vpc.private.id
+}
+
+output "private_subnet_ids" {
+  description = "The IDs of the private subnets"
+  value       = aws_subnet.private[*].id
+}
+
+output "vpc_endpoints_security_group_id" {
+  description = "The ID of the security group attached to the interface endpoints"
+  value       = module.vpc_endpoints.vpc_endpoints_security_group_id
+}
+
+output "s3_endpoint_id" {
+  description = "The ID of the S3 gateway endpoint"
+  value       = module.vpc_endpoints.s3_endpoint_id
+}
@@ -12,4 +12,12 @@ data "aws_subnet" "public" {
   id       = each.value
 }
 
-data "aws_region" "current" {}
+# The postcondition fails the plan if the provider points at a region outside allowed_regions
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
+}
@@ -36,6 +36,47 @@ resource "aws_cloudwatch_log_group" "django" {
   }
 }
 
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE AN ERROR RATE METRIC FILTER AND ALARM
+# Django logs every 5xx response (and any unhandled exception) at ERROR level, so counting ERROR lines in the log group
+# gives an error rate without any application-side metrics code.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_log_metric_filter" "error_rate" {
+  count          = var.create_error_rate_alarm ? 1 : 0
+  name           = "${var.name}-errors"
+  log_group_name = aws_cloudwatch_log_group.django.name
+  pattern        = var.error_rate_filter_pattern
+
+  metric_transformation {
+    name          = "${var.name}-ErrorCount"
+    namespace     = var.error_rate_metric_namespace
+    value         = "1"
+    default_value = "0"
+  }
+}
+
+resource "aws_cloudwatch_metric_alarm" "error_rate" {
+  count               = var.create_error_rate_alarm ? 1 : 0
+  alarm_name          = "${var.name}-error-rate"
+  alarm_description   = "More than ${var.error_rate_alarm_threshold} ERROR log lines in ${var.error_rate_alarm_period} seconds for ${var.name}"
+  namespace           = var.error_rate_metric_namespace
+  metric_name         = aws_cloudwatch_log_metric_filter.error_rate[0].metric_transformation[0].name
+  statistic           = "Sum"
+  period              = var.error_rate_alarm_period
+  evaluation_periods  = 1
+  threshold           = var.error_rate_alarm_threshold
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = var.alarm_actions
+  ok_actions          = var.alarm_actions
+
+  tags = {
+    Name        = "${var.name}-error-rate"
+    Environment = var.environment
+  }
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE THE ECS SERVICE
 # ---------------------------------------------------------------------------------------------------------------------
@@ -47,6 +88,12 @@ resource "aws_ecs_service" "service" {
   launch_type     = "FARGATE"
   task_definition = aws_ecs_task_definition.service.arn
 
+  # Allow `aws ecs execute-command` into running tasks for debugging and in-VPC connectivity checks
+  enable_execute_command = var.enable_execute_command
+
+  # The container runs migrations before Gunicorn listens, so health checks fail until they finish
+  health_check_grace_period_seconds = var.health_check_grace_period_seconds
+
   load_balancer {
     container_name   = var.name
     container_port   = var.container_port
@@ -74,16 +121,31 @@ resource "aws_ecs_service" "service" {
 # ---------------------------------------------------------------------------------------------------------------------
 
 locals {
+  # Gunicorn sees the host's CPUs rather than the task's share, so derive the worker count from the task CPU allocation
+  # using the usual 2 * vCPU + 1 rule (256 CPU units = 1 worker, 1024 = 3, 2048 = 5)
+  gunicorn_workers = var.gunicorn_workers != null ? var.gunicorn_workers : floor(2 * var.cpu / 1024) + 1
+
   # Construct Django environment variables
   django_env_vars = merge(
     {
-      DJANGO_SETTINGS_MODULE = var.django_settings_module
-      DJANGO_ALLOWED_HOSTS   = var.django_allowed_hosts
-      DATABASE_URL           = var.database_url
-      DEBUG                  = tostring(var.debug)
-      ENVIRONMENT            = var.environment
-      AWS_REGION             = var.aws_region
-      AWS_DEFAULT_REGION     = var.aws_region
+      DJANGO_SETTINGS_MODULE      = var.django_settings_module
+      DJANGO_ALLOWED_HOSTS        = var.django_allowed_hosts
+      DATABASE_URL                = var.database_url
+      DEBUG                       = tostring(var.debug)
+      ENVIRONMENT                 = var.environment
+      AWS_REGION                  = var.aws_region
+      AWS_DEFAULT_REGION          = var.aws_region
+      FEATURE_FLAGS               = jsonencode(var.feature_flags)
+      GUNICORN_WORKERS            = tostring(local.gunicorn_workers)
+      GUNICORN_TIMEOUT            = tostring(var.request_timeout)
+      GUNICORN_THREADS            = tostring(var.gunicorn_threads)
+      DATA_UPLOAD_MAX_MEMORY_SIZE = tostring(var.max_request_body_size)
+      DB_POOL_SIZE                = tostring(coalesce(var.db_pool_size, 0))
+      DB_POOL_TIMEOUT             = tostring(var.db_pool_timeout)
+      DB_CONN_MAX_AGE             = tostring(var.db_conn_max_age)
+      CACHE_CONTROL_RULES         = jsonencode(var.cache_control_rules)
+      METRICS_ENABLED             = tostring(var.metrics_enabled)
+      METRICS_PATH                = var.metrics_path
     },
     var.redis_url != null ? {
       REDIS_URL         = var.redis_url
@@ -117,6 +179,9 @@ locals {
       memory    = var.memory
       essential = true
 
+      # Time between SIGTERM and SIGKILL, so workers can finish in-flight tasks during a rolling deploy
+      stopTimeout = var.stop_timeout
+
       portMappings = [
         {
           containerPort = var.container_port
@@ -144,7 +209,7 @@ locals {
         interval    = 30
         timeout     = 5
         retries     = 3
-        startPeriod = 60
+        startPeriod = var.container_health_check_start_period
       }
     }
   ])
@@ -277,6 +342,29 @@ resource "aws_iam_role_policy" "task_basic_permissions" {
   })
 }
 
+# ECS Exec opens an SSM session into the container, which requires these permissions on the task role
+resource "aws_iam_role_policy" "execute_command" {
+  count = var.task_role_arn == null && var.enable_execute_command ? 1 : 0
+  name  = "${var.name}-execute-command"
+  role  = aws_iam_role.ecs_task_role[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "ssmmessages:CreateControlChannel",
+          "ssmmessages:CreateDataChannel",
+          "ssmmessages:OpenControlChannel",
+          "ssmmessages:OpenDataChannel"
+        ]
+        Resource = "*"
+      }
+    ]
+  })
+}
+
 locals {
   task_role_arn = var.task_role_arn != null ? var.task_role_arn : aws_iam_role.ecs_task_role[0].arn
 }
@@ -290,6 +378,8 @@ module "service_sg" {
   source = "../sg"
   name   = "${var.name}-service"
   vpc_id = data.aws_vpc.selected.id
+
+  allowed_regions = var.allowed_regions
 }
 
 locals {
@@ -304,6 +394,8 @@ module "allow_outbound_all" {
   to_port           = 0
   protocol          = "-1"
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
 
 module "allow_inbound_on_container_port" {
@@ -312,6 +404,21 @@ module "allow_inbound_on_container_port" {
   from_port                = var.container_port
   to_port                  = var.container_port
   source_security_group_id = local.alb_sg_id
+
+  allowed_regions = var.allowed_regions
+}
+
+# Let metrics scrapers reach the tasks directly, since the ALB doesn't forward metrics_path
+module "allow_metrics_scrapers" {
+  for_each = var.metrics_enabled ? toset(var.metrics_allowed_sg_ids) : toset([])
+
+  source                   = "../sg-rule"
+  security_group_id        = local.service_sg_id
+  from_port                = var.container_port
+  to_port                  = var.container_port
+  source_security_group_id = each.value
+
+  allowed_regions = var.allowed_regions
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -330,6 +437,10 @@ resource "aws_lb" "ecs" {
   subnets            = local.subnets_for_alb
   security_groups    = [local.alb_sg_id]
 
+  # Keep connections open longer than Gunicorn's request timeout so slow requests are cut off by Gunicorn, which frees
+  # the worker, rather than by the ALB, which leaves the worker busy
+  idle_timeout = max(60, var.request_timeout + 5)
+
   tags = {
     Name        = var.name
     Environment = var.environment
@@ -383,6 +494,30 @@ resource "aws_lb_target_group" "ecs" {
   }
 }
 
+# Keep the metrics endpoint off the internet: requests for it get the same 404 as unknown paths, before forward_all
+resource "aws_lb_listener_rule" "block_metrics" {
+  count = var.metrics_enabled ? 1 : 0
+
+  listener_arn = aws_lb_listener.http.arn
+  priority     = 10
+
+  condition {
+    path_pattern {
+      values = ["${trimsuffix(var.metrics_path, "/")}*"]
+    }
+  }
+
+  action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      message_body = "404: page not found"
+      status_code  = 404
+    }
+  }
+}
+
 resource "aws_lb_listener_rule" "forward_all" {
   listener_arn = aws_lb_listener.http.arn
   priority     = 100
@@ -408,6 +543,8 @@ module "alb_sg" {
   source = "../sg"
   name   = "${var.name}-alb"
   vpc_id = data.aws_vpc.selected.id
+
+  allowed_regions = var.allowed_regions
 }
 
 locals {
@@ -420,6 +557,8 @@ module "alb_allow_http_inbound" {
   from_port         = var.alb_port
   to_port           = var.alb_port
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
 
 module "alb_allow_all_outbound" {
@@ -430,4 +569,6 @@ module "alb_allow_all_outbound" {
   to_port           = 0
   protocol          = "-1"
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
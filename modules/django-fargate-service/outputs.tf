@@ -62,3 +62,38 @@ output "cloudwatch_log_group_name" {
   description = "The name of the CloudWatch log group for Django logs"
   value       = aws_cloudwatch_log_group.django.name
 }
+
+output "gunicorn_workers" {
+  description = "The number of Gunicorn worker processes configured for each task"
+  value       = local.gunicorn_workers
+}
+
+output "request_timeout" {
+  description = "The number of seconds Gunicorn allows a request to run before killing the worker"
+  value       = var.request_timeout
+}
+
+output "db_pool_size" {
+  description = "The maximum number of requests per worker process that may hold a database connection at once (null if unlimited)"
+  value       = var.db_pool_size
+}
+
+output "error_rate_metric_filter_name" {
+  description = "The name of the error rate log metric filter (if enabled)"
+  value       = try(aws_cloudwatch_log_metric_filter.error_rate[0].name, null)
+}
+
+output "error_rate_metric_name" {
+  description = "The name of the error count metric (if enabled)"
+  value       = try(aws_cloudwatch_log_metric_filter.error_rate[0].metric_transformation[0].name, null)
+}
+
+output "error_rate_metric_namespace" {
+  description = "The CloudWatch namespace of the error count metric"
+  value       = var.error_rate_metric_namespace
+}
+
+output "error_rate_alarm_name" {
+  description = "The name of the error rate alarm (if enabled)"
+  value       = try(aws_cloudwatch_metric_alarm.error_rate[0].alarm_name, null)
+}
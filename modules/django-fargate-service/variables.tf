@@ -170,6 +170,23 @@ variable "health_check_unhealthy_threshold" {
   default     = 3
 }
 
+variable "health_check_grace_period_seconds" {
+  description = "Seconds after a task starts during which ECS ignores failing ALB and container health checks. Must cover migrations, collectstatic, and Gunicorn boot, or slow-starting tasks are killed and replaced in a loop."
+  type        = number
+  default     = 120
+}
+
+variable "container_health_check_start_period" {
+  description = "Seconds after the container starts before failing container health checks count against its retries. ECS allows at most 300."
+  type        = number
+  default     = 60
+
+  validation {
+    condition     = var.container_health_check_start_period >= 0 && var.container_health_check_start_period <= 300
+    error_message = "container_health_check_start_period must be between 0 and 300 seconds."
+  }
+}
+
 variable "log_retention_days" {
   description = "Number of days to retain CloudWatch logs"
   type        = number
@@ -182,8 +199,169 @@ variable "additional_environment_variables" {
   default     = {}
 }
 
+variable "feature_flags" {
+  description = "Map of runtime feature flags exposed to the Django app as the FEATURE_FLAGS environment variable (JSON encoded)"
+  type        = map(bool)
+  default     = {}
+}
+
+variable "gunicorn_workers" {
+  description = "Number of Gunicorn worker processes. If null, derived from cpu as 2 * vCPU + 1."
+  type        = number
+  default     = null
+}
+
+variable "request_timeout" {
+  description = "Seconds a Gunicorn worker may spend on a request before it is killed and restarted, so slow endpoints can't exhaust the worker pool"
+  type        = number
+  default     = 30
+
+  validation {
+    condition     = var.request_timeout > 0 && var.request_timeout <= 3600
+    error_message = "request_timeout must be between 1 and 3600 seconds."
+  }
+}
+
+variable "stop_timeout" {
+  description = "Seconds ECS waits after sending SIGTERM before killing the container. Celery workers finish their current task on SIGTERM, so this must cover the longest task or it is cut off mid-run on every deploy."
+  type        = number
+  default     = 30
+
+  validation {
+    condition     = var.stop_timeout >= 2 && var.stop_timeout <= 120
+    error_message = "stop_timeout must be between 2 and 120 seconds, the range Fargate allows."
+  }
+}
+
+variable "max_request_body_size" {
+  description = "Largest request body, in bytes, Django accepts (DATA_UPLOAD_MAX_MEMORY_SIZE). Larger requests are rejected with a 413 before the body is read into a worker's memory. File uploads stream to disk and aren't counted."
+  type        = number
+  default     = 2621440
+
+  validation {
+    condition     = var.max_request_body_size >= 1024 && var.max_request_body_size <= 104857600
+    error_message = "max_request_body_size must be between 1024 bytes and 100 MiB."
+  }
+}
+
+variable "gunicorn_threads" {
+  description = "Number of threads per Gunicorn worker. Values above 1 switch Gunicorn to the gthread worker, and each thread holds its own database connection."
+  type        = number
+  default     = 1
+}
+
+variable "db_pool_size" {
+  description = "Maximum number of requests per worker process that may hold a database connection at once. Requests beyond this wait up to db_pool_timeout seconds and then receive a 503 instead of exhausting the database's connections. If null, requests are not limited."
+  type        = number
+  default     = null
+}
+
+variable "db_pool_timeout" {
+  description = "Seconds a request waits for a free database connection slot (see db_pool_size) before receiving a 503"
+  type        = number
+  default     = 5
+}
+
+variable "db_conn_max_age" {
+  description = "Seconds Django keeps a database connection open for reuse (CONN_MAX_AGE). 0 closes the connection after every request."
+  type        = number
+  default     = 600
+}
+
+variable "cache_control_rules" {
+  description = "Cache-Control header for dynamic responses by path prefix; the first matching rule applies. Static files are served with a long max-age regardless. Keep authenticated paths no-store, private so a CDN or browser never serves one user's response to another."
+  type = list(object({
+    path_prefix   = string
+    cache_control = string
+  }))
+  default = [
+    { path_prefix = "/api/", cache_control = "no-store, private" },
+    { path_prefix = "/admin/", cache_control = "no-store, private" },
+  ]
+}
+
+variable "metrics_enabled" {
+  description = "If set to true, serve Prometheus metrics (request counters and database connection gauges) at metrics_path. The ALB never forwards metrics_path, so scrapers must reach the tasks directly from a security group in metrics_allowed_sg_ids."
+  type        = bool
+  default     = false
+}
+
+variable "metrics_path" {
+  description = "The path the Prometheus metrics endpoint is served at when metrics_enabled is true"
+  type        = string
+  default     = "/metrics/"
+
+  validation {
+    condition     = can(regex("^/[A-Za-z0-9_-]+(/[A-Za-z0-9_-]+)*/$", var.metrics_path))
+    error_message = "metrics_path must start and end with '/' and contain only letters, digits, '-', '_', and '/'."
+  }
+}
+
+variable "metrics_allowed_sg_ids" {
+  description = "Security groups, such as a Prometheus server's, allowed to reach the tasks on container_port to scrape metrics_path"
+  type        = list(string)
+  default     = []
+}
+
+variable "enable_execute_command" {
+  description = "If set to true, enable ECS Exec so commands can be run inside running tasks. Grants the created task role the required SSM permissions."
+  type        = bool
+  default     = false
+}
+
 variable "task_role_arn" {
   description = "ARN of the IAM role for the ECS task (for application-level AWS access). If null, a basic role will be created."
   type        = string
   default     = null
 }
+
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES - Error Rate Alerting
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "create_error_rate_alarm" {
+  description = "If set to true, create a log metric filter counting error log lines and an alarm on the error rate"
+  type        = bool
+  default     = false
+}
+
+variable "error_rate_filter_pattern" {
+  description = "CloudWatch Logs filter pattern for lines counted as errors. Django logs 5xx responses at ERROR level."
+  type        = string
+  default     = "?ERROR ?CRITICAL"
+}
+
+variable "error_rate_metric_namespace" {
+  description = "CloudWatch namespace for the error count metric"
+  type        = string
+  default     = "Django"
+}
+
+variable "error_rate_alarm_threshold" {
+  description = "Number of error log lines within error_rate_alarm_period that triggers the alarm"
+  type        = number
+  default     = 10
+}
+
+variable "error_rate_alarm_period" {
+  description = "Period, in seconds, over which error log lines are summed"
+  type        = number
+  default     = 60
+}
+
+variable "alarm_actions" {
+  description = "ARNs (e.g. SNS topics) to notify when the error rate alarm changes state"
+  type        = list(string)
+  default     = []
+}
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
@@ -11,3 +11,13 @@ resource "aws_ecr_repository" "foo" {
     scan_on_push = var.scan_on_push
   }
 }
+
+# Fail the plan, before anything is created, if the provider points at a region outside allowed_regions
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
+}
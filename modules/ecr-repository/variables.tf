@@ -34,3 +34,14 @@ variable "scan_on_push" {
   type        = bool
   default     = true
 }
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
@@ -4,6 +4,29 @@
 
 resource "aws_ecs_cluster" "fargate" {
   name = var.name
+
+  # Always set explicitly, so turning Container Insights off is applied rather than left at its previous value. Changing
+  # a cluster setting updates the cluster in place.
+  setting {
+    name  = "containerInsights"
+    value = var.enable_container_insights ? "enabled" : "disabled"
+  }
+}
+
+# Services can only use capacity providers associated with their cluster. Without this, a service that requests
+# FARGATE_SPOT fails to place tasks with a "No Capacity Provider" error.
+resource "aws_ecs_cluster_capacity_providers" "fargate" {
+  cluster_name       = aws_ecs_cluster.fargate.name
+  capacity_providers = var.capacity_providers
+
+  dynamic "default_capacity_provider_strategy" {
+    for_each = var.default_capacity_provider_strategy
+    content {
+      capacity_provider = default_capacity_provider_strategy.value.capacity_provider
+      weight            = default_capacity_provider_strategy.value.weight
+      base              = default_capacity_provider_strategy.value.base
+    }
+  }
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -11,12 +34,113 @@ resource "aws_ecs_cluster" "fargate" {
 # ---------------------------------------------------------------------------------------------------------------------
 
 resource "aws_ecs_service" "service" {
+  count = var.enable_autoscaling ? 0 : 1
+
+  name            = var.name
+  cluster         = aws_ecs_cluster.fargate.arn
+  desired_count   = var.desired_count
+  task_definition = aws_ecs_task_definition.service.arn
+
+  # A service either uses a launch type or a capacity provider strategy, never both
+  launch_type = length(var.service_capacity_provider_strategy) == 0 ? "FARGATE" : null
+
+  dynamic "capacity_provider_strategy" {
+    for_each = var.service_capacity_provider_strategy
+    content {
+      capacity_provider = capacity_provider_strategy.value.capacity_provider
+      weight            = capacity_provider_strategy.value.weight
+      base              = capacity_provider_strategy.value.base
+    }
+  }
+
+  deployment_minimum_healthy_percent = var.deployment_minimum_healthy_percent
+  deployment_maximum_percent         = var.deployment_maximum_percent
+
+  # Stop a deployment whose tasks keep failing to start or pass health checks, and optionally roll back to the last
+  # deployment that completed
+  deployment_circuit_breaker {
+    enable   = var.enable_deployment_circuit_breaker
+    rollback = var.enable_deployment_circuit_breaker && var.enable_rollback
+  }
+
+  load_balancer {
+    container_name   = var.name
+    container_port   = var.container_port
+    target_group_arn = aws_lb_target_group.ecs.arn
+  }
+
+  network_configuration {
+    subnets          = data.aws_subnets.default.ids
+    security_groups  = [local.service_sg_id]
+    assign_public_ip = true
+  }
+
+  # Service Connect gives other services in the namespace a stable DNS alias for this one, with built-in retries and
+  # metrics. When service_connect_port_name is null, the service only joins the namespace as a client.
+  dynamic "service_connect_configuration" {
+    for_each = var.enable_service_connect ? [1] : []
+    content {
+      enabled   = true
+      namespace = local.service_connect_namespace_arn
+
+      dynamic "service" {
+        for_each = var.service_connect_port_name != null ? [1] : []
+        content {
+          port_name      = var.service_connect_port_name
+          discovery_name = var.name
+
+          client_alias {
+            port     = var.container_port
+            dns_name = var.service_connect_dns_name != null ? var.service_connect_dns_name : var.name
+          }
+        }
+      }
+    }
+  }
+
+  lifecycle {
+    precondition {
+      condition     = alltrue([for item in var.service_capacity_provider_strategy : contains(var.capacity_providers, item.capacity_provider)])
+      error_message = "service_capacity_provider_strategy uses ${join(", ", [for item in var.service_capacity_provider_strategy : item.capacity_provider])}, but the cluster only has ${join(", ", var.capacity_providers)} associated. Add the missing provider to capacity_providers."
+    }
+  }
+
+  # Ensure ALB and capacity providers are provisioned first
+  depends_on = [aws_lb.ecs, aws_lb_listener.http, aws_lb_listener.https, aws_lb_listener_rule.forward_all, aws_lb_target_group.ecs, aws_ecs_cluster_capacity_providers.fargate]
+}
+
+# With autoscaling enabled, the service is created from this copy instead, which differs only in ignoring desired_count
+# after creation. lifecycle can't be set conditionally, so the two are switched with count. Keep them in sync.
+resource "aws_ecs_service" "autoscaled" {
+  count = var.enable_autoscaling ? 1 : 0
+
   name            = var.name
   cluster         = aws_ecs_cluster.fargate.arn
   desired_count   = var.desired_count
-  launch_type     = "FARGATE"
   task_definition = aws_ecs_task_definition.service.arn
 
+  # A service either uses a launch type or a capacity provider strategy, never both
+  launch_type = length(var.service_capacity_provider_strategy) == 0 ? "FARGATE" : null
+
+  dynamic "capacity_provider_strategy" {
+    for_each = var.service_capacity_provider_strategy
+    content {
+      capacity_provider = capacity_provider_strategy.value.capacity_provider
+      weight            = capacity_provider_strategy.value.weight
+      base              = capacity_provider_strategy.value.base
+    }
+  }
+
+  deployment_minimum_healthy_percent = var.deployment_minimum_healthy_percent
+  deployment_maximum_percent         = var.deployment_maximum_percent
+
+  # Stop a deployment whose tasks keep failing to start or pass health checks, and optionally roll back to the last
+  # deployment that completed
+  deployment_circuit_breaker {
+    enable   = var.enable_deployment_circuit_breaker
+    rollback = var.enable_deployment_circuit_breaker && var.enable_rollback
+  }
+
   load_balancer {
     container_name   = var.name
     container_port   = var.container_port
@@ -29,8 +153,108 @@ resource "aws_ecs_service" "service" {
     assign_public_ip = true
   }
 
-  # Ensure ALB is provisioned first
-  depends_on = [aws_lb.ecs, aws_lb_listener.http, aws_lb_listener_rule.forward_all, aws_lb_target_group.ecs]
+  # Service Connect gives other services in the namespace a stable DNS alias for this one, with built-in retries and
+  # metrics. When service_connect_port_name is null, the service only joins the namespace as a client.
+  dynamic "service_connect_configuration" {
+    for_each = var.enable_service_connect ? [1] : []
+    content {
+      enabled   = true
+      namespace = local.service_connect_namespace_arn
+
+      dynamic "service" {
+        for_each = var.service_connect_port_name != null ? [1] : []
+        content {
+          port_name      = var.service_connect_port_name
+          discovery_name = var.name
+
+          client_alias {
+            port     = var.container_port
+            dns_name = var.service_connect_dns_name != null ? var.service_connect_dns_name : var.name
+          }
+        }
+      }
+    }
+  }
+
+  lifecycle {
+    # The scaler owns the task count once the service exists, so applying the module again mustn't reset it
+    ignore_changes = [desired_count]
+
+    precondition {
+      condition     = alltrue([for item in var.service_capacity_provider_strategy : contains(var.capacity_providers, item.capacity_provider)])
+      error_message = "service_capacity_provider_strategy uses ${join(", ", [for item in var.service_capacity_provider_strategy : item.capacity_provider])}, but the cluster only has ${join(", ", var.capacity_providers)} associated. Add the missing provider to capacity_providers."
+    }
+  }
+
+  # Ensure ALB and capacity providers are provisioned first
+  depends_on = [aws_lb.ecs, aws_lb_listener.http, aws_lb_listener.https, aws_lb_listener_rule.forward_all, aws_lb_target_group.ecs, aws_ecs_cluster_capacity_providers.fargate]
+}
+
+locals {
+  service = one(concat(aws_ecs_service.service, aws_ecs_service.autoscaled))
+}
+
+moved {
+  from = aws_ecs_service.service
+  to   = aws_ecs_service.service[0]
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# AUTOSCALE THE SERVICE ON CPU
+# Target tracking adds tasks quickly when average CPU rises above the target and removes them slowly once it falls, with
+# the cooldowns spacing out consecutive scaling actions in the same direction so short bursts don't cause flapping.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_appautoscaling_target" "service" {
+  count = var.enable_autoscaling ? 1 : 0
+
+  service_namespace  = "ecs"
+  resource_id        = "service/${aws_ecs_cluster.fargate.name}/${local.service.name}"
+  scalable_dimension = "ecs:service:DesiredCount"
+  min_capacity       = var.min_capacity
+  max_capacity       = var.max_capacity
+
+  lifecycle {
+    precondition {
+      condition     = var.min_capacity <= var.max_capacity
+      error_message = "min_capacity (${var.min_capacity}) must not be greater than max_capacity (${var.max_capacity})."
+    }
+  }
+}
+
+resource "aws_appautoscaling_policy" "cpu" {
+  count = var.enable_autoscaling ? 1 : 0
+
+  name               = "${var.name}-cpu"
+  policy_type        = "TargetTrackingScaling"
+  service_namespace  = aws_appautoscaling_target.service[0].service_namespace
+  resource_id        = aws_appautoscaling_target.service[0].resource_id
+  scalable_dimension = aws_appautoscaling_target.service[0].scalable_dimension
+
+  target_tracking_scaling_policy_configuration {
+    target_value       = var.target_cpu_utilization
+    scale_out_cooldown = var.scale_out_cooldown
+    scale_in_cooldown  = var.scale_in_cooldown
+
+    predefined_metric_specification {
+      predefined_metric_type = "ECSServiceAverageCPUUtilization"
+    }
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A SERVICE CONNECT NAMESPACE
+# Only created when Service Connect is enabled and no existing namespace is passed in. Services that need to talk to
+# each other must share a namespace, so pass this module's namespace ARN to the other services.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_service_discovery_http_namespace" "service_connect" {
+  count = var.enable_service_connect && var.service_connect_namespace_arn == null ? 1 : 0
+  name  = var.name
+}
+
+locals {
+  service_connect_namespace_arn = var.service_connect_namespace_arn != null ? var.service_connect_namespace_arn : try(aws_service_discovery_http_namespace.service_connect[0].arn, null)
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -42,15 +266,157 @@ resource "aws_ecs_task_definition" "service" {
   network_mode             = "awsvpc"
   cpu                      = var.cpu
   memory                   = var.memory
-  container_definitions    = var.container_definitions
+  container_definitions    = local.container_definitions
   requires_compatibilities = ["FARGATE"]
   execution_role_arn       = aws_iam_role.ecs_task_execution_role.arn
+  task_role_arn            = var.task_role_arn
 
   runtime_platform {
     cpu_architecture = var.cpu_architecture
   }
 }
 
+# ---------------------------------------------------------------------------------------------------------------------
+# INJECT SECRETS AND A HEALTH CHECK INTO THE APP CONTAINERS
+# Each entry in secrets becomes an environment variable in every container in container_definitions, resolved from
+# Secrets Manager by ECS when the task starts, so secret values never appear in the task definition. When
+# health_check_command is set, every container also gets a health check that ECS runs inside it.
+# ---------------------------------------------------------------------------------------------------------------------
+
+locals {
+  secret_environment = [for name, arn in var.secrets : { name = name, valueFrom = arn }]
+
+  # An empty command would be rejected by ECS, so no command means no healthCheck block at all
+  container_health_check = length(var.health_check_command) == 0 ? {} : {
+    healthCheck = {
+      command     = var.health_check_command
+      interval    = var.health_check_interval
+      timeout     = var.health_check_timeout
+      retries     = var.health_check_retries
+      startPeriod = var.health_check_start_period
+    }
+  }
+
+  # Containers are only modified when there is something to add. Secrets are appended to any the container definition
+  # already sets.
+  customize_app_containers  = length(var.secrets) > 0 || length(var.health_check_command) > 0
+  app_containers_customized = local.customize_app_containers ? [
+    for container in jsondecode(var.container_definitions) : merge(
+      container,
+      length(var.secrets) > 0 ? { secrets = concat(try(container.secrets, []), local.secret_environment) } : {},
+      local.container_health_check,
+    )
+  ] : []
+  app_containers_as_given = local.customize_app_containers ? [] : jsondecode(var.container_definitions)
+
+  app_containers = concat(local.app_containers_customized, local.app_containers_as_given)
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# RUN AN INIT CONTAINER BEFORE THE APP
+# When init_container_definition is set, it runs to completion before the app containers start. It is non-essential so
+# the task keeps running after it exits, and each app container depends on it with condition SUCCESS so a failed init
+# stops the task instead of starting the app against an unprepared environment.
+# ---------------------------------------------------------------------------------------------------------------------
+
+locals {
+  init_containers = var.init_container_definition == null ? [] : [
+    merge(jsondecode(var.init_container_definition), { essential = false })
+  ]
+
+  init_dependencies = [
+    for container in local.init_containers : {
+      containerName = container.name
+      condition     = "SUCCESS"
+    }
+  ]
+
+  # Containers are only given a dependsOn when there is an init container, so task definitions without one are unchanged
+  app_containers_after_init = var.init_container_definition == null ? [] : [
+    for container in local.app_containers : merge(container, {
+      dependsOn = concat(try(container.dependsOn, []), local.init_dependencies)
+    })
+  ]
+  app_containers_without_init = var.init_container_definition == null ? local.app_containers : []
+
+  workload_containers = concat(local.init_containers, local.app_containers_after_init, local.app_containers_without_init)
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ROUTE CONTAINER LOGS TO CLOUDWATCH OR THROUGH FIRELENS
+# By default, every container that doesn't set its own logConfiguration sends its logs to a CloudWatch log group named
+# after the service, with streams named ecs/<container>/<task ID>.
+# When firelens_configuration is set, a FluentBit log router sidecar is added to the task and every container in
+# container_definitions, plus the init container if there is one, sends its logs to it using the awsfirelens driver.
+# FluentBit then forwards them to the configured output plugin (e.g. http, kinesis_streams, datadog, splunk). The log
+# router's own logs go to CloudWatch so delivery problems can be debugged.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_log_group" "app" {
+  count             = var.firelens_configuration == null ? 1 : 0
+  name              = "/ecs/${var.name}"
+  retention_in_days = var.log_retention_days
+}
+
+resource "aws_cloudwatch_log_group" "log_router" {
+  count             = var.firelens_configuration != null ? 1 : 0
+  name              = "/ecs/${var.name}/log-router"
+  retention_in_days = 7
+}
+
+locals {
+  log_router_container = var.firelens_configuration == null ? [] : [{
+    name              = "log_router"
+    image             = var.firelens_image
+    essential         = true
+    memoryReservation = var.firelens_memory_reservation
+
+    firelensConfiguration = {
+      type = "fluentbit"
+      options = {
+        "enable-ecs-log-metadata" = "true"
+      }
+    }
+
+    logConfiguration = {
+      logDriver = "awslogs"
+      options = {
+        "awslogs-group"         = aws_cloudwatch_log_group.log_router[0].name
+        "awslogs-region"        = data.aws_region.current.name
+        "awslogs-stream-prefix" = "log-router"
+      }
+    }
+  }]
+
+  workload_containers_with_firelens = var.firelens_configuration == null ? [] : [
+    for container in local.workload_containers : merge(container, {
+      logConfiguration = {
+        logDriver = "awsfirelens"
+        options   = merge({ Name = var.firelens_configuration.destination }, var.firelens_configuration.options)
+      }
+    })
+  ]
+
+  # A logConfiguration in the container definition takes precedence over the default
+  workload_containers_with_awslogs = var.firelens_configuration != null ? [] : [
+    for container in local.workload_containers : merge({
+      logConfiguration = {
+        logDriver = "awslogs"
+        options = {
+          "awslogs-group"         = aws_cloudwatch_log_group.app[0].name
+          "awslogs-region"        = data.aws_region.current.name
+          "awslogs-stream-prefix" = "ecs"
+        }
+      }
+    }, container)
+  ]
+
+  container_definitions = (
+    var.firelens_configuration != null ? jsonencode(concat(local.workload_containers_with_firelens, local.log_router_container)) :
+    jsonencode(local.workload_containers_with_awslogs)
+  )
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE IAM ROLE FOR ECS TASK EXECUTION
 # ---------------------------------------------------------------------------------------------------------------------
@@ -77,6 +443,29 @@ resource "aws_iam_role_policy_attachment" "ecs_task_execution_role_policy" {
   policy_arn = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
 }
 
+locals {
+  # A JSON key selector (<arn>:<json-key>::) isn't part of the secret's ARN, so IAM needs the ARN without it
+  secret_arns = distinct([for arn in values(var.secrets) : join(":", slice(split(":", arn), 0, 7))])
+}
+
+# ECS fetches secrets with the execution role when the task starts, before any container runs
+resource "aws_iam_role_policy" "ecs_task_execution_secrets" {
+  count = length(var.secrets) > 0 ? 1 : 0
+  name  = "${var.name}-secrets"
+  role  = aws_iam_role.ecs_task_execution_role.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect   = "Allow"
+        Action   = ["secretsmanager:GetSecretValue"]
+        Resource = local.secret_arns
+      }
+    ]
+  })
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE A SECURITY GROUP FOR THE ECS SERVICE
 # ---------------------------------------------------------------------------------------------------------------------
@@ -88,6 +477,8 @@ module "service_sg" {
 
   name   = "${var.name}-service"
   vpc_id = data.aws_vpc.default.id
+
+  allowed_regions = var.allowed_regions
 }
 
 locals {
@@ -103,6 +494,8 @@ module "allow_outbound_all" {
   to_port           = 0
   protocol          = "-1"
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
 
 module "allow_inbound_on_container_port" {
@@ -112,6 +505,21 @@ module "allow_inbound_on_container_port" {
   from_port                = var.container_port
   to_port                  = var.container_port
   source_security_group_id = local.alb_sg_id
+
+  allowed_regions = var.allowed_regions
+}
+
+module "allow_inbound_from_service_connect_clients" {
+  for_each = toset(var.service_connect_client_sg_ids)
+
+  source = "../sg-rule"
+
+  security_group_id        = local.service_sg_id
+  from_port                = var.container_port
+  to_port                  = var.container_port
+  source_security_group_id = each.value
+
+  allowed_regions = var.allowed_regions
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -129,6 +537,69 @@ resource "aws_lb" "ecs" {
   load_balancer_type = "application"
   subnets            = local.subnets_for_alb
   security_groups    = [local.alb_sg_id]
+
+  dynamic "access_logs" {
+    for_each = local.access_logs_enabled ? [1] : []
+    content {
+      bucket  = var.access_logs_bucket
+      prefix  = var.access_logs_prefix
+      enabled = true
+    }
+  }
+
+  # Enabling access logs fails unless ELB can already write to the bucket
+  depends_on = [aws_s3_bucket_policy.access_logs]
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# LET ELB DELIVER ACCESS LOGS TO THE BUCKET
+# When access_logs_bucket is set, the ALB writes a log file for every 5 minutes of requests to
+# <prefix>/AWSLogs/<account ID>/elasticloadbalancing/ in the bucket. Regions launched before August 2022 deliver from a
+# regional ELB account, newer ones from the log delivery service, so the policy allows both.
+# ---------------------------------------------------------------------------------------------------------------------
+
+locals {
+  access_logs_enabled = var.access_logs_bucket != null && var.access_logs_bucket != ""
+  access_logs_path    = var.access_logs_prefix == null || var.access_logs_prefix == "" ? "AWSLogs" : "${var.access_logs_prefix}/AWSLogs"
+}
+
+data "aws_caller_identity" "current" {}
+
+data "aws_elb_service_account" "current" {
+  count = local.access_logs_enabled ? 1 : 0
+}
+
+resource "aws_s3_bucket_policy" "access_logs" {
+  count  = local.access_logs_enabled && var.manage_access_logs_bucket_policy ? 1 : 0
+  bucket = var.access_logs_bucket
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid       = "AllowELBAccountLogDelivery"
+        Effect    = "Allow"
+        Principal = { AWS = data.aws_elb_service_account.current[0].arn }
+        Action    = "s3:PutObject"
+        Resource  = "arn:aws:s3:::${var.access_logs_bucket}/${local.access_logs_path}/${data.aws_caller_identity.current.account_id}/*"
+      },
+      {
+        Sid       = "AllowLogDeliveryService"
+        Effect    = "Allow"
+        Principal = { Service = "logdelivery.elasticloadbalancing.amazonaws.com" }
+        Action    = "s3:PutObject"
+        Resource  = "arn:aws:s3:::${var.access_logs_bucket}/${local.access_logs_path}/${data.aws_caller_identity.current.account_id}/*"
+      },
+    ]
+  })
+}
+
+locals {
+  # An HTTPS listener without a certificate can't be created, so an empty certificate_arn keeps the ALB HTTP-only
+  https_enabled = var.enable_https && var.certificate_arn != null && var.certificate_arn != ""
+
+  # Routing rules live on whichever listener serves the app
+  app_listener_arn = local.https_enabled ? aws_lb_listener.https[0].arn : aws_lb_listener.http.arn
 }
 
 resource "aws_lb_listener" "http" {
@@ -136,53 +607,116 @@ resource "aws_lb_listener" "http" {
   port              = var.alb_port
   protocol          = "HTTP"
 
-  # By default, return a simple 404 page
+  # With HTTPS enabled, every plain HTTP request is permanently redirected to the same path on port 443
+  dynamic "default_action" {
+    for_each = local.https_enabled ? [1] : []
+    content {
+      type = "redirect"
+
+      redirect {
+        port        = "443"
+        protocol    = "HTTPS"
+        status_code = "HTTP_301"
+      }
+    }
+  }
+
+  # Requests that match none of the routed_path_patterns get this response at the ALB without reaching the service
+  dynamic "default_action" {
+    for_each = local.https_enabled ? [] : [1]
+    content {
+      type = "fixed-response"
+
+      fixed_response {
+        content_type = var.default_fixed_response.content_type
+        message_body = var.default_fixed_response.message_body
+        status_code  = var.default_fixed_response.status_code
+      }
+    }
+  }
+}
+
+resource "aws_lb_listener" "https" {
+  count             = local.https_enabled ? 1 : 0
+  load_balancer_arn = aws_lb.ecs.arn
+  port              = 443
+  protocol          = "HTTPS"
+  certificate_arn   = var.certificate_arn
+  ssl_policy        = var.ssl_policy
+
   default_action {
     type = "fixed-response"
 
     fixed_response {
-      content_type = "text/plain"
-      message_body = "404: page not found"
-      status_code  = 404
+      content_type = var.default_fixed_response.content_type
+      message_body = var.default_fixed_response.message_body
+      status_code  = var.default_fixed_response.status_code
     }
   }
 }
 
 resource "aws_lb_target_group" "ecs" {
-  name_prefix = substr(var.name, 0, 6)
-  port        = var.container_port
-  protocol    = "HTTP"
-  vpc_id      = data.aws_vpc.default.id
-  target_type = "ip"
+  name_prefix      = substr(var.name, 0, 6)
+  port             = var.container_port
+  protocol         = var.target_group_protocol
+  protocol_version = var.target_group_protocol_version
+  vpc_id           = data.aws_vpc.default.id
+  target_type      = "ip"
 
   health_check {
-    path                = "/"
-    protocol            = "HTTP"
-    matcher             = "200"
+    path                = var.health_check_path
+    protocol            = var.target_group_protocol
+    matcher             = var.health_check_matcher
     interval            = 15
     timeout             = 3
-    healthy_threshold   = 2
+    healthy_threshold   = var.health_check_healthy_threshold
     unhealthy_threshold = 2
   }
 
   lifecycle {
     create_before_destroy = true
+
+    precondition {
+      condition     = var.target_group_protocol_version == "HTTP1" || var.target_group_protocol == "HTTPS"
+      error_message = "target_group_protocol_version = ${var.target_group_protocol_version} requires target_group_protocol = HTTPS."
+    }
   }
 }
 
+locals {
+  # With no tasks to forward to, the ALB would answer with its own 503 page, which is indistinguishable from an outage
+  paused = var.desired_count == 0
+}
+
 resource "aws_lb_listener_rule" "forward_all" {
-  listener_arn = aws_lb_listener.http.arn
+  listener_arn = local.app_listener_arn
   priority     = 100
 
   condition {
     path_pattern {
-      values = ["*"]
+      values = var.routed_path_patterns
     }
   }
 
-  action {
-    type             = "forward"
-    target_group_arn = aws_lb_target_group.ecs.arn
+  dynamic "action" {
+    for_each = local.paused ? [] : [1]
+    content {
+      type             = "forward"
+      target_group_arn = aws_lb_target_group.ecs.arn
+    }
+  }
+
+  dynamic "action" {
+    for_each = local.paused ? [1] : []
+    content {
+      type = "fixed-response"
+
+      fixed_response {
+        content_type = var.paused_response.content_type
+        message_body = var.paused_response.message_body
+        status_code  = var.paused_response.status_code
+      }
+    }
   }
 }
 
@@ -200,6 +734,8 @@ module "alb_sg" {
 
   name   = "${var.name}-alb"
   vpc_id = data.aws_vpc.default.id
+
+  allowed_regions = var.allowed_regions
 }
 
 locals {
@@ -213,6 +749,20 @@ module "alb_allow_http_inbound" {
   from_port         = var.alb_port
   to_port           = var.alb_port
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
+}
+
+module "alb_allow_https_inbound" {
+  count  = local.https_enabled ? 1 : 0
+  source = "../sg-rule"
+
+  security_group_id = local.alb_sg_id
+  from_port         = 443
+  to_port           = 443
+  cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
 
 module "alb_allow_all_outbound" {
@@ -224,4 +774,95 @@ module "alb_allow_all_outbound" {
   to_port           = 0
   protocol          = "-1"
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A CLOUDWATCH DASHBOARD
+# When create_dashboard is true, a dashboard named after the service shows its CPU and memory alongside the ALB's
+# request count, 5XX errors, response time, and healthy targets.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_dashboard" "service" {
+  count          = var.create_dashboard ? 1 : 0
+  dashboard_name = var.name
+
+  dashboard_body = jsonencode({
+    widgets = [
+      {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title  = "CPU and memory utilization"
+          region = data.aws_region.current.name
+          stat   = "Average"
+          period = 300
+          metrics = [
+            ["AWS/ECS", "CPUUtilization", "ClusterName", aws_ecs_cluster.fargate.name, "ServiceName", local.service.name],
+            ["AWS/ECS", "MemoryUtilization", "ClusterName", aws_ecs_cluster.fargate.name, "ServiceName", local.service.name],
+          ]
+        }
+      },
+      {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title  = "Requests and 5XX errors"
+          region = data.aws_region.current.name
+          stat   = "Sum"
+          period = 300
+          metrics = [
+            ["AWS/ApplicationELB", "RequestCount", "LoadBalancer", aws_lb.ecs.arn_suffix],
+            ["AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", "LoadBalancer", aws_lb.ecs.arn_suffix],
+            ["AWS/ApplicationELB", "HTTPCode_ELB_5XX_Count", "LoadBalancer", aws_lb.ecs.arn_suffix],
+          ]
+        }
+      },
+      {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title  = "Target response time"
+          region = data.aws_region.current.name
+          stat   = "p99"
+          period = 300
+          metrics = [
+            ["AWS/ApplicationELB", "TargetResponseTime", "LoadBalancer", aws_lb.ecs.arn_suffix],
+          ]
+        }
+      },
+      {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title  = "Healthy targets"
+          region = data.aws_region.current.name
+          stat   = "Minimum"
+          period = 60
+          metrics = [
+            ["AWS/ApplicationELB", "HealthyHostCount", "TargetGroup", aws_lb_target_group.ecs.arn_suffix, "LoadBalancer", aws_lb.ecs.arn_suffix],
+          ]
+        }
+      },
+    ]
+  })
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENFORCE THE REGION ALLOWLIST
+# Fails the plan, before anything is created, if the provider points at a region outside allowed_regions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
 }
@@ -2,10 +2,30 @@ output "url" {
   value = "http://${aws_lb.ecs.dns_name}:${var.alb_port}"
 }
 
+output "https_url" {
+  value = local.https_enabled ? "https://${aws_lb.ecs.dns_name}" : null
+}
+
 output "alb_dns_name" {
   value = aws_lb.ecs.dns_name
 }
 
+output "access_logs_enabled" {
+  value = local.access_logs_enabled
+}
+
+output "alb_listener_arn" {
+  value = aws_lb_listener.http.arn
+}
+
+output "paused" {
+  value = local.paused
+}
+
+output "target_group_arn" {
+  value = aws_lb_target_group.ecs.arn
+}
+
 output "service_security_group_id" {
   value = local.service_sg_id
 }
@@ -13,3 +33,53 @@ output "service_security_group_id" {
 output "alb_security_group_id" {
   value = local.alb_sg_id
 }
+
+output "ecs_cluster_name" {
+  value = aws_ecs_cluster.fargate.name
+}
+
+output "ecs_service_name" {
+  value = local.service.name
+}
+
+output "launch_type" {
+  value = local.service.launch_type
+}
+
+output "capacity_provider_strategy" {
+  value = [
+    for item in local.service.capacity_provider_strategy : {
+      capacity_provider = item.capacity_provider
+      weight            = item.weight
+      base              = item.base
+    }
+  ]
+}
+
+output "service_connect_namespace_arn" {
+  value = local.service_connect_namespace_arn
+}
+
+output "task_definition_arn" {
+  value = aws_ecs_task_definition.service.arn
+}
+
+output "log_group_name" {
+  value = try(aws_cloudwatch_log_group.app[0].name, null)
+}
+
+output "log_router_log_group_name" {
+  value = try(aws_cloudwatch_log_group.log_router[0].name, null)
+}
+
+output "autoscaling_policy_name" {
+  value = try(aws_appautoscaling_policy.cpu[0].name, null)
+}
+
+output "scaling_target_resource_id" {
+  value = try(aws_appautoscaling_target.service[0].resource_id, null)
+}
+
+output "dashboard_name" {
+  value = try(aws_cloudwatch_dashboard.service[0].dashboard_name, null)
+}
@@ -13,7 +13,7 @@ variable "container_definitions" {
 }
 
 variable "desired_count" {
-  description = "How many instances of the service to run"
+  description = "How many instances of the service to run. 0 pauses the service: the ALB answers routed paths with paused_response instead of forwarding them to a target group with no targets."
   type        = number
 }
 
@@ -53,8 +53,443 @@ variable "alb_sg_id" {
   default     = null
 }
 
+variable "routed_path_patterns" {
+  description = "The path patterns the ALB forwards to the service. Requests matching none of them get default_fixed_response instead. Defaults to forwarding everything."
+  type        = list(string)
+  default     = ["*"]
+
+  validation {
+    condition     = length(var.routed_path_patterns) >= 1 && length(var.routed_path_patterns) <= 5
+    error_message = "routed_path_patterns must contain between 1 and 5 patterns, the most an ALB listener rule condition allows."
+  }
+}
+
+variable "default_fixed_response" {
+  description = "The response the ALB returns for requests that match none of the routed_path_patterns"
+  type = object({
+    content_type = string
+    message_body = string
+    status_code  = number
+  })
+  default = {
+    content_type = "text/plain"
+    message_body = "404: page not found"
+    status_code  = 404
+  }
+
+  validation {
+    condition     = contains(["text/plain", "text/css", "text/html", "application/javascript", "application/json"], var.default_fixed_response.content_type)
+    error_message = "default_fixed_response.content_type must be one of text/plain, text/css, text/html, application/javascript, or application/json."
+  }
+
+  validation {
+    condition     = can(regex("^[245][0-9][0-9]$", tostring(var.default_fixed_response.status_code)))
+    error_message = "default_fixed_response.status_code must be a 2XX, 4XX, or 5XX status code."
+  }
+
+  validation {
+    condition     = length(var.default_fixed_response.message_body) <= 1024
+    error_message = "default_fixed_response.message_body must be at most 1024 characters."
+  }
+}
+
+variable "paused_response" {
+  description = "The response the ALB returns for routed paths while the service is paused with desired_count = 0"
+  type = object({
+    content_type = string
+    message_body = string
+    status_code  = number
+  })
+  default = {
+    content_type = "text/plain"
+    message_body = "503: service paused for maintenance"
+    status_code  = 503
+  }
+
+  validation {
+    condition     = contains(["text/plain", "text/css", "text/html", "application/javascript", "application/json"], var.paused_response.content_type)
+    error_message = "paused_response.content_type must be one of text/plain, text/css, text/html, application/javascript, or application/json."
+  }
+
+  validation {
+    condition     = can(regex("^[245][0-9][0-9]$", tostring(var.paused_response.status_code)))
+    error_message = "paused_response.status_code must be a 2XX, 4XX, or 5XX status code."
+  }
+}
+
+variable "target_group_protocol" {
+  description = "The protocol the ALB uses to reach the container, HTTP or HTTPS. It must match what the app serves on container_port, or every target fails its health check."
+  type        = string
+  default     = "HTTP"
+
+  validation {
+    condition     = contains(["HTTP", "HTTPS"], var.target_group_protocol)
+    error_message = "target_group_protocol must be HTTP or HTTPS."
+  }
+}
+
+variable "target_group_protocol_version" {
+  description = "The protocol version the ALB uses to reach the container: HTTP1, HTTP2, or GRPC. HTTP2 and GRPC require target_group_protocol = HTTPS."
+  type        = string
+  default     = "HTTP1"
+
+  validation {
+    condition     = contains(["HTTP1", "HTTP2", "GRPC"], var.target_group_protocol_version)
+    error_message = "target_group_protocol_version must be HTTP1, HTTP2, or GRPC."
+  }
+}
+
+variable "health_check_path" {
+  description = "The path the ALB requests to check each target's health, e.g. /health/live/ for the Django app. It must return a code in health_check_matcher without redirecting, or targets flap between healthy and unhealthy."
+  type        = string
+  default     = "/"
+
+  validation {
+    condition     = substr(var.health_check_path, 0, 1) == "/"
+    error_message = "health_check_path must start with /."
+  }
+}
+
+variable "health_check_matcher" {
+  description = "The response codes that count as a healthy target, as a code, a range, or a comma-separated list of either, e.g. 200, 200-299, or 200,302. For GRPC these are gRPC status codes, e.g. 0 or 0-99."
+  type        = string
+  default     = "200"
+
+  validation {
+    condition     = can(regex("^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$", var.health_check_matcher))
+    error_message = "health_check_matcher must be a code, a range such as 200-299, or a comma-separated list of codes and ranges."
+  }
+}
+
+variable "health_check_healthy_threshold" {
+  description = "The number of consecutive successful ALB health checks before a target counts as healthy"
+  type        = number
+  default     = 2
+
+  validation {
+    condition     = var.health_check_healthy_threshold >= 2 && var.health_check_healthy_threshold <= 10
+    error_message = "health_check_healthy_threshold must be between 2 and 10."
+  }
+}
+
+variable "health_check_command" {
+  description = "The command ECS runs inside each container in container_definitions to check its health, e.g. [\"CMD-SHELL\", \"curl -f http://localhost:5000/ || exit 1\"]. Leave empty for no container health check."
+  type        = list(string)
+  default     = []
+
+  validation {
+    condition     = length(var.health_check_command) == 0 || contains(["CMD", "CMD-SHELL"], try(var.health_check_command[0], ""))
+    error_message = "health_check_command must start with CMD or CMD-SHELL, or be empty to disable the health check."
+  }
+}
+
+variable "health_check_interval" {
+  description = "Seconds between container health checks"
+  type        = number
+  default     = 30
+
+  validation {
+    condition     = var.health_check_interval >= 5 && var.health_check_interval <= 300
+    error_message = "health_check_interval must be between 5 and 300 seconds."
+  }
+}
+
+variable "health_check_timeout" {
+  description = "Seconds a container health check may take before it counts as a failure"
+  type        = number
+  default     = 5
+
+  validation {
+    condition     = var.health_check_timeout >= 2 && var.health_check_timeout <= 60
+    error_message = "health_check_timeout must be between 2 and 60 seconds."
+  }
+}
+
+variable "health_check_retries" {
+  description = "Consecutive failed container health checks before the container is marked unhealthy"
+  type        = number
+  default     = 3
+
+  validation {
+    condition     = var.health_check_retries >= 1 && var.health_check_retries <= 10
+    error_message = "health_check_retries must be between 1 and 10."
+  }
+}
+
+variable "health_check_start_period" {
+  description = "Seconds after the container starts during which failed health checks don't count, so slow starts aren't killed"
+  type        = number
+  default     = 0
+
+  validation {
+    condition     = var.health_check_start_period >= 0 && var.health_check_start_period <= 300
+    error_message = "health_check_start_period must be between 0 and 300 seconds."
+  }
+}
+
+variable "enable_https" {
+  description = "If true and certificate_arn is set, the ALB serves HTTPS on port 443 and alb_port redirects to it. Without a certificate the ALB stays HTTP-only."
+  type        = bool
+  default     = false
+}
+
+variable "certificate_arn" {
+  description = "The ARN of the ACM certificate for the HTTPS listener. Only used when enable_https is true."
+  type        = string
+  default     = null
+}
+
+variable "ssl_policy" {
+  description = "The security policy for the HTTPS listener, which sets the TLS versions and ciphers it accepts"
+  type        = string
+  default     = "ELBSecurityPolicy-TLS13-1-2-2021-06"
+}
+
+variable "access_logs_bucket" {
+  description = "Name of an existing S3 bucket to write ALB access logs to. If null, access logs are disabled. The bucket must use SSE-S3 encryption, not SSE-KMS."
+  type        = string
+  default     = null
+}
+
+variable "access_logs_prefix" {
+  description = "Key prefix for ALB access logs in access_logs_bucket. If null, logs are written at the root of the bucket."
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.access_logs_prefix == null || !can(regex("(^/|/$|AWSLogs)", var.access_logs_prefix))
+    error_message = "access_logs_prefix must not start or end with a slash or contain AWSLogs, which ELB adds itself."
+  }
+}
+
+variable "manage_access_logs_bucket_policy" {
+  description = "If set to true, attach a bucket policy to access_logs_bucket that lets ELB write the logs. This replaces any existing policy on the bucket, so set it to false and grant the access yourself if the bucket already has one."
+  type        = bool
+  default     = true
+}
+
 variable "cpu_architecture" {
   description = "The CPU architecture for the service"
   type        = string
   default     = "ARM64"
 }
+
+variable "deployment_minimum_healthy_percent" {
+  description = "The lower limit, as a percentage of desired_count, of running tasks that must remain healthy during a deployment"
+  type        = number
+  default     = 100
+}
+
+variable "deployment_maximum_percent" {
+  description = "The upper limit, as a percentage of desired_count, of running tasks that can run during a deployment"
+  type        = number
+  default     = 200
+}
+
+variable "enable_deployment_circuit_breaker" {
+  description = "If set to true, ECS marks a deployment as failed once its tasks repeatedly fail to start or pass health checks, instead of retrying them indefinitely"
+  type        = bool
+  default     = false
+}
+
+variable "enable_rollback" {
+  description = "If set to true, a deployment stopped by the circuit breaker rolls back to the last completed deployment. Only applies when enable_deployment_circuit_breaker is true."
+  type        = bool
+  default     = true
+}
+
+variable "enable_container_insights" {
+  description = "If set to true, enable CloudWatch Container Insights on the cluster for task-level CPU, memory, and network metrics. Container Insights metrics are billed as custom metrics."
+  type        = bool
+  default     = false
+}
+
+variable "capacity_providers" {
+  description = "The capacity providers to associate with the cluster. Any provider used by a capacity provider strategy must be listed here."
+  type        = list(string)
+  default     = ["FARGATE", "FARGATE_SPOT"]
+
+  validation {
+    condition     = length(var.capacity_providers) > 0 && alltrue([for provider in var.capacity_providers : contains(["FARGATE", "FARGATE_SPOT"], provider)])
+    error_message = "capacity_providers must be a non-empty list containing only FARGATE and/or FARGATE_SPOT."
+  }
+}
+
+variable "default_capacity_provider_strategy" {
+  description = "The cluster's default capacity provider strategy, used by tasks and services that don't specify a launch type or strategy"
+  type = list(object({
+    capacity_provider = string
+    weight            = number
+    base              = number
+  }))
+  default = [
+    {
+      capacity_provider = "FARGATE"
+      weight            = 1
+      base              = 0
+    }
+  ]
+}
+
+variable "service_capacity_provider_strategy" {
+  description = "The capacity provider strategy for the service, e.g. a FARGATE base with the rest on FARGATE_SPOT. If empty, the service uses the FARGATE launch type."
+  type = list(object({
+    capacity_provider = string
+    weight            = number
+    base              = number
+  }))
+  default = []
+
+  validation {
+    condition     = alltrue([for item in var.service_capacity_provider_strategy : contains(["FARGATE", "FARGATE_SPOT"], item.capacity_provider)])
+    error_message = "service_capacity_provider_strategy may only use the FARGATE and FARGATE_SPOT capacity providers."
+  }
+
+  validation {
+    condition     = alltrue([for item in var.service_capacity_provider_strategy : item.weight >= 0 && item.weight <= 1000 && item.base >= 0 && item.base <= 100000])
+    error_message = "Each service_capacity_provider_strategy weight must be between 0 and 1000 and each base between 0 and 100000."
+  }
+
+  validation {
+    condition     = length(var.service_capacity_provider_strategy) == 0 || anytrue([for item in var.service_capacity_provider_strategy : item.weight > 0])
+    error_message = "At least one provider in service_capacity_provider_strategy must have a weight greater than 0."
+  }
+
+  validation {
+    condition     = length([for item in var.service_capacity_provider_strategy : item if item.base > 0]) <= 1
+    error_message = "Only one provider in service_capacity_provider_strategy can have a base greater than 0."
+  }
+}
+
+variable "enable_service_connect" {
+  description = "If set to true, register the service with ECS Service Connect so other services can reach it by DNS alias"
+  type        = bool
+  default     = false
+}
+
+variable "service_connect_namespace_arn" {
+  description = "ARN of an existing Cloud Map HTTP namespace to join. If null and Service Connect is enabled, a namespace named after the service is created."
+  type        = string
+  default     = null
+}
+
+variable "service_connect_port_name" {
+  description = "The name of the container port mapping to expose via Service Connect. Must match a portMappings name in container_definitions. If null, the service only joins the namespace as a client."
+  type        = string
+  default     = null
+}
+
+variable "service_connect_dns_name" {
+  description = "The DNS alias other services use to reach this one. Defaults to the service name."
+  type        = string
+  default     = null
+}
+
+variable "service_connect_client_sg_ids" {
+  description = "Security group IDs of Service Connect clients allowed to reach the container port"
+  type        = list(string)
+  default     = []
+}
+
+variable "enable_autoscaling" {
+  description = "If set to true, scale the service between min_capacity and max_capacity to keep average CPU utilization near target_cpu_utilization. desired_count is then only the starting count: the service is managed as aws_ecs_service.autoscaled, which ignores later changes to it, so applying the module again keeps the count the scaler chose. Services created with autoscaling enabled by an earlier version of this module must be moved to it in the state (aws_ecs_service.service[0] to aws_ecs_service.autoscaled[0]) before applying, or they are replaced."
+  type        = bool
+  default     = false
+}
+
+variable "min_capacity" {
+  description = "The fewest tasks autoscaling may scale in to"
+  type        = number
+  default     = 1
+}
+
+variable "max_capacity" {
+  description = "The most tasks autoscaling may scale out to"
+  type        = number
+  default     = 4
+}
+
+variable "target_cpu_utilization" {
+  description = "The average CPU utilization, as a percentage, that autoscaling adds or removes tasks to stay near"
+  type        = number
+  default     = 70
+}
+
+variable "scale_out_cooldown" {
+  description = "Seconds after a scale-out before autoscaling may scale out again. Keep it short so the service reacts quickly to load."
+  type        = number
+  default     = 60
+}
+
+variable "scale_in_cooldown" {
+  description = "Seconds after a scale-in before autoscaling may scale in again. Keep it long enough for a burst to subside so the service doesn't flap."
+  type        = number
+  default     = 300
+}
+
+variable "task_role_arn" {
+  description = "ARN of the IAM role the containers assume. Needed when the FireLens destination (e.g. Kinesis or CloudWatch) requires AWS permissions."
+  type        = string
+  default     = null
+}
+
+variable "secrets" {
+  description = "Secrets Manager secrets to inject into every container in container_definitions, as a map of environment variable name to secret ARN. To inject one key of a JSON secret, append it to the ARN as <arn>:<json-key>::. The task execution role is granted read access to each secret."
+  type        = map(string)
+  default     = {}
+
+  validation {
+    condition     = alltrue([for arn in values(var.secrets) : can(regex("^arn:[^:]+:secretsmanager:[^:]+:[0-9]{12}:secret:[^:]+(:[^:]*:[^:]*:[^:]*)?$", arn))])
+    error_message = "Each secrets value must be a Secrets Manager secret ARN, optionally followed by :<json-key>:<version-stage>:<version-id>."
+  }
+}
+
+variable "init_container_definition" {
+  description = "If set, a JSON-encoded container definition for a run-once init container (e.g. database migrations). It is marked non-essential, and every container in container_definitions waits for it to exit successfully before starting."
+  type        = string
+  default     = null
+}
+
+variable "log_retention_days" {
+  description = "Number of days to keep the containers' logs in CloudWatch. Not used when firelens_configuration is set."
+  type        = number
+  default     = 30
+}
+
+variable "firelens_configuration" {
+  description = "If set, add a FluentBit log router sidecar and route all container logs through it. destination is the FluentBit output plugin (e.g. http, kinesis_streams, datadog, splunk) and options are that plugin's settings."
+  type = object({
+    destination = string
+    options     = map(string)
+  })
+  default = null
+}
+
+variable "firelens_image" {
+  description = "The FluentBit image to use for the FireLens log router"
+  type        = string
+  default     = "public.ecr.aws/aws-observability/aws-for-fluent-bit:stable"
+}
+
+variable "firelens_memory_reservation" {
+  description = "The soft memory limit, in MB, for the FireLens log router. The task memory must leave room for it alongside the app containers."
+  type        = number
+  default     = 50
+}
+
+variable "create_dashboard" {
+  description = "If true, create a CloudWatch dashboard named after the service with its CPU, memory, request, error, latency, and healthy target metrics"
+  type        = bool
+  default     = false
+}
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
@@ -1,3 +1,20 @@
+# ---------------------------------------------------------------------------------------------------------------------
+# LOOK UP THE AVAILABILITY ZONES OF THE SUBNETS
+# Used to fail at plan time, instead of minutes into an apply, when the subnets don't span enough AZs.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_subnet" "selected" {
+  count = length(var.subnet_ids)
+  id    = var.subnet_ids[count.index]
+}
+
+locals {
+  availability_zones = distinct([for subnet in data.aws_subnet.selected : subnet.availability_zone])
+
+  # kms_key_id is the deprecated name of kms_key_arn
+  kms_key_arn = var.kms_key_arn != null ? var.kms_key_arn : var.kms_key_id
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE DB SUBNET GROUP
 # ---------------------------------------------------------------------------------------------------------------------
@@ -6,6 +23,13 @@ resource "aws_db_subnet_group" "postgresql" {
   name       = "${var.name}-subnet-group"
   subnet_ids = var.subnet_ids
 
+  lifecycle {
+    precondition {
+      condition     = length(local.availability_zones) >= 2
+      error_message = "subnet_ids span ${length(local.availability_zones)} availability zone(s) (${join(", ", local.availability_zones)}), but RDS DB subnet groups require subnets in at least 2 availability zones, even when multi_az = false. Add a subnet in another AZ."
+    }
+  }
+
   tags = merge(
     var.tags,
     {
@@ -25,7 +49,13 @@ resource "aws_db_instance" "postgresql" {
 
   db_name  = var.db_name != null ? var.db_name : replace(var.name, "-", "")
   username = var.master_username
-  password = var.master_password
+  password = var.manage_master_user_password ? null : var.master_password
+
+  # RDS keeps the password in Secrets Manager and rotates it without a rotation Lambda of our own
+  manage_master_user_password = var.manage_master_user_password ? true : null
+
+  # Users granted rds_iam log in with IAM auth tokens; the master user keeps its password
+  iam_database_authentication_enabled = var.iam_database_authentication_enabled
 
   instance_class    = var.instance_class
   allocated_storage = var.allocated_storage
@@ -37,7 +67,7 @@ resource "aws_db_instance" "postgresql" {
   backup_window                   = var.backup_window
   maintenance_window              = var.maintenance_window
   storage_encrypted               = var.storage_encrypted
-  kms_key_id                      = var.kms_key_id
+  kms_key_id                      = local.kms_key_arn
   max_allocated_storage           = var.max_allocated_storage
   enabled_cloudwatch_logs_exports = var.enabled_cloudwatch_logs_exports
 
@@ -59,6 +89,16 @@ resource "aws_db_instance" "postgresql" {
   # Parameter group for Django-optimized settings
   parameter_group_name = var.parameter_group_name != null ? var.parameter_group_name : aws_db_parameter_group.postgresql[0].name
 
+  # Point-in-time recovery from another instance's automated backups
+  dynamic "restore_to_point_in_time" {
+    for_each = var.restore_to_point_in_time != null ? [var.restore_to_point_in_time] : []
+    content {
+      source_db_instance_identifier = restore_to_point_in_time.value.source_db_instance_identifier
+      restore_time                  = restore_to_point_in_time.value.restore_time
+      use_latest_restorable_time    = restore_to_point_in_time.value.use_latest_restorable_time ? true : null
+    }
+  }
+
   tags = merge(
     var.tags,
     {
@@ -66,6 +106,74 @@ resource "aws_db_instance" "postgresql" {
       Environment = var.environment
     }
   )
+
+  lifecycle {
+    precondition {
+      condition     = var.manage_master_user_password == (var.master_password == null)
+      error_message = "Set exactly one of master_password and manage_master_user_password = true."
+    }
+
+    precondition {
+      condition     = !var.multi_az || length(local.availability_zones) >= 2
+      error_message = "multi_az = true requires subnet_ids in at least 2 availability zones, but they only span: ${join(", ", local.availability_zones)}. Add a subnet in another AZ or set multi_az = false."
+    }
+
+    precondition {
+      condition     = var.storage_encrypted || local.kms_key_arn == null
+      error_message = "kms_key_arn is set but storage_encrypted = false. Set storage_encrypted = true to encrypt with the key."
+    }
+
+    precondition {
+      condition     = var.max_allocated_storage == 0 || var.max_allocated_storage > var.allocated_storage
+      error_message = "max_allocated_storage (${var.max_allocated_storage} GB) must be greater than allocated_storage (${var.allocated_storage} GB), or 0 to disable storage autoscaling."
+    }
+
+    precondition {
+      condition     = var.read_replica_count == 0 || var.backup_retention_period > 0
+      error_message = "read_replica_count = ${var.read_replica_count} requires backup_retention_period > 0, since RDS only creates read replicas of instances with automated backups."
+    }
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE READ REPLICAS
+# Each replica has its own endpoint, so reads can be sent to it directly. Replicas share the primary's security group,
+# so whatever can reach the primary can reach them.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_db_instance" "replica" {
+  count = var.read_replica_count
+
+  identifier          = "${var.name}-replica-${count.index + 1}"
+  replicate_source_db = aws_db_instance.postgresql.identifier
+
+  # The engine, storage, encryption, and master credentials come from the primary
+  instance_class        = var.instance_class
+  storage_type          = var.storage_type
+  max_allocated_storage = var.max_allocated_storage
+
+  # Replicas are rebuilt from the primary, so they keep no backups of their own
+  backup_retention_period = 0
+  skip_final_snapshot     = true
+  maintenance_window      = var.maintenance_window
+  deletion_protection     = var.deletion_protection
+
+  performance_insights_enabled          = var.performance_insights_enabled
+  performance_insights_retention_period = var.performance_insights_retention_period
+  enabled_cloudwatch_logs_exports       = var.enabled_cloudwatch_logs_exports
+
+  vpc_security_group_ids = [aws_security_group.db.id]
+  parameter_group_name   = aws_db_instance.postgresql.parameter_group_name
+
+  iam_database_authentication_enabled = var.iam_database_authentication_enabled
+
+  tags = merge(
+    var.tags,
+    {
+      Name        = "${var.name}-replica-${count.index + 1}"
+      Environment = var.environment
+    }
+  )
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -116,6 +224,20 @@ resource "aws_db_parameter_group" "postgresql" {
     value = "1000" # Log queries slower than 1 second
   }
 
+  parameter {
+    name  = "statement_timeout"
+    value = var.statement_timeout
+  }
+
+  dynamic "parameter" {
+    for_each = var.enable_logical_replication ? [1] : []
+    content {
+      name         = "rds.logical_replication"
+      value        = "1"
+      apply_method = "pending-reboot" # Static parameter requires reboot
+    }
+  }
+
   tags = merge(
     var.tags,
     {
@@ -143,13 +265,70 @@ resource "aws_security_group" "db" {
   )
 }
 
-module "allow_outbound_all" {
+# RDS only answers connections allowed by the ingress rules, so the security group gets no egress unless
+# egress_cidr_blocks is set
+module "allow_outbound" {
   source = "../sg-rule"
+  count  = length(var.egress_cidr_blocks) > 0 ? 1 : 0
 
   security_group_id = aws_security_group.db.id
   type              = "egress"
   from_port         = 0
   to_port           = 0
   protocol          = "-1"
-  cidr_blocks       = ["0.0.0.0/0"]
+  cidr_blocks       = var.egress_cidr_blocks
+
+  allowed_regions = var.allowed_regions
+}
+
+moved {
+  from = module.allow_outbound_all
+  to   = module.allow_outbound[0]
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A CLOUDWATCH DASHBOARD
+# When create_dashboard is true, a dashboard named <name>-postgresql shows the instance's CPU, connections, free storage,
+# and read/write latency.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_dashboard" "postgresql" {
+  count          = var.create_dashboard ? 1 : 0
+  dashboard_name = "${var.name}-postgresql"
+
+  dashboard_body = jsonencode({
+    widgets = [
+      for widget in [
+        { title = "CPU utilization", stat = "Average", metrics = ["CPUUtilization"] },
+        { title = "Database connections", stat = "Maximum", metrics = ["DatabaseConnections"] },
+        { title = "Free storage space", stat = "Minimum", metrics = ["FreeStorageSpace"] },
+        { title = "Read and write latency", stat = "Average", metrics = ["ReadLatency", "WriteLatency"] },
+        ] : {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title   = widget.title
+          region  = data.aws_region.current.name
+          stat    = widget.stat
+          period  = 300
+          metrics = [for metric in widget.metrics : ["AWS/RDS", metric, "DBInstanceIdentifier", aws_db_instance.postgresql.identifier]]
+        }
+      }
+    ]
+  })
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENFORCE THE REGION ALLOWLIST
+# Fails the plan, before anything is created, if the provider points at a region outside allowed_regions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
 }
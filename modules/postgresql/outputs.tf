@@ -13,6 +13,16 @@ output "port" {
   value       = aws_db_instance.postgresql.port
 }
 
+output "allocated_storage" {
+  description = "The storage (GB) allocated to the instance, including any growth from storage autoscaling"
+  value       = aws_db_instance.postgresql.allocated_storage
+}
+
+output "max_allocated_storage" {
+  description = "The limit (GB) RDS can automatically scale storage to, or 0 when storage autoscaling is disabled"
+  value       = aws_db_instance.postgresql.max_allocated_storage
+}
+
 output "db_name" {
   description = "The name of the database"
   value       = aws_db_instance.postgresql.db_name
@@ -29,18 +39,58 @@ output "arn" {
   value       = aws_db_instance.postgresql.arn
 }
 
+output "replica_endpoints" {
+  description = "The connection endpoints (hostname:port) of the read replicas, in order. Empty when read_replica_count is 0."
+  value       = aws_db_instance.replica[*].endpoint
+}
+
 output "db_security_group_id" {
   description = "The ID of the security group attached to the database"
   value       = aws_security_group.db.id
 }
 
+output "kms_key_id" {
+  description = "The ARN of the KMS key encrypting the instance's storage (the AWS managed aws/rds key unless kms_key_arn is set), or null when storage_encrypted is false"
+  value       = var.storage_encrypted ? aws_db_instance.postgresql.kms_key_id : null
+}
+
 output "resource_id" {
   description = "The resource ID of the DB instance"
   value       = aws_db_instance.postgresql.resource_id
 }
 
 output "connection_string" {
-  description = "PostgreSQL connection string (DATABASE_URL format for Django)"
-  value       = "postgresql://${aws_db_instance.postgresql.username}:${var.master_password}@${aws_db_instance.postgresql.address}:${aws_db_instance.postgresql.port}/${aws_db_instance.postgresql.db_name}"
+  description = "PostgreSQL connection string (DATABASE_URL format for Django). Null when manage_master_user_password is true, since the password rotates; read it from master_user_secret_arn instead."
+  value       = var.manage_master_user_password ? null : "postgresql://${aws_db_instance.postgresql.username}:${var.master_password}@${aws_db_instance.postgresql.address}:${aws_db_instance.postgresql.port}/${aws_db_instance.postgresql.db_name}"
   sensitive   = true
 }
+
+output "iam_auth_enabled" {
+  description = "Whether users granted the rds_iam role can log in with IAM auth tokens"
+  value       = aws_db_instance.postgresql.iam_database_authentication_enabled
+}
+
+output "cloudwatch_log_group_names" {
+  description = "The CloudWatch log groups RDS exports the primary's logs to, keyed by log type. RDS creates each group the first time it publishes that log."
+  value       = { for log_type in var.enabled_cloudwatch_logs_exports : log_type => "/aws/rds/instance/${aws_db_instance.postgresql.identifier}/${log_type}" }
+}
+
+output "performance_insights_enabled" {
+  description = "Whether Performance Insights is enabled on the instance"
+  value       = aws_db_instance.postgresql.performance_insights_enabled
+}
+
+output "master_user_secret_arn" {
+  description = "The ARN of the Secrets Manager secret holding the master user's credentials, when manage_master_user_password is true"
+  value       = try(aws_db_instance.postgresql.master_user_secret[0].secret_arn, null)
+}
+
+output "additional_databases" {
+  description = "The extra databases to create on the instance, with their owner roles (null for the master user)"
+  value       = var.additional_databases
+}
+
+output "dashboard_name" {
+  description = "The name of the CloudWatch dashboard, when create_dashboard is true"
+  value       = try(aws_cloudwatch_dashboard.postgresql[0].dashboard_name, null)
+}
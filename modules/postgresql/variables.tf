@@ -36,15 +36,40 @@ variable "master_username" {
 }
 
 variable "master_password" {
-  description = "The password for the master user of the DB"
+  description = "The password for the master user of the DB. Must be at least 12 characters and contain an uppercase letter, a lowercase letter, and a digit or symbol. Required unless manage_master_user_password is true, in which case it must be null."
   type        = string
   sensitive   = true
+  default     = null
+
+  validation {
+    condition = var.master_password == null || try(
+      length(var.master_password) >= 12 &&
+      can(regex("[A-Z]", var.master_password)) &&
+      can(regex("[a-z]", var.master_password)) &&
+      can(regex("[^A-Za-z]", var.master_password)) &&
+      !can(regex("[/@\" ]", var.master_password)),
+      false
+    )
+    error_message = "master_password must be at least 12 characters, contain an uppercase letter, a lowercase letter, and a digit or symbol, and must not contain '/', '@', '\"', or spaces."
+  }
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Production Features
 # ---------------------------------------------------------------------------------------------------------------------
 
+variable "manage_master_user_password" {
+  description = "If set to true, RDS generates the master password, stores it in a Secrets Manager secret (see the master_user_secret_arn output), and rotates it every 7 days. master_password must then be null."
+  type        = bool
+  default     = false
+}
+
+variable "iam_database_authentication_enabled" {
+  description = "If set to true, database users granted the rds_iam role log in with short-lived IAM auth tokens instead of passwords. Token logins require TLS (sslmode=require)."
+  type        = bool
+  default     = false
+}
+
 variable "engine_version" {
   description = "The version of PostgreSQL to run. https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/CHAP_PostgreSQL.html#PostgreSQL.Concepts"
   type        = string
@@ -82,39 +107,65 @@ variable "maintenance_window" {
 }
 
 variable "storage_encrypted" {
-  description = "Specifies whether the DB instance is encrypted"
+  description = "Specifies whether the DB instance is encrypted. Can only be set at creation: changing it replaces the instance, so an unencrypted instance must be migrated through an encrypted snapshot copy instead."
   type        = bool
   default     = true
 }
 
+variable "kms_key_arn" {
+  description = "The ARN of a customer managed KMS key to encrypt storage with. If null, the AWS managed aws/rds key is used. Like storage_encrypted, it can only be set at creation."
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.kms_key_arn == null || can(regex("^arn:aws[a-z-]*:kms:", var.kms_key_arn))
+    error_message = "kms_key_arn must be a KMS key ARN, e.g. arn:aws:kms:us-east-1:123456789012:key/..."
+  }
+}
+
 variable "kms_key_id" {
-  description = "The ARN for the KMS encryption key. If not specified, AWS managed key is used."
+  description = "Deprecated: use kms_key_arn. Only used when kms_key_arn is null."
   type        = string
   default     = null
 }
 
 variable "max_allocated_storage" {
-  description = "The upper limit (GB) to which RDS can automatically scale storage. 0 = disabled. Recommended: allocated_storage * 5"
+  description = "The upper limit (GB) to which RDS can automatically scale storage. Must be greater than allocated_storage. 0 = disabled. Recommended: allocated_storage * 5"
   type        = number
   default     = 100
+
+  validation {
+    condition     = var.max_allocated_storage >= 0 && floor(var.max_allocated_storage) == var.max_allocated_storage
+    error_message = "max_allocated_storage must be a whole number of GB, or 0 to disable storage autoscaling."
+  }
 }
 
 variable "enabled_cloudwatch_logs_exports" {
-  description = "List of log types to export to CloudWatch Logs. Valid values: postgresql, upgrade."
+  description = "List of log types to export to CloudWatch Logs. Valid values: postgresql, upgrade, iam-db-auth-error. Set to [] to export nothing."
   type        = list(string)
   default     = ["postgresql", "upgrade"]
+
+  validation {
+    condition     = alltrue([for log_type in var.enabled_cloudwatch_logs_exports : contains(["postgresql", "upgrade", "iam-db-auth-error"], log_type)])
+    error_message = "enabled_cloudwatch_logs_exports may only contain postgresql, upgrade, and iam-db-auth-error."
+  }
 }
 
 variable "performance_insights_enabled" {
-  description = "Enable Performance Insights (useful for query optimization)"
+  description = "Enable Performance Insights (useful for query optimization). Not supported on every instance class, e.g. db.t3.micro."
   type        = bool
   default     = true
 }
 
 variable "performance_insights_retention_period" {
-  description = "The amount of time in days to retain Performance Insights data. Valid values: 7, 731"
+  description = "The amount of time in days to retain Performance Insights data. Valid values: 7 (free tier), a multiple of 31 up to 713, or 731"
   type        = number
   default     = 7
+
+  validation {
+    condition     = contains([7, 731], var.performance_insights_retention_period) || (var.performance_insights_retention_period % 31 == 0 && var.performance_insights_retention_period >= 31 && var.performance_insights_retention_period <= 713)
+    error_message = "performance_insights_retention_period must be 7, a multiple of 31 from 31 to 713, or 731."
+  }
 }
 
 variable "deletion_protection" {
@@ -175,6 +226,72 @@ variable "effective_cache_size" {
   default     = "131072" # 1GB
 }
 
+variable "statement_timeout" {
+  description = "PostgreSQL statement_timeout parameter (in ms). Statements running longer are cancelled, so one runaway query can't hold locks indefinitely. 0 disables the timeout. Applies to every session, including migrations; a session can raise it with SET statement_timeout."
+  type        = string
+  default     = "0"
+}
+
+variable "enable_logical_replication" {
+  description = "Set rds.logical_replication = 1. Required for RDS blue/green deployments and logical replication subscribers."
+  type        = bool
+  default     = false
+}
+
+variable "additional_databases" {
+  description = "Extra databases to create on the instance after it is provisioned, for consolidating several small services onto one instance. RDS only creates db_name itself, so these are created by running CREATE DATABASE as the master user (see README). Set owner to a role name to have that login role created and own the database, or to null to leave it owned by the master user."
+  type = list(object({
+    name  = string
+    owner = string
+  }))
+  default = []
+
+  validation {
+    condition     = alltrue([for db in var.additional_databases : can(regex("^[a-z_][a-z0-9_]{0,62}$", db.name))])
+    error_message = "additional_databases names must be lowercase PostgreSQL identifiers: letters, digits, and underscores, starting with a letter or underscore, at most 63 characters."
+  }
+
+  validation {
+    condition     = length(distinct([for db in var.additional_databases : db.name])) == length(var.additional_databases)
+    error_message = "additional_databases names must be unique."
+  }
+}
+
+variable "restore_to_point_in_time" {
+  description = "If set, create the instance by restoring source_db_instance_identifier, which must have automated backups, to a point in time. Set restore_time to an RFC 3339 UTC timestamp (e.g. 2025-01-01T12:00:00Z) and use_latest_restorable_time = false, or restore_time = null and use_latest_restorable_time = true. The restored instance keeps the source's databases and master username. Only takes effect on creation."
+  type = object({
+    source_db_instance_identifier = string
+    restore_time                  = string
+    use_latest_restorable_time    = bool
+  })
+  default = null
+
+  validation {
+    condition     = var.restore_to_point_in_time == null || try((var.restore_to_point_in_time.restore_time != null) != var.restore_to_point_in_time.use_latest_restorable_time, false)
+    error_message = "restore_to_point_in_time must set exactly one of restore_time and use_latest_restorable_time = true."
+  }
+
+  validation {
+    condition     = var.restore_to_point_in_time == null || try(var.restore_to_point_in_time.restore_time == null || can(formatdate("YYYY", var.restore_to_point_in_time.restore_time)), false)
+    error_message = "restore_to_point_in_time.restore_time must be an RFC 3339 timestamp, e.g. 2025-01-01T12:00:00Z."
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES - Read Replicas
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "read_replica_count" {
+  description = "The number of read replicas to create. Replicas use the primary's instance class, security group, and parameter group, and require backup_retention_period > 0."
+  type        = number
+  default     = 0
+
+  validation {
+    condition     = var.read_replica_count >= 0 && var.read_replica_count <= 15 && floor(var.read_replica_count) == var.read_replica_count
+    error_message = "read_replica_count must be a whole number from 0 to 15, the most RDS allows for PostgreSQL."
+  }
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Networking
 # ---------------------------------------------------------------------------------------------------------------------
@@ -189,6 +306,18 @@ variable "subnet_ids" {
   type        = list(string)
 }
 
+variable "egress_cidr_blocks" {
+  description = "CIDR blocks the security group may send outbound traffic to. Defaults to none."
+  type        = list(string)
+  default     = []
+}
+
+variable "create_dashboard" {
+  description = "If true, create a CloudWatch dashboard named <name>-postgresql with the instance's CPU, connection, storage, and latency metrics"
+  type        = bool
+  default     = false
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Tags
 # ---------------------------------------------------------------------------------------------------------------------
@@ -198,3 +327,14 @@ variable "tags" {
   type        = map(string)
   default     = {}
 }
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
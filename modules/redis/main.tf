@@ -1,3 +1,58 @@
+# ---------------------------------------------------------------------------------------------------------------------
+# LOOK UP THE AVAILABILITY ZONES OF THE SUBNETS
+# Used to fail at plan time, instead of minutes into an apply, when the subnets don't span enough AZs.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_subnet" "selected" {
+  count = length(var.subnet_ids)
+  id    = var.subnet_ids[count.index]
+}
+
+locals {
+  availability_zones = distinct([for subnet in data.aws_subnet.selected : subnet.availability_zone])
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENGINE DEFAULTS
+# ---------------------------------------------------------------------------------------------------------------------
+# Valkey and Redis use different version numbers and parameter group families, so derive sensible defaults per engine.
+
+locals {
+  default_engine_versions = {
+    redis  = "7.1"
+    valkey = "8.0"
+  }
+
+  default_parameter_group_families = {
+    redis  = "redis7"
+    valkey = "valkey8"
+  }
+
+  engine_version         = var.engine_version != null ? var.engine_version : local.default_engine_versions[var.engine]
+  parameter_group_family = var.parameter_group_family != null ? var.parameter_group_family : local.default_parameter_group_families[var.engine]
+
+  # In cluster mode each shard has its own primary and replicas, so failover and Multi-AZ depend on the shard size
+  nodes_per_shard = var.cluster_mode_enabled ? var.replicas_per_node_group + 1 : var.num_cache_clusters
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# READ THE AUTH TOKEN FROM SECRETS MANAGER
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_secretsmanager_secret_version" "auth_token" {
+  count = var.auth_token_enabled && var.auth_token_secret_arn != null ? 1 : 0
+
+  secret_id = var.auth_token_secret_arn
+}
+
+locals {
+  # A secret that isn't a JSON object with an auth_token key is taken to be the raw token
+  auth_token = length(data.aws_secretsmanager_secret_version.auth_token) > 0 ? try(
+    jsondecode(data.aws_secretsmanager_secret_version.auth_token[0].secret_string).auth_token,
+    data.aws_secretsmanager_secret_version.auth_token[0].secret_string,
+  ) : var.auth_token
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE A REDIS CLUSTER (ElastiCache)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -7,14 +62,16 @@ resource "aws_elasticache_replication_group" "redis" {
   description          = "Redis cluster for ${var.name}"
 
   # Engine configuration
-  engine               = "redis"
-  engine_version       = var.engine_version
+  engine               = var.engine
+  engine_version       = local.engine_version
   port                 = var.port
   parameter_group_name = var.parameter_group_name != null ? var.parameter_group_name : aws_elasticache_parameter_group.redis[0].name
 
-  # Node configuration
-  node_type          = var.node_type
-  num_cache_clusters = var.num_cache_clusters
+  # Node configuration. Cluster mode sizes the group by shards and replicas per shard instead of a total node count.
+  node_type               = var.node_type
+  num_cache_clusters      = var.cluster_mode_enabled ? null : var.num_cache_clusters
+  num_node_groups         = var.cluster_mode_enabled ? var.num_node_groups : null
+  replicas_per_node_group = var.cluster_mode_enabled ? var.replicas_per_node_group : null
 
   # Availability and failover
   automatic_failover_enabled = var.automatic_failover_enabled
@@ -24,7 +81,7 @@ resource "aws_elasticache_replication_group" "redis" {
   security_group_ids         = [aws_security_group.redis.id]
   at_rest_encryption_enabled = var.at_rest_encryption_enabled
   transit_encryption_enabled = var.transit_encryption_enabled
-  auth_token                 = var.auth_token_enabled ? var.auth_token : null
+  auth_token                 = var.auth_token_enabled ? local.auth_token : null
 
   # Subnet configuration
   subnet_group_name = aws_elasticache_subnet_group.redis.name
@@ -34,6 +91,7 @@ resource "aws_elasticache_replication_group" "redis" {
   snapshot_window            = var.snapshot_window
   snapshot_retention_limit   = var.snapshot_retention_limit
   auto_minor_version_upgrade = var.auto_minor_version_upgrade
+  apply_immediately          = var.apply_immediately
 
   # Notifications
   notification_topic_arn = var.notification_topic_arn
@@ -60,37 +118,85 @@ resource "aws_elasticache_replication_group" "redis" {
       Environment = var.environment
     }
   )
+
+  lifecycle {
+    precondition {
+      condition     = !var.multi_az_enabled || length(local.availability_zones) >= 2
+      error_message = "multi_az_enabled = true requires subnet_ids in at least 2 availability zones, but they only span: ${join(", ", local.availability_zones)}. Add a subnet in another AZ or set multi_az_enabled = false."
+    }
+
+    precondition {
+      condition     = !var.multi_az_enabled || local.nodes_per_shard >= 2
+      error_message = "multi_az_enabled = true requires num_cache_clusters >= 2 (replicas_per_node_group >= 1 in cluster mode) so a replica can run in a second AZ."
+    }
+
+    # ElastiCache only rejects this at create time, after the subnet and parameter groups already exist
+    precondition {
+      condition     = var.cluster_mode_enabled || !var.automatic_failover_enabled || var.num_cache_clusters >= 2
+      error_message = "automatic_failover_enabled = true requires num_cache_clusters >= 2 (the primary plus at least one replica to fail over to). Set automatic_failover_enabled = false for a single node."
+    }
+
+    precondition {
+      condition     = !var.cluster_mode_enabled || var.automatic_failover_enabled
+      error_message = "cluster_mode_enabled = true requires automatic_failover_enabled = true."
+    }
+
+    precondition {
+      condition     = !var.auth_token_enabled || ((var.auth_token == null) != (var.auth_token_secret_arn == null))
+      error_message = "auth_token_enabled = true requires exactly one of auth_token and auth_token_secret_arn."
+    }
+
+    precondition {
+      condition     = length(var.parameter_group_overrides) == 0 || var.parameter_group_name == null
+      error_message = "parameter_group_overrides only applies to the parameter group this module creates. Set the parameters in the group named by parameter_group_name instead."
+    }
+
+    precondition {
+      condition     = !var.appendonly || (var.automatic_failover_enabled && local.nodes_per_shard >= 2)
+      error_message = "appendonly = true requires automatic_failover_enabled = true and num_cache_clusters >= 2 (replicas_per_node_group >= 1 in cluster mode). ElastiCache has no AOF for this engine, so durability comes from failing over to a replica."
+    }
+  }
+}
+
+locals {
+  # Cluster mode has no primary endpoint; cluster-aware clients discover the shards through the configuration endpoint
+  endpoint_address = var.cluster_mode_enabled ? aws_elasticache_replication_group.redis.configuration_endpoint_address : aws_elasticache_replication_group.redis.primary_endpoint_address
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
 # CREATE PARAMETER GROUP FOR REDIS (Django-optimized)
 # ---------------------------------------------------------------------------------------------------------------------
 
+locals {
+  parameters = merge(
+    # Django session/cache optimization
+    {
+      "maxmemory-policy"  = var.maxmemory_policy
+      "timeout"           = var.timeout
+      "tcp-keepalive"     = "300"
+      "maxmemory-samples" = "5"
+    },
+    var.parameter_group_overrides,
+    # Must match the replication group's mode; ElastiCache rejects a cluster-mode group with a non-cluster parameter group
+    {
+      "cluster-enabled" = var.cluster_mode_enabled ? "yes" : "no"
+    },
+  )
+}
+
 resource "aws_elasticache_parameter_group" "redis" {
   count = var.parameter_group_name == null ? 1 : 0
 
   name   = "${var.name}-redis-pg"
-  family = var.parameter_group_family
-
-  # Django session/cache optimization
-  parameter {
-    name  = "maxmemory-policy"
-    value = var.maxmemory_policy
-  }
+  family = local.parameter_group_family
 
-  parameter {
-    name  = "timeout"
-    value = var.timeout
-  }
-
-  parameter {
-    name  = "tcp-keepalive"
-    value = "300"
-  }
+  dynamic "parameter" {
+    for_each = local.parameters
 
-  parameter {
-    name  = "maxmemory-samples"
-    value = "5"
+    content {
+      name  = parameter.key
+      value = parameter.value
+    }
   }
 
   tags = merge(
@@ -137,15 +243,25 @@ resource "aws_security_group" "redis" {
   )
 }
 
-module "allow_outbound_all" {
+# Replication and snapshots happen inside ElastiCache, not through this security group, so the cache needs no egress
+# unless egress_cidr_blocks is set
+module "allow_outbound" {
   source = "../sg-rule"
+  count  = length(var.egress_cidr_blocks) > 0 ? 1 : 0
 
   security_group_id = aws_security_group.redis.id
   type              = "egress"
   from_port         = 0
   to_port           = 0
   protocol          = "-1"
-  cidr_blocks       = ["0.0.0.0/0"]
+  cidr_blocks       = var.egress_cidr_blocks
+
+  allowed_regions = var.allowed_regions
+}
+
+moved {
+  from = module.allow_outbound_all
+  to   = module.allow_outbound[0]
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -177,3 +293,139 @@ resource "aws_cloudwatch_log_group" "redis_engine_log" {
     }
   )
 }
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE A CLOUDWATCH DASHBOARD
+# When create_dashboard is true, a dashboard named <name>-redis shows the CPU, memory, connections, and cache hit rate
+# of every node in the replication group.
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_cloudwatch_dashboard" "redis" {
+  count          = var.create_dashboard ? 1 : 0
+  dashboard_name = "${var.name}-redis"
+
+  dashboard_body = jsonencode({
+    widgets = [
+      for widget in [
+        { title = "Engine CPU utilization", stat = "Average", metric = "EngineCPUUtilization" },
+        { title = "Memory usage", stat = "Maximum", metric = "DatabaseMemoryUsagePercentage" },
+        { title = "Current connections", stat = "Maximum", metric = "CurrConnections" },
+        { title = "Cache hit rate", stat = "Average", metric = "CacheHitRate" },
+        ] : {
+        type   = "metric"
+        width  = 12
+        height = 6
+        properties = {
+          title   = widget.title
+          region  = data.aws_region.current.name
+          stat    = widget.stat
+          period  = 300
+          metrics = [for cluster_id in aws_elasticache_replication_group.redis.member_clusters : ["AWS/ElastiCache", widget.metric, "CacheClusterId", cluster_id]]
+        }
+      }
+    ]
+  })
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CREATE CLOUDWATCH ALARMS
+# ElastiCache only publishes metrics per node, so each alarm combines the metric of every node with metric math: the
+# busiest node for CPU and memory, the total for evictions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+locals {
+  node_count = var.cluster_mode_enabled ? var.num_node_groups * local.nodes_per_shard : var.num_cache_clusters
+
+  alarms = var.enable_alarms ? {
+    cpu = {
+      metric             = "EngineCPUUtilization"
+      stat               = "Average"
+      expression         = "MAX(METRICS())"
+      threshold          = var.cpu_utilization_threshold
+      evaluation_periods = 3
+      description        = "Engine CPU of a ${var.name} node above ${var.cpu_utilization_threshold}% for 15 minutes"
+    }
+    memory = {
+      metric             = "DatabaseMemoryUsagePercentage"
+      stat               = "Maximum"
+      expression         = "MAX(METRICS())"
+      threshold          = var.database_memory_usage_threshold
+      evaluation_periods = 1
+      description        = "Memory usage of a ${var.name} node above ${var.database_memory_usage_threshold}%"
+    }
+    evictions = {
+      metric             = "Evictions"
+      stat               = "Sum"
+      expression         = "SUM(METRICS())"
+      threshold          = var.evictions_threshold
+      evaluation_periods = 1
+      description        = "More than ${var.evictions_threshold} keys evicted from ${var.name} in 5 minutes"
+    }
+  } : {}
+}
+
+resource "aws_cloudwatch_metric_alarm" "redis" {
+  for_each = local.alarms
+
+  alarm_name          = "${var.name}-redis-${each.key}"
+  alarm_description   = each.value.description
+  comparison_operator = "GreaterThanThreshold"
+  threshold           = each.value.threshold
+  evaluation_periods  = each.value.evaluation_periods
+  alarm_actions       = var.alarm_actions
+  ok_actions          = var.alarm_actions
+
+  metric_query {
+    id          = "e1"
+    expression  = each.value.expression
+    label       = each.value.metric
+    return_data = true
+  }
+
+  dynamic "metric_query" {
+    for_each = { for i, cluster_id in sort(tolist(aws_elasticache_replication_group.redis.member_clusters)) : "m${i}" => cluster_id }
+
+    content {
+      id = metric_query.key
+
+      metric {
+        namespace   = "AWS/ElastiCache"
+        metric_name = each.value.metric
+        stat        = each.value.stat
+        period      = 300
+        dimensions = {
+          CacheClusterId = metric_query.value
+        }
+      }
+    }
+  }
+
+  tags = merge(
+    var.tags,
+    {
+      Name        = "${var.name}-redis-${each.key}"
+      Environment = var.environment
+    }
+  )
+
+  lifecycle {
+    precondition {
+      condition     = local.node_count <= 10
+      error_message = "enable_alarms = true supports at most 10 nodes, the most metrics a CloudWatch alarm can combine."
+    }
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENFORCE THE REGION ALLOWLIST
+# Fails the plan, before anything is created, if the provider points at a region outside allowed_regions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
+}
@@ -1,11 +1,26 @@
 output "primary_endpoint_address" {
-  description = "The address of the primary endpoint for the replication group (read/write)"
-  value       = aws_elasticache_replication_group.redis.primary_endpoint_address
+  description = "The address of the primary endpoint for the replication group (read/write). Null in cluster mode; use configuration_endpoint_address."
+  value       = var.cluster_mode_enabled ? null : aws_elasticache_replication_group.redis.primary_endpoint_address
 }
 
 output "reader_endpoint_address" {
-  description = "The address of the endpoint for the reader node in the replication group (read-only)"
-  value       = aws_elasticache_replication_group.redis.reader_endpoint_address
+  description = "The address of the reader endpoint, which spreads read-only connections across the replicas. Empty when num_cache_clusters is 1, since there are no replicas to read from, and null in cluster mode."
+  value       = var.cluster_mode_enabled ? null : (var.num_cache_clusters > 1 ? aws_elasticache_replication_group.redis.reader_endpoint_address : "")
+}
+
+output "engine" {
+  description = "The cache engine running on the replication group (redis or valkey)"
+  value       = aws_elasticache_replication_group.redis.engine
+}
+
+output "engine_version_actual" {
+  description = "The running engine version of the replication group"
+  value       = aws_elasticache_replication_group.redis.engine_version_actual
+}
+
+output "persistence_mode" {
+  description = "How writes are made durable: replicated (replica with automatic failover) or snapshot (periodic snapshots only)"
+  value       = var.appendonly ? "replicated" : "snapshot"
 }
 
 output "port" {
@@ -23,6 +38,11 @@ output "id" {
   value       = aws_elasticache_replication_group.redis.id
 }
 
+output "tls_enabled" {
+  description = "Whether the cluster requires TLS. Clients must connect with TLS (rediss://) when this is true, whether or not auth_token_enabled is set."
+  value       = aws_elasticache_replication_group.redis.transit_encryption_enabled
+}
+
 output "redis_security_group_id" {
   description = "The ID of the security group attached to the Redis cluster"
   value       = aws_security_group.redis.id
@@ -33,29 +53,59 @@ output "member_clusters" {
   value       = aws_elasticache_replication_group.redis.member_clusters
 }
 
+output "parameter_group_name" {
+  description = "The name of the parameter group the cluster uses, whether created by this module or passed in"
+  value       = aws_elasticache_replication_group.redis.parameter_group_name
+}
+
 output "configuration_endpoint_address" {
-  description = "The address of the replication group configuration endpoint (for cluster mode only)"
-  value       = aws_elasticache_replication_group.redis.configuration_endpoint_address
+  description = "The address of the replication group configuration endpoint, which cluster-aware clients connect to. Null unless cluster_mode_enabled is true."
+  value       = var.cluster_mode_enabled ? aws_elasticache_replication_group.redis.configuration_endpoint_address : null
 }
 
 output "redis_url" {
-  description = "Redis connection URL for Django CACHES configuration"
-  value       = "redis://${aws_elasticache_replication_group.redis.primary_endpoint_address}:${aws_elasticache_replication_group.redis.port}/0"
+  description = "Redis connection URL for Django CACHES configuration. Points at the configuration endpoint in cluster mode."
+  value       = "redis://${local.endpoint_address}:${aws_elasticache_replication_group.redis.port}/0"
 }
 
 output "redis_url_with_auth" {
   description = "Redis connection URL with AUTH token (if auth_token_enabled)"
-  value       = var.auth_token_enabled ? "redis://:${var.auth_token}@${aws_elasticache_replication_group.redis.primary_endpoint_address}:${aws_elasticache_replication_group.redis.port}/0" : null
+  value       = var.auth_token_enabled ? "redis://:${local.auth_token}@${local.endpoint_address}:${aws_elasticache_replication_group.redis.port}/0" : null
   sensitive   = true
 }
 
 output "celery_broker_url" {
-  description = "Redis connection URL formatted for Celery broker (uses DB 1 to avoid conflicts with cache)"
-  value       = "redis://${aws_elasticache_replication_group.redis.primary_endpoint_address}:${aws_elasticache_replication_group.redis.port}/1"
+  description = "Redis connection URL formatted for Celery broker (uses DB 1 to avoid conflicts with cache). Null in cluster mode, which only has DB 0."
+  value       = var.cluster_mode_enabled ? null : "redis://${local.endpoint_address}:${aws_elasticache_replication_group.redis.port}/1"
 }
 
 output "celery_broker_url_with_auth" {
-  description = "Redis connection URL formatted for Celery broker with AUTH token"
-  value       = var.auth_token_enabled ? "redis://:${var.auth_token}@${aws_elasticache_replication_group.redis.primary_endpoint_address}:${aws_elasticache_replication_group.redis.port}/1" : null
+  description = "Redis connection URL formatted for Celery broker with AUTH token. Null in cluster mode."
+  value       = var.auth_token_enabled && !var.cluster_mode_enabled ? "redis://:${local.auth_token}@${local.endpoint_address}:${aws_elasticache_replication_group.redis.port}/1" : null
   sensitive   = true
 }
+
+output "auth_token_secret_arn" {
+  description = "The ARN of the Secrets Manager secret the AUTH token was read from, when auth_token_secret_arn is set. Read the token from the secret; it is never output."
+  value       = var.auth_token_enabled ? var.auth_token_secret_arn : null
+}
+
+output "dashboard_name" {
+  description = "The name of the CloudWatch dashboard, when create_dashboard is true"
+  value       = try(aws_cloudwatch_dashboard.redis[0].dashboard_name, null)
+}
+
+output "cpu_alarm_arn" {
+  description = "The ARN of the engine CPU alarm, when enable_alarms is true"
+  value       = try(aws_cloudwatch_metric_alarm.redis["cpu"].arn, null)
+}
+
+output "memory_alarm_arn" {
+  description = "The ARN of the memory usage alarm, when enable_alarms is true"
+  value       = try(aws_cloudwatch_metric_alarm.redis["memory"].arn, null)
+}
+
+output "eviction_alarm_arn" {
+  description = "The ARN of the evictions alarm, when enable_alarms is true"
+  value       = try(aws_cloudwatch_metric_alarm.redis["evictions"].arn, null)
+}
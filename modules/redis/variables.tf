@@ -21,10 +21,21 @@ variable "subnet_ids" {
 # OPTIONAL VARIABLES - Cluster Configuration
 # ---------------------------------------------------------------------------------------------------------------------
 
+variable "engine" {
+  description = "The cache engine to run: redis or valkey. Valkey is wire-compatible with Redis clients and cheaper to run."
+  type        = string
+  default     = "redis"
+
+  validation {
+    condition     = contains(["redis", "valkey"], var.engine)
+    error_message = "engine must be one of: redis, valkey"
+  }
+}
+
 variable "engine_version" {
-  description = "The version of Redis to run. https://docs.aws.amazon.com/AmazonElastiCache/latest/red-ug/supported-engine-versions.html"
+  description = "The engine version to run. If null, defaults to 7.1 for redis and 8.0 for valkey. https://docs.aws.amazon.com/AmazonElastiCache/latest/red-ug/supported-engine-versions.html"
   type        = string
-  default     = "7.1"
+  default     = null
 }
 
 variable "port" {
@@ -34,9 +45,42 @@ variable "port" {
 }
 
 variable "num_cache_clusters" {
-  description = "Number of cache clusters (nodes). Minimum 2 for Multi-AZ. For production: 2 = 1 primary + 1 replica"
+  description = "Total number of nodes in the replication group, counting the primary: 1 = primary only, 3 = 1 primary + 2 read replicas. Minimum 2 for Multi-AZ or automatic failover."
+  type        = number
+  default     = 2
+
+  validation {
+    condition     = var.num_cache_clusters >= 1 && var.num_cache_clusters <= 6
+    error_message = "num_cache_clusters must be between 1 and 6 (1 primary and up to 5 replicas)."
+  }
+}
+
+variable "cluster_mode_enabled" {
+  description = "If true, shard the keyspace across num_node_groups node groups, each with replicas_per_node_group replicas, instead of one primary with num_cache_clusters - 1 replicas. Clients must use a cluster-aware client and the configuration_endpoint_address output; primary_endpoint_address is null in cluster mode."
+  type        = bool
+  default     = false
+}
+
+variable "num_node_groups" {
+  description = "The number of shards (node groups) when cluster_mode_enabled is true. Ignored otherwise."
   type        = number
   default     = 2
+
+  validation {
+    condition     = var.num_node_groups >= 1 && var.num_node_groups <= 500
+    error_message = "num_node_groups must be between 1 and 500."
+  }
+}
+
+variable "replicas_per_node_group" {
+  description = "The number of replicas in each shard when cluster_mode_enabled is true. Ignored otherwise."
+  type        = number
+  default     = 1
+
+  validation {
+    condition     = var.replicas_per_node_group >= 0 && var.replicas_per_node_group <= 5
+    error_message = "replicas_per_node_group must be between 0 and 5."
+  }
 }
 
 variable "environment" {
@@ -56,11 +100,17 @@ variable "automatic_failover_enabled" {
 }
 
 variable "multi_az_enabled" {
-  description = "Specifies whether Multi-AZ is enabled. Requires num_cache_clusters >= 2"
+  description = "Specifies whether Multi-AZ is enabled. Requires num_cache_clusters >= 2, or replicas_per_node_group >= 1 in cluster mode"
   type        = bool
   default     = true
 }
 
+variable "appendonly" {
+  description = "Request append-only-file durability. ElastiCache doesn't support AOF on Redis 2.8.22+ or Valkey, so this enforces the AWS-recommended equivalent instead: a replica with automatic failover, so acknowledged writes survive a primary node failure. When false, durability relies on snapshots only."
+  type        = bool
+  default     = false
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Security
 # ---------------------------------------------------------------------------------------------------------------------
@@ -78,18 +128,24 @@ variable "transit_encryption_enabled" {
 }
 
 variable "auth_token_enabled" {
-  description = "Whether to use Redis AUTH token for authentication. Required when transit_encryption_enabled = true"
+  description = "Whether to use Redis AUTH token for authentication. Requires transit_encryption_enabled = true, but TLS works without it"
   type        = bool
   default     = false
 }
 
 variable "auth_token" {
-  description = "The password used to access a password protected server. Required if auth_token_enabled = true"
+  description = "The password used to access a password protected server. Set this or auth_token_secret_arn when auth_token_enabled = true"
   type        = string
   sensitive   = true
   default     = null
 }
 
+variable "auth_token_secret_arn" {
+  description = "The ARN of a Secrets Manager secret holding the AUTH token, read at apply time. The secret can be the raw token or a JSON object with an auth_token key. Set this or auth_token when auth_token_enabled = true"
+  type        = string
+  default     = null
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Maintenance and Backups
 # ---------------------------------------------------------------------------------------------------------------------
@@ -110,6 +166,11 @@ variable "snapshot_retention_limit" {
   description = "The number of days to retain automatic snapshots. 0 = disabled. Recommended: 7 for production"
   type        = number
   default     = 7
+
+  validation {
+    condition     = var.snapshot_retention_limit >= 0 && var.snapshot_retention_limit <= 35
+    error_message = "snapshot_retention_limit must be between 0 (backups disabled) and 35 days, the ElastiCache maximum."
+  }
 }
 
 variable "auto_minor_version_upgrade" {
@@ -118,6 +179,12 @@ variable "auto_minor_version_upgrade" {
   default     = true
 }
 
+variable "apply_immediately" {
+  description = "If set to true, apply changes such as node type or engine version changes immediately, which can briefly disrupt clients. If false, ElastiCache holds them as pending modifications until the next maintenance_window."
+  type        = bool
+  default     = false
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Parameter Group (Django-optimized defaults)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -129,9 +196,9 @@ variable "parameter_group_name" {
 }
 
 variable "parameter_group_family" {
-  description = "The family of the parameter group (e.g. redis7, redis6.x)"
+  description = "The family of the parameter group (e.g. redis7, valkey8). If null, defaults to redis7 for redis and valkey8 for valkey."
   type        = string
-  default     = "redis7"
+  default     = null
 }
 
 variable "maxmemory_policy" {
@@ -146,6 +213,12 @@ variable "timeout" {
   default     = "300"
 }
 
+variable "parameter_group_overrides" {
+  description = "Parameters to set in the parameter group this module creates, on top of (and taking precedence over) its Django-optimized defaults, e.g. { \"maxmemory-policy\" = \"allkeys-lfu\" }. cluster-enabled is always derived from cluster_mode_enabled. Can't be combined with parameter_group_name."
+  type        = map(string)
+  default     = {}
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Monitoring
 # ---------------------------------------------------------------------------------------------------------------------
@@ -162,6 +235,42 @@ variable "log_retention_days" {
   default     = 7
 }
 
+variable "create_dashboard" {
+  description = "If true, create a CloudWatch dashboard named <name>-redis with the CPU, memory, connection, and cache hit rate metrics of every node"
+  type        = bool
+  default     = false
+}
+
+variable "enable_alarms" {
+  description = "If true, create CloudWatch alarms on engine CPU, memory usage, and evictions across all nodes. Setting it to false removes all of them."
+  type        = bool
+  default     = false
+}
+
+variable "cpu_utilization_threshold" {
+  description = "EngineCPUUtilization percentage of the busiest node that triggers the CPU alarm"
+  type        = number
+  default     = 75
+}
+
+variable "database_memory_usage_threshold" {
+  description = "DatabaseMemoryUsagePercentage of the fullest node that triggers the memory alarm. Past 100%, keys are evicted."
+  type        = number
+  default     = 80
+}
+
+variable "evictions_threshold" {
+  description = "Number of keys evicted across all nodes in 5 minutes that triggers the eviction alarm"
+  type        = number
+  default     = 1000
+}
+
+variable "alarm_actions" {
+  description = "ARNs (e.g. SNS topics) to notify when an alarm changes state"
+  type        = list(string)
+  default     = []
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - Tags
 # ---------------------------------------------------------------------------------------------------------------------
@@ -176,3 +285,20 @@ variable "vpc_id" {
   description = "VPC ID where the Redis cluster will be deployed"
   type        = string
 }
+
+variable "egress_cidr_blocks" {
+  description = "CIDR blocks the security group may send outbound traffic to. Defaults to none."
+  type        = list(string)
+  default     = []
+}
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
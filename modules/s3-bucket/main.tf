@@ -20,6 +20,86 @@ resource "aws_s3_bucket_versioning" "versioning" {
   }
 }
 
+# ---------------------------------------------------------------------------------------------------------------------
+# LIFECYCLE RULES (clean up storage nobody can see)
+# ---------------------------------------------------------------------------------------------------------------------
+# Parts of abandoned multipart uploads and old object versions are billed like any other object but never show up in
+# a listing, so without these rules they accumulate unnoticed.
+
+#
+# lifecycle_rules adds rules of its own, each scoped to a key prefix, e.g. to expire temporary uploads or move old
+# assets to cheaper storage. A bucket has a single lifecycle configuration, so they share this resource.
+
+locals {
+  cleanup_rule_enabled = var.abort_incomplete_multipart_upload_days > 0 || (var.enable_versioning && var.noncurrent_version_expiration_days > 0)
+}
+
+resource "aws_s3_bucket_lifecycle_configuration" "lifecycle" {
+  count  = local.cleanup_rule_enabled || length(var.lifecycle_rules) > 0 ? 1 : 0
+  bucket = aws_s3_bucket.bucket.id
+
+  dynamic "rule" {
+    for_each = local.cleanup_rule_enabled ? [1] : []
+    content {
+      id     = "cleanup"
+      status = "Enabled"
+
+      filter {}
+
+      dynamic "abort_incomplete_multipart_upload" {
+        for_each = var.abort_incomplete_multipart_upload_days > 0 ? [1] : []
+        content {
+          days_after_initiation = var.abort_incomplete_multipart_upload_days
+        }
+      }
+
+      dynamic "noncurrent_version_expiration" {
+        for_each = var.enable_versioning && var.noncurrent_version_expiration_days > 0 ? [1] : []
+        content {
+          noncurrent_days = var.noncurrent_version_expiration_days
+        }
+      }
+    }
+  }
+
+  dynamic "rule" {
+    for_each = var.lifecycle_rules
+    content {
+      id     = rule.value.id
+      status = "Enabled"
+
+      filter {
+        prefix = rule.value.prefix
+      }
+
+      dynamic "expiration" {
+        for_each = rule.value.expiration_days != null ? [1] : []
+        content {
+          days = rule.value.expiration_days
+        }
+      }
+
+      dynamic "noncurrent_version_expiration" {
+        for_each = rule.value.noncurrent_version_expiration_days != null ? [1] : []
+        content {
+          noncurrent_days = rule.value.noncurrent_version_expiration_days
+        }
+      }
+
+      dynamic "transition" {
+        for_each = rule.value.transitions
+        content {
+          days          = transition.value.days
+          storage_class = transition.value.storage_class
+        }
+      }
+    }
+  }
+
+  # Noncurrent version rules only apply once versioning is configured
+  depends_on = [aws_s3_bucket_versioning.versioning]
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # SERVER-SIDE ENCRYPTION (always enabled)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -35,6 +115,20 @@ resource "aws_s3_bucket_server_side_encryption_configuration" "encryption" {
   }
 }
 
+# ---------------------------------------------------------------------------------------------------------------------
+# OBJECT OWNERSHIP (ACLs disabled by default)
+# ---------------------------------------------------------------------------------------------------------------------
+# With BucketOwnerEnforced the bucket owns every object and S3 rejects any request that sets an ACL, so public read
+# access can only come from the bucket policy.
+
+resource "aws_s3_bucket_ownership_controls" "ownership" {
+  bucket = aws_s3_bucket.bucket.id
+
+  rule {
+    object_ownership = var.object_ownership
+  }
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # BLOCK PUBLIC ACCESS (disable for CDN buckets)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -66,47 +160,145 @@ resource "aws_s3_bucket_website_configuration" "website" {
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
-# BUCKET POLICY FOR PUBLIC READ (CDN buckets)
+# BUCKET POLICY: HTTPS ONLY AND PUBLIC READ (CDN buckets)
 # ---------------------------------------------------------------------------------------------------------------------
-# Note: Requires block_public_access = false to allow public bucket policies
+# When require_https is true, any request made without TLS is denied, whoever makes it (CIS AWS Foundations 2.1.1).
+#
+# When enable_public_read is true, anyone may read objects. This requires block_public_access = false to allow a public
+# bucket policy.
 #
-# IMPORTANT: S3 website endpoints only support HTTP, not HTTPS directly.
-# This policy allows public read access over HTTP for website hosting.
-# For production CDN use, Cloudflare sits in front and:
+# IMPORTANT: S3 website endpoints only support HTTP, not HTTPS, so with require_https they answer every request with a
+# 403. For production CDN use, Cloudflare sits in front and:
 #   1. Terminates HTTPS from end users
-#   2. Proxies requests to S3 over HTTP
+#   2. Proxies requests to the bucket's REST endpoint (bucket_regional_domain_name) over HTTPS
 #   3. Caches responses at the edge
-# Architecture: User --HTTPS--> Cloudflare --HTTP--> S3 Website Endpoint
+# Architecture: User --HTTPS--> Cloudflare --HTTPS--> S3 REST Endpoint
+# Proxying to the website endpoint over HTTP instead requires require_https = false.
 # ---------------------------------------------------------------------------------------------------------------------
 
-resource "aws_s3_bucket_policy" "public_read" {
-  count  = var.enable_public_read ? 1 : 0
+locals {
+  deny_insecure_transport_statement = {
+    Sid       = "DenyInsecureTransport"
+    Effect    = "Deny"
+    Principal = "*"
+    Action    = "s3:*"
+    Resource  = [aws_s3_bucket.bucket.arn, "${aws_s3_bucket.bucket.arn}/*"]
+    Condition = {
+      Bool = { "aws:SecureTransport" = "false" }
+    }
+  }
+
+  # Only the module's own distribution may read through Origin Access Control, so this doesn't make the bucket public
+  cloudfront_read_statement = var.enable_cloudfront ? {
+    Sid       = "AllowCloudFrontOAC"
+    Effect    = "Allow"
+    Principal = { Service = "cloudfront.amazonaws.com" }
+    Action    = "s3:GetObject"
+    Resource  = "${aws_s3_bucket.bucket.arn}/*"
+    Condition = {
+      StringEquals = { "AWS:SourceArn" = aws_cloudfront_distribution.cdn[0].arn }
+    }
+  } : null
+
+  public_read_statement = {
+    Sid       = "PublicReadGetObject"
+    Effect    = "Allow"
+    Principal = "*"
+    Action    = "s3:GetObject"
+    Resource  = "${aws_s3_bucket.bucket.arn}/*"
+  }
+
+  bucket_policy_statements = concat(
+    var.require_https ? [local.deny_insecure_transport_statement] : [],
+    var.enable_public_read ? [local.public_read_statement] : [],
+    var.enable_cloudfront ? [local.cloudfront_read_statement] : [],
+  )
+}
+
+resource "aws_s3_bucket_policy" "bucket" {
+  count  = length(local.bucket_policy_statements) > 0 ? 1 : 0
   bucket = aws_s3_bucket.bucket.id
 
   # Wait for public access block to be configured before applying policy
   depends_on = [aws_s3_bucket_public_access_block.public_access]
 
   policy = jsonencode({
-    Version = "2012-10-17"
-    Statement = [
-      {
-        Sid       = "PublicReadGetObject"
-        Effect    = "Allow"
-        Principal = "*"
-        Action    = "s3:GetObject"
-        Resource  = "${aws_s3_bucket.bucket.arn}/*"
-      }
-    ]
+    Version   = "2012-10-17"
+    Statement = local.bucket_policy_statements
   })
 
   lifecycle {
     precondition {
-      condition     = !var.block_public_access
+      condition     = !var.enable_public_read || !var.block_public_access
       error_message = "enable_public_read requires block_public_access = false"
     }
   }
 }
 
+moved {
+  from = aws_s3_bucket_policy.public_read
+  to   = aws_s3_bucket_policy.bucket
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# CLOUDFRONT DISTRIBUTION (CDN in front of a private bucket)
+# ---------------------------------------------------------------------------------------------------------------------
+# CloudFront signs its requests to the bucket's REST endpoint with Origin Access Control, and the bucket policy only
+# lets this distribution read, so the bucket keeps public access blocked and objects are only reachable through the CDN.
+
+resource "aws_cloudfront_origin_access_control" "bucket" {
+  count                             = var.enable_cloudfront ? 1 : 0
+  name                              = var.name
+  description                       = "Read access to s3://${var.name}"
+  origin_access_control_origin_type = "s3"
+  signing_behavior                  = "always"
+  signing_protocol                  = "sigv4"
+}
+
+resource "aws_cloudfront_distribution" "cdn" {
+  count               = var.enable_cloudfront ? 1 : 0
+  enabled             = true
+  comment             = var.name
+  default_root_object = var.website_index_document
+  price_class         = var.cloudfront_price_class
+
+  origin {
+    origin_id                = var.name
+    domain_name              = aws_s3_bucket.bucket.bucket_regional_domain_name
+    origin_access_control_id = aws_cloudfront_origin_access_control.bucket[0].id
+  }
+
+  default_cache_behavior {
+    target_origin_id       = var.name
+    viewer_protocol_policy = "redirect-to-https"
+    allowed_methods        = ["GET", "HEAD", "OPTIONS"]
+    cached_methods         = ["GET", "HEAD"]
+    compress               = true
+
+    # AWS managed CachingOptimized policy
+    cache_policy_id = "658327ea-f89d-4fab-a63d-7e88639e58f6"
+  }
+
+  restrictions {
+    geo_restriction {
+      restriction_type = "none"
+    }
+  }
+
+  viewer_certificate {
+    cloudfront_default_certificate = true
+  }
+
+  tags = var.tags
+
+  lifecycle {
+    precondition {
+      condition     = var.block_public_access && !var.enable_public_read
+      error_message = "enable_cloudfront serves objects through Origin Access Control, so it requires block_public_access = true and enable_public_read = false."
+    }
+  }
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # CORS CONFIGURATION (for CDN buckets serving assets to multiple domains)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -123,3 +315,64 @@ resource "aws_s3_bucket_cors_configuration" "cors" {
     max_age_seconds = var.cors_max_age_seconds
   }
 }
+
+# ---------------------------------------------------------------------------------------------------------------------
+# TRANSFER ACCELERATION (for large uploads, e.g. video or bundles, from clients far from the bucket's region)
+# ---------------------------------------------------------------------------------------------------------------------
+
+resource "aws_s3_bucket_accelerate_configuration" "acceleration" {
+  count  = var.enable_transfer_acceleration ? 1 : 0
+  bucket = aws_s3_bucket.bucket.id
+  status = "Enabled"
+
+  lifecycle {
+    precondition {
+      condition     = length(regexall("\\.", var.name)) == 0
+      error_message = "Transfer acceleration isn't supported for bucket names containing dots (${var.name})."
+    }
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# EVENT NOTIFICATIONS (e.g., trigger asset processing on upload)
+# ---------------------------------------------------------------------------------------------------------------------
+# The target queue/topic policy must allow s3.amazonaws.com to publish, otherwise AWS rejects the configuration.
+
+resource "aws_s3_bucket_notification" "notification" {
+  count  = var.notification_target_arn != null ? 1 : 0
+  bucket = aws_s3_bucket.bucket.id
+
+  dynamic "queue" {
+    for_each = can(regex("^arn:aws[a-z-]*:sqs:", var.notification_target_arn)) ? [1] : []
+    content {
+      queue_arn     = var.notification_target_arn
+      events        = var.notification_events
+      filter_prefix = var.notification_filter_prefix
+      filter_suffix = var.notification_filter_suffix
+    }
+  }
+
+  dynamic "topic" {
+    for_each = can(regex("^arn:aws[a-z-]*:sns:", var.notification_target_arn)) ? [1] : []
+    content {
+      topic_arn     = var.notification_target_arn
+      events        = var.notification_events
+      filter_prefix = var.notification_filter_prefix
+      filter_suffix = var.notification_filter_suffix
+    }
+  }
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENFORCE THE REGION ALLOWLIST
+# Fails the plan, before anything is created, if the provider points at a region outside allowed_regions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
+}
@@ -13,6 +13,11 @@ output "bucket_regional_domain_name" {
   value       = aws_s3_bucket.bucket.bucket_regional_domain_name
 }
 
+output "versioning_status" {
+  description = "The bucket's versioning status: Enabled, or Suspended when enable_versioning is false"
+  value       = aws_s3_bucket_versioning.versioning.versioning_configuration[0].status
+}
+
 output "website_endpoint" {
   description = "The website endpoint of the bucket (if website hosting is enabled)"
   value       = try(aws_s3_bucket_website_configuration.website[0].website_endpoint, null)
@@ -22,3 +27,23 @@ output "website_domain" {
   description = "The domain of the website endpoint (if website hosting is enabled)"
   value       = try(aws_s3_bucket_website_configuration.website[0].website_domain, null)
 }
+
+output "transfer_acceleration_endpoint" {
+  description = "The accelerated endpoint of the bucket (if transfer acceleration is enabled)"
+  value       = var.enable_transfer_acceleration ? "${aws_s3_bucket.bucket.bucket}.s3-accelerate.amazonaws.com" : null
+}
+
+output "cloudfront_domain_name" {
+  description = "The domain name of the CloudFront distribution (if enable_cloudfront is true)"
+  value       = try(aws_cloudfront_distribution.cdn[0].domain_name, null)
+}
+
+output "cloudfront_distribution_id" {
+  description = "The ID of the CloudFront distribution (if enable_cloudfront is true)"
+  value       = try(aws_cloudfront_distribution.cdn[0].id, null)
+}
+
+output "lifecycle_rule_count" {
+  description = "The number of lifecycle rules configured on the bucket, including the module's cleanup rule"
+  value       = length(var.lifecycle_rules) + (local.cleanup_rule_enabled ? 1 : 0)
+}
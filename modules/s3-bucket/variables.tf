@@ -29,6 +29,79 @@ variable "enable_versioning" {
   default     = true
 }
 
+variable "require_https" {
+  description = "If set to true, deny every request to the bucket that isn't made over HTTPS. The S3 website endpoint is HTTP-only, so it stops serving objects; put a CDN in front of the REST endpoint instead, or set this to false."
+  type        = bool
+  default     = true
+}
+
+variable "object_ownership" {
+  description = "Who owns objects uploaded to the bucket. BucketOwnerEnforced, the AWS recommendation, disables ACLs entirely so access is controlled by policies alone; the other values keep ACLs working for legacy clients."
+  type        = string
+  default     = "BucketOwnerEnforced"
+
+  validation {
+    condition     = contains(["BucketOwnerEnforced", "BucketOwnerPreferred", "ObjectWriter"], var.object_ownership)
+    error_message = "object_ownership must be BucketOwnerEnforced, BucketOwnerPreferred, or ObjectWriter."
+  }
+}
+
+variable "abort_incomplete_multipart_upload_days" {
+  description = "Delete the parts of multipart uploads that haven't completed this many days after they started. Set to 0 to keep them."
+  type        = number
+  default     = 7
+
+  validation {
+    condition     = var.abort_incomplete_multipart_upload_days >= 0 && floor(var.abort_incomplete_multipart_upload_days) == var.abort_incomplete_multipart_upload_days
+    error_message = "abort_incomplete_multipart_upload_days must be a whole number of days, or 0 to disable."
+  }
+}
+
+variable "noncurrent_version_expiration_days" {
+  description = "When versioning is enabled, delete object versions this many days after they are overwritten or deleted. Set to 0 to keep every version."
+  type        = number
+  default     = 90
+
+  validation {
+    condition     = var.noncurrent_version_expiration_days >= 0 && floor(var.noncurrent_version_expiration_days) == var.noncurrent_version_expiration_days
+    error_message = "noncurrent_version_expiration_days must be a whole number of days, or 0 to disable."
+  }
+}
+
+variable "lifecycle_rules" {
+  description = "Lifecycle rules for objects whose keys start with prefix (an empty prefix matches every object). Each rule can expire objects, expire noncurrent versions, and transition objects to STANDARD_IA or GLACIER after a number of days. These are added to the module's own cleanup rule."
+  type = list(object({
+    id                                 = string
+    prefix                             = optional(string, "")
+    expiration_days                    = optional(number)
+    noncurrent_version_expiration_days = optional(number)
+    transitions = optional(list(object({
+      days          = number
+      storage_class = string
+    })), [])
+  }))
+  default = []
+
+  validation {
+    condition     = length(distinct([for rule in var.lifecycle_rules : rule.id])) == length(var.lifecycle_rules) && !contains([for rule in var.lifecycle_rules : rule.id], "cleanup")
+    error_message = "Each lifecycle_rules id must be unique, and cleanup is reserved for the module's own rule."
+  }
+
+  validation {
+    condition     = alltrue([for rule in var.lifecycle_rules : rule.expiration_days != null || rule.noncurrent_version_expiration_days != null || length(rule.transitions) > 0])
+    error_message = "Each lifecycle rule must set expiration_days, noncurrent_version_expiration_days, or at least one transition."
+  }
+
+  validation {
+    condition = alltrue(flatten([
+      for rule in var.lifecycle_rules : [
+        for transition in rule.transitions : contains(["STANDARD_IA", "GLACIER"], transition.storage_class) && transition.days >= (transition.storage_class == "STANDARD_IA" ? 30 : 0)
+      ]
+    ]))
+    error_message = "Transitions must be to STANDARD_IA, after at least 30 days as S3 requires, or to GLACIER."
+  }
+}
+
 variable "tags" {
   description = "A map of tags to apply to the bucket"
   type        = map(string)
@@ -40,7 +113,7 @@ variable "tags" {
 # ---------------------------------------------------------------------------------------------------------------------
 
 variable "enable_website_hosting" {
-  description = "Enable static website hosting. Required for CDN buckets served via Cloudflare."
+  description = "Enable static website hosting. The website endpoint is HTTP-only, so it requires require_https = false to serve objects."
   type        = bool
   default     = false
 }
@@ -63,6 +136,23 @@ variable "enable_public_read" {
   default     = false
 }
 
+variable "enable_cloudfront" {
+  description = "Create a CloudFront distribution in front of the bucket that reads it through Origin Access Control. Requires block_public_access = true and enable_public_read = false."
+  type        = bool
+  default     = false
+}
+
+variable "cloudfront_price_class" {
+  description = "The CloudFront price class, which limits the edge locations used. PriceClass_100 serves North America and Europe."
+  type        = string
+  default     = "PriceClass_100"
+
+  validation {
+    condition     = contains(["PriceClass_100", "PriceClass_200", "PriceClass_All"], var.cloudfront_price_class)
+    error_message = "cloudfront_price_class must be PriceClass_100, PriceClass_200, or PriceClass_All."
+  }
+}
+
 # ---------------------------------------------------------------------------------------------------------------------
 # OPTIONAL VARIABLES - CORS Configuration (CDN)
 # ---------------------------------------------------------------------------------------------------------------------
@@ -110,3 +200,57 @@ variable "cors_max_age_seconds" {
   type        = number
   default     = 3600
 }
+
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES - Transfer Acceleration
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "enable_transfer_acceleration" {
+  description = "Enable S3 Transfer Acceleration so large objects can be uploaded via the s3-accelerate endpoint. Incurs additional per-GB charges."
+  type        = bool
+  default     = false
+}
+
+# ---------------------------------------------------------------------------------------------------------------------
+# OPTIONAL VARIABLES - Event Notifications
+# ---------------------------------------------------------------------------------------------------------------------
+
+variable "notification_target_arn" {
+  description = "ARN of an SQS queue or SNS topic to notify on bucket events. Set to null to disable notifications."
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.notification_target_arn == null || can(regex("^arn:aws[a-z-]*:(sqs|sns):", var.notification_target_arn))
+    error_message = "notification_target_arn must be an SQS queue or SNS topic ARN"
+  }
+}
+
+variable "notification_events" {
+  description = "S3 events that trigger a notification"
+  type        = list(string)
+  default     = ["s3:ObjectCreated:*"]
+}
+
+variable "notification_filter_prefix" {
+  description = "Only notify for object keys starting with this prefix"
+  type        = string
+  default     = null
+}
+
+variable "notification_filter_suffix" {
+  description = "Only notify for object keys ending with this suffix (e.g., '.jpg')"
+  type        = string
+  default     = null
+}
+
+variable "allowed_regions" {
+  description = "The AWS regions this module may create resources in. Planning against any other region fails, so data stays in approved regions."
+  type        = list(string)
+  default     = ["us-east-1", "us-west-2"]
+
+  validation {
+    condition     = length(var.allowed_regions) > 0
+    error_message = "allowed_regions must list at least one region."
+  }
+}
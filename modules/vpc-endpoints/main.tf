@@ -26,6 +26,8 @@ module "allow_https_from_vpc" {
   to_port           = 443
   protocol          = "tcp"
   cidr_blocks       = [var.vpc_cidr]
+
+  allowed_regions = var.allowed_regions
 }
 
 # Allow all outbound traffic
@@ -38,6 +40,8 @@ module "allow_outbound_all" {
   to_port           = 0
   protocol          = "-1"
   cidr_blocks       = ["0.0.0.0/0"]
+
+  allowed_regions = var.allowed_regions
 }
 
 # ---------------------------------------------------------------------------------------------------------------------
@@ -141,3 +145,17 @@ resource "aws_vpc_endpoint" "logs" {
     }
   )
 }
+
+# ---------------------------------------------------------------------------------------------------------------------
+# ENFORCE THE REGION ALLOWLIST
+# Fails the plan, before anything is created, if the provider points at a region outside allowed_regions.
+# ---------------------------------------------------------------------------------------------------------------------
+
+data "aws_region" "current" {
+  lifecycle {
+    postcondition {
+      condition     = contains(var.allowed_regions, self.name)
+      error_message = "Region ${self.name} is not an approved region for this module. Deploy to one of ${join(", ", var.allowed_regions)}, or add the region to allowed_regions once it has been approved for data residency."
+    }
+  }
+}
@@ -5,11 +5,27 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +48,9 @@ func TestDjangoIntegrationFull(t *testing.T) {
 	// Cleanup after test
 	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
 
+	// Keep the deploy output, plan, and container logs if anything below fails
+	defer helpers.CaptureLogsOnFailure(t, terraformOptions)()
+
 	// Deploy infrastructure
 	terraform.RunTerraformCommand(t, terraformOptions, "apply", "-auto-approve")
 
@@ -209,6 +228,156 @@ func testCORSHeaders(t *testing.T, client *http.Client, baseURL string) {
 	t.Logf("CORS test: OPTIONS request returned %d", resp.StatusCode)
 }
 
+// FeatureFlagsResponse represents the feature flags endpoint response
+type FeatureFlagsResponse struct {
+	Flags map[string]bool `json:"flags"`
+}
+
+// TestDjangoFeatureFlags verifies feature flags flow from the module variable to application behavior
+func TestDjangoFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	flagName := "new_dashboard"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"feature_flags": map[string]bool{
+				flagName: true,
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+
+	t.Run("FlagEnabled", func(t *testing.T) {
+		waitForFeatureFlag(t, client, url, flagName, true)
+	})
+
+	// Flip the flag via a redeploy; no code or image change
+	terraformOptions.Vars["feature_flags"] = map[string]bool{
+		flagName: false,
+	}
+	terraform.Apply(t, terraformOptions)
+
+	t.Run("FlagDisabledAfterRedeploy", func(t *testing.T) {
+		waitForFeatureFlag(t, client, url, flagName, false)
+	})
+}
+
+// ServerStatusResponse represents the server status endpoint response
+type ServerStatusResponse struct {
+	Workers           int `json:"workers"`
+	ConfiguredWorkers int `json:"configured_workers"`
+	CPUCount          int `json:"cpu_count"`
+}
+
+// TestDjangoWorkerCount verifies the Gunicorn worker count scales with the task CPU allocation
+func TestDjangoWorkerCount(t *testing.T) {
+	t.Parallel()
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"cpu":    256,
+			"memory": 512,
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+
+	// 256 CPU units = 0.25 vCPU, so 2 * 0.25 + 1 rounds down to a single worker
+	smallWorkers := waitForWorkerCount(t, client, url, 1)
+
+	// Scale up to 1 vCPU; no code or image change
+	terraformOptions.Vars["cpu"] = 1024
+	terraformOptions.Vars["memory"] = 2048
+	terraform.Apply(t, terraformOptions)
+
+	largeWorkers := waitForWorkerCount(t, client, url, 3)
+
+	assert.Greater(t, largeWorkers, smallWorkers, "Worker count should increase with the task CPU allocation")
+	t.Logf("✅ Workers scaled from %d at 256 CPU to %d at 1024 CPU", smallWorkers, largeWorkers)
+}
+
+// waitForWorkerCount polls the server status endpoint until the running worker count matches the expected value
+func waitForWorkerCount(t *testing.T, client *http.Client, baseURL string, want int) int {
+	statusURL := fmt.Sprintf("%s/api/status/", baseURL)
+	maxRetries := 36 // 3 minutes with 5-second intervals (covers the rolling deployment)
+	retryDelay := 5 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		resp, err := client.Get(statusURL)
+		if err == nil && resp.StatusCode == 200 {
+			var result ServerStatusResponse
+			decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+			resp.Body.Close()
+
+			if decodeErr == nil {
+				if result.Workers == want {
+					assert.Equal(t, want, result.ConfiguredWorkers, "Running workers should match the configured count")
+					t.Logf("✅ %d workers running after %d attempts (host reports %d CPUs)", want, i+1, result.CPUCount)
+					return result.Workers
+				}
+				t.Logf("%d workers running, waiting for %d... (attempt %d/%d)", result.Workers, want, i+1, maxRetries)
+			}
+		} else if resp != nil {
+			resp.Body.Close()
+		}
+
+		time.Sleep(retryDelay)
+	}
+
+	require.Fail(t, fmt.Sprintf("Worker count did not reach %d", want))
+	return 0
+}
+
+// waitForFeatureFlag polls the feature flags endpoint until the flag reports the expected value
+func waitForFeatureFlag(t *testing.T, client *http.Client, baseURL, flag string, want bool) {
+	flagsURL := fmt.Sprintf("%s/api/flags/", baseURL)
+	maxRetries := 36 // 3 minutes with 5-second intervals (covers the rolling deployment)
+	retryDelay := 5 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		resp, err := client.Get(flagsURL)
+		if err == nil && resp.StatusCode == 200 {
+			var result FeatureFlagsResponse
+			decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+			resp.Body.Close()
+
+			if decodeErr == nil {
+				if got, ok := result.Flags[flag]; ok && got == want {
+					t.Logf("✅ Feature flag %s=%v after %d attempts", flag, want, i+1)
+					return
+				}
+				t.Logf("Feature flag %s is %v, waiting for %v... (attempt %d/%d)", flag, result.Flags[flag], want, i+1, maxRetries)
+			}
+		} else if resp != nil {
+			resp.Body.Close()
+		}
+
+		time.Sleep(retryDelay)
+	}
+
+	t.Fatalf("Feature flag %s did not report %v within timeout", flag, want)
+}
+
 // TestDjangoContainerStartupTime measures container startup performance
 func TestDjangoContainerStartupTime(t *testing.T) {
 	t.Parallel()
@@ -221,11 +390,17 @@ func TestDjangoContainerStartupTime(t *testing.T) {
 	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
 
 	startTime := time.Now()
-	terraform.RunTerraformCommand(t, terraformOptions, "apply", "-auto-approve")
+	provisionTime := helpers.MeasureInfraProvisionTime(t, terraformOptions)
 
 	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
 	require.NoError(t, err)
 
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
 	// Wait for first successful health check
 	client := createHTTPClient()
 	healthURL := fmt.Sprintf("%s/health/live/", url)
@@ -245,12 +420,22 @@ func TestDjangoContainerStartupTime(t *testing.T) {
 	duration := time.Since(startTime)
 	t.Logf("⏱️  Container startup time: %s", duration)
 
+	// Measure the application's own startup separately from infrastructure provisioning, by timing a fresh task from
+	// RUNNING to HEALTHY
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: "us-east-1"})
+	warmStart := helpers.MeasureContainerWarmStart(t, sess, clusterName, serviceName, 5*time.Minute)
+
 	// Performance assertion: should start within 3 minutes
 	assert.Less(t, duration.Minutes(), 3.0, "Container should start within 3 minutes")
 
+	// The container itself (migrations, collectstatic, gunicorn boot) has a much tighter budget than the full deploy
+	assert.Less(t, warmStart.Seconds(), 90.0, "Container warm start should be under 90 seconds")
+
 	// Log performance metrics
 	t.Logf("Performance Metrics:")
 	t.Logf("  - Total startup time: %s", duration)
+	t.Logf("  - Infrastructure provisioning: %s", provisionTime)
+	t.Logf("  - Container warm start: %s (target: < 90 seconds)", warmStart)
 	t.Logf("  - Target: < 3 minutes")
 	t.Logf("  - Status: %s", func() string {
 		if duration.Minutes() < 2 {
@@ -261,3 +446,1275 @@ func TestDjangoContainerStartupTime(t *testing.T) {
 		return "⚠️  Slow"
 	}())
 }
+
+// TestServiceDependencyReachability verifies a running Django task can resolve and reach its PostgreSQL and Redis
+// endpoints over the in-VPC network path (routes, security groups, DNS), which external test clients can't exercise.
+// The ingress rules come from the stack's own sg-to-db-sg-rule unit, so a missing or wrong rule there fails the probe.
+func TestServiceDependencyReachability(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"enable_execute_command": true,
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+
+	// The Django container is named after the service
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	// The unit leaves the data store ingress rules to the sg-to-db-sg-rule unit, so apply it here as a stack would
+	dbRuleOptions := applyDataStoreRuleUnit(t, "../units/postgresql", 5432)
+	defer terraform.RunTerraformCommand(t, dbRuleOptions, "destroy", "-auto-approve")
+	redisRuleOptions := applyDataStoreRuleUnit(t, "../units/redis", 6379)
+	defer terraform.RunTerraformCommand(t, redisRuleOptions, "destroy", "-auto-approve")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	taskARN := helpers.WaitForECSExecAgentRunning(t, sess, clusterName, serviceName, 10*time.Minute)
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		probeDependencyFromTask(t, awsRegion, clusterName, taskARN, serviceName, "DATABASE_URL", 5432)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		probeDependencyFromTask(t, awsRegion, clusterName, taskARN, serviceName, "REDIS_URL", 6379)
+	})
+}
+
+// unitOutput reads an output from an already-applied dependency unit
+func unitOutput(t *testing.T, unitDir, output string) string {
+	value, err := terraform.RunTerraformCommandAndGetStdoutE(t, &terraform.Options{
+		TerraformDir:    unitDir,
+		TerraformBinary: "terragrunt",
+	}, "output", "-raw", output)
+	require.NoError(t, err, "Failed to read %s from %s", output, unitDir)
+	return value
+}
+
+// applyDataStoreRuleUnit applies the stack's sg-to-db-sg-rule unit, which lets the Django service reach the data store
+// unit in destUnitDir on port. Each rule gets its own copy of the unit, next to the other units so root.hcl still
+// resolves, so the rules don't share a state file; its values are written to terragrunt.values.hcl as a stack would.
+// The caller is responsible for destroying the returned options.
+func applyDataStoreRuleUnit(t *testing.T, destUnitDir string, port int) *terraform.Options {
+	unitsDir, err := filepath.Abs("../units")
+	require.NoError(t, err)
+	sourcePath, err := filepath.Abs("../units/django-fargate-stateful-service")
+	require.NoError(t, err)
+	destPath, err := filepath.Abs(destUnitDir)
+	require.NoError(t, err)
+
+	unitDir, err := files.CopyTerragruntFolderToDest(filepath.Join(unitsDir, "sg-to-db-sg-rule"), unitsDir, fmt.Sprintf("sg-to-db-sg-rule-%d-", port))
+	require.NoError(t, err, "Failed to copy the sg-to-db-sg-rule unit")
+	t.Cleanup(func() { os.RemoveAll(filepath.Dir(unitDir)) })
+
+	values := fmt.Sprintf("source_path = %q\ndest_path   = %q\nport        = %d\n", sourcePath, destPath, port)
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, "terragrunt.values.hcl"), []byte(values), 0o644))
+
+	options := &terraform.Options{
+		TerraformDir:    unitDir,
+		TerraformBinary: "terragrunt",
+	}
+	terraform.Apply(t, options)
+	return options
+}
+
+// probeDependencyFromTask opens a TCP connection from inside the task to the host in the given connection URL
+// environment variable, so the probe targets exactly the endpoint the app uses
+func probeDependencyFromTask(t *testing.T, region, clusterName, taskARN, containerName, urlEnvVar string, port int) {
+	// The image has no nc, so use the Python interpreter the app already ships with
+	probe := fmt.Sprintf(
+		`python -c "import os,socket,urllib.parse as p;u=p.urlparse(os.environ['%s']);socket.create_connection((u.hostname,u.port or %d),5);print('REACHABLE',u.hostname,u.port or %d)"`,
+		urlEnvVar, port, port,
+	)
+
+	var output string
+	var err error
+	for i := 0; i < 5; i++ {
+		output, err = helpers.RunECSExecCommandE(t, region, clusterName, taskARN, containerName, probe)
+		if err == nil && strings.Contains(output, "REACHABLE") {
+			break
+		}
+		t.Logf("Probe for %s failed (attempt %d/5): %v", urlEnvVar, i+1, err)
+		time.Sleep(10 * time.Second)
+	}
+
+	require.NoError(t, err, "ECS Exec probe for %s failed", urlEnvVar)
+	require.Contains(t, output, "REACHABLE", "Task could not connect to the %s endpoint", urlEnvVar)
+	assert.Contains(t, output, fmt.Sprintf(" %d", port), "%s should point at port %d", urlEnvVar, port)
+	t.Logf("✅ Task reached %s endpoint on port %d", urlEnvVar, port)
+}
+
+// TestECSErrorRateAlarm verifies the log-derived alerting pipeline: error log lines increment the metric filter's
+// metric and push the error rate alarm into ALARM
+func TestECSErrorRateAlarm(t *testing.T) {
+	t.Parallel()
+
+	errorThreshold := 5
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"create_error_rate_alarm":    true,
+			"error_rate_alarm_threshold": errorThreshold,
+			"feature_flags": map[string]bool{
+				"error_test_endpoint": true,
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	logGroupName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "cloudwatch_log_group_name")
+	require.NoError(t, err)
+
+	filterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "error_rate_metric_filter_name")
+	require.NoError(t, err)
+
+	metricName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "error_rate_metric_name")
+	require.NoError(t, err)
+
+	metricNamespace, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "error_rate_metric_namespace")
+	require.NoError(t, err)
+
+	alarmName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "error_rate_alarm_name")
+	require.NoError(t, err)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: "us-east-1"})
+	client := createHTTPClient()
+
+	t.Run("MetricFilterExists", func(t *testing.T) {
+		filter := helpers.AssertMetricFilterExists(t, sess, logGroupName, filterName)
+		assert.Equal(t, metricName, aws.StringValue(filter.MetricTransformations[0].MetricName))
+	})
+
+	t.Run("AlarmStartsOK", func(t *testing.T) {
+		waitForHealthyService(t, client, url)
+		helpers.WaitForAlarmState(t, sess, alarmName, cloudwatch.StateValueOk, 5*time.Minute)
+	})
+
+	// A second filter on the same log group, matching only the error endpoint's exception, checks the app's error
+	// lines reach CloudWatch independently of the module's pattern. Filters only count lines logged after they exist.
+	testFilterName := fmt.Sprintf("%s-deliberate", filterName)
+	helpers.CreateMetricFilter(t, sess, logGroupName, testFilterName, `"Deliberate error for alerting tests"`)
+	defer helpers.DeleteMetricFilter(t, sess, logGroupName, testFilterName)
+
+	// Generate more errors than the threshold within a single alarm period
+	errorURL := fmt.Sprintf("%s/api/debug/error/", url)
+	for i := 0; i < errorThreshold*3; i++ {
+		resp, err := client.Get(errorURL)
+		require.NoError(t, err, "Error endpoint should respond")
+		resp.Body.Close()
+		assert.Equal(t, 500, resp.StatusCode, "Error endpoint should return 500")
+	}
+
+	t.Run("MetricIncrements", func(t *testing.T) {
+		helpers.WaitForMetricSum(t, sess, metricNamespace, metricName, float64(errorThreshold), 5*time.Minute)
+	})
+
+	t.Run("TestFilterIncrements", func(t *testing.T) {
+		helpers.WaitForMetricSum(t, sess, helpers.MetricFilterNamespace, testFilterName, float64(errorThreshold), 5*time.Minute)
+	})
+
+	t.Run("AlarmFires", func(t *testing.T) {
+		helpers.WaitForAlarmState(t, sess, alarmName, cloudwatch.StateValueAlarm, 5*time.Minute)
+	})
+}
+
+// TestDjangoRequestTimeout verifies a request running longer than request_timeout is cut off by Gunicorn instead of
+// hanging and tying up a worker
+func TestDjangoRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	requestTimeout := 10
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"request_timeout": requestTimeout,
+			"feature_flags": map[string]bool{
+				"slow_test_endpoint": true,
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	t.Run("FastRequestSucceeds", func(t *testing.T) {
+		resp, err := client.Get(fmt.Sprintf("%s/api/debug/slow/?seconds=1", url))
+		require.NoError(t, err, "Request within the timeout should complete")
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("SlowRequestCutOff", func(t *testing.T) {
+		// Give the client far longer than the server timeout so only the server can end the request early
+		slowClient := createHTTPClient()
+		slowClient.Timeout = 2 * time.Minute
+
+		start := time.Now()
+		resp, err := slowClient.Get(fmt.Sprintf("%s/api/debug/slow/?seconds=90", url))
+		elapsed := time.Since(start)
+
+		if err == nil {
+			defer resp.Body.Close()
+			// The ALB reports the killed worker's dropped connection as a 502, or a 504 if it gave up first
+			assert.Contains(t, []int{502, 504}, resp.StatusCode, "Slow request should fail with a gateway error")
+		} else {
+			t.Logf("Connection closed by server: %v", err)
+		}
+
+		limit := time.Duration(requestTimeout)*time.Second + 15*time.Second
+		assert.Less(t, elapsed, limit, "Request should be cut off near the %ds timeout", requestTimeout)
+		assert.GreaterOrEqual(t, elapsed, time.Duration(requestTimeout)*time.Second, "Request should not be cut off before the timeout")
+		t.Logf("✅ Slow request cut off after %s (timeout %ds)", elapsed.Round(time.Second), requestTimeout)
+	})
+
+	t.Run("WorkerRecovers", func(t *testing.T) {
+		// Gunicorn replaces the killed worker, so the service keeps serving requests
+		waitForHealthyService(t, client, url)
+	})
+}
+
+// TestDjangoPoolExhaustion verifies that when more requests need a database connection than the pool allows, the
+// excess requests are turned away with a 503 and Retry-After rather than crashing with a 500, and that the service
+// serves normally again once the load subsides
+func TestDjangoPoolExhaustion(t *testing.T) {
+	t.Parallel()
+
+	// One worker with 8 threads but only 2 connection slots, so a burst of slow queries exhausts the pool quickly
+	poolSize := 2
+	poolTimeout := 2
+	queryDuration := 10
+	concurrentRequests := 12
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"desired_count":    1,
+			"gunicorn_workers": 1,
+			"gunicorn_threads": 8,
+			"db_pool_size":     poolSize,
+			"db_pool_timeout":  poolTimeout,
+			"feature_flags": map[string]bool{
+				"slow_test_endpoint": true,
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	t.Run("ExcessRequestsGet503", func(t *testing.T) {
+		slowClient := createHTTPClient()
+		slowClient.Timeout = 2 * time.Minute
+
+		var wg sync.WaitGroup
+		statuses := make([]int, concurrentRequests)
+		retryAfter := make([]string, concurrentRequests)
+
+		for i := 0; i < concurrentRequests; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, err := slowClient.Get(fmt.Sprintf("%s/api/debug/slow-query/?seconds=%d", url, queryDuration))
+				if err != nil {
+					t.Errorf("Request %d failed outright: %v", i, err)
+					return
+				}
+				defer resp.Body.Close()
+				statuses[i] = resp.StatusCode
+				retryAfter[i] = resp.Header.Get("Retry-After")
+			}(i)
+		}
+		wg.Wait()
+
+		counts := map[int]int{}
+		for i, status := range statuses {
+			counts[status]++
+			if status == 503 {
+				assert.NotEmpty(t, retryAfter[i], "503 responses should tell clients when to retry")
+			}
+		}
+		t.Logf("Status codes for %d concurrent slow queries: %v", concurrentRequests, counts)
+
+		for status, count := range counts {
+			assert.True(t, status == 200 || status == 503, "Unexpected status %d for %d requests; pool exhaustion should never crash", status, count)
+		}
+		assert.GreaterOrEqual(t, counts[200], 1, "Requests that got a connection slot should complete")
+		assert.LessOrEqual(t, counts[200], poolSize, "No more requests than the pool size should run concurrently")
+		assert.GreaterOrEqual(t, counts[503], 1, "Requests beyond the pool should be turned away with 503")
+		t.Logf("✅ %d requests completed and %d were turned away with 503", counts[200], counts[503])
+	})
+
+	t.Run("ServiceRecovers", func(t *testing.T) {
+		waitForHealthyService(t, client, url)
+
+		resp, err := client.Get(fmt.Sprintf("%s/api/debug/slow-query/?seconds=1", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode, "Database requests should succeed once the load subsides")
+
+		resp, err = client.Get(fmt.Sprintf("%s/health/ready/", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode, "Readiness should report the database as reachable")
+		t.Log("✅ Service recovered after the load subsided")
+	})
+}
+
+// TestDjangoCacheControl verifies through the ALB that dynamic responses carry the Cache-Control configured for their
+// path, so authenticated API responses are never cached and served to another user, while static assets are cacheable
+// for a long time
+func TestDjangoCacheControl(t *testing.T) {
+	t.Parallel()
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			// The flags endpoint is the same for every user, so it may be cached briefly; the first matching rule wins
+			"cache_control_rules": []map[string]string{
+				{"path_prefix": "/api/flags/", "cache_control": "public, max-age=60"},
+				{"path_prefix": "/api/", "cache_control": "no-store, private"},
+				{"path_prefix": "/admin/", "cache_control": "no-store, private"},
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	t.Run("APIResponsesNotCached", func(t *testing.T) {
+		resp, err := client.Get(fmt.Sprintf("%s/api/status/", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assertNotCacheable(t, resp)
+
+		// Error responses must not be cached either, or a CDN could serve one user's 401 to everyone
+		body, _ := json.Marshal(map[string]string{"username": "invalid", "password": "invalid"})
+		resp, err = client.Post(fmt.Sprintf("%s/api/token/", url), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assertNotCacheable(t, resp)
+	})
+
+	t.Run("PerPathRule", func(t *testing.T) {
+		resp, err := client.Get(fmt.Sprintf("%s/api/flags/", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("StaticAssetsLongLived", func(t *testing.T) {
+		// Static file names contain a content hash, so find one by reading a page that links to it
+		resp, err := client.Get(fmt.Sprintf("%s/admin/login/", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, 200, resp.StatusCode)
+		assertNotCacheable(t, resp)
+
+		page, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		match := regexp.MustCompile(`href="(/static/[^"]+\.css)"`).FindSubmatch(page)
+		require.NotNil(t, match, "Admin login page should link to a stylesheet")
+
+		assetResp, err := client.Get(url + string(match[1]))
+		require.NoError(t, err)
+		defer assetResp.Body.Close()
+		require.Equal(t, 200, assetResp.StatusCode)
+
+		cacheControl := assetResp.Header.Get("Cache-Control")
+		assert.Contains(t, cacheControl, "public")
+		assert.GreaterOrEqual(t, cacheMaxAge(t, cacheControl), 365*24*60*60, "Static assets should be cacheable for at least a year")
+		t.Logf("✅ %s served with Cache-Control: %s", match[1], cacheControl)
+	})
+}
+
+// assertNotCacheable fails unless the response forbids both shared and browser caches from storing it
+func assertNotCacheable(t *testing.T, resp *http.Response) {
+	t.Helper()
+
+	cacheControl := resp.Header.Get("Cache-Control")
+	assert.Contains(t, cacheControl, "no-store", "%s should not be stored by any cache", resp.Request.URL.Path)
+	assert.Contains(t, cacheControl, "private", "%s should not be stored by shared caches", resp.Request.URL.Path)
+	t.Logf("%s (%d) served with Cache-Control: %s", resp.Request.URL.Path, resp.StatusCode, cacheControl)
+}
+
+// cacheMaxAge returns the max-age directive of a Cache-Control header
+func cacheMaxAge(t *testing.T, cacheControl string) int {
+	t.Helper()
+
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			maxAge, err := strconv.Atoi(value)
+			require.NoError(t, err, "Invalid max-age in %q", cacheControl)
+			return maxAge
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("No max-age in Cache-Control %q", cacheControl))
+	return 0
+}
+
+// TestDjangoMetricsEndpoint verifies the Prometheus metrics endpoint serves request counters and database connection
+// gauges to scrapers inside the VPC, and can't be reached through the internet-facing ALB
+func TestDjangoMetricsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	metricsPath := "/metrics/"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"metrics_enabled":        true,
+			"metrics_path":           metricsPath,
+			"enable_execute_command": true,
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	// The Django container is named after the service
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	t.Run("NotPublic", func(t *testing.T) {
+		for _, path := range []string{metricsPath, strings.TrimSuffix(metricsPath, "/")} {
+			resp, err := client.Get(url + path)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode, "%s should not be served through the ALB", path)
+			assert.NotContains(t, string(body), "django_http_requests_total", "%s leaked metrics through the ALB", path)
+		}
+		t.Logf("✅ %s is not reachable through the ALB", metricsPath)
+	})
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	taskARN := helpers.WaitForECSExecAgentRunning(t, sess, clusterName, serviceName, 10*time.Minute)
+
+	t.Run("ScrapeFromInsideVPC", func(t *testing.T) {
+		// Hit a database-backed endpoint first so the worker has counted a request and opened a connection
+		status, _ := getFromTask(t, awsRegion, clusterName, taskARN, serviceName, "/health/ready/", false)
+		require.Equal(t, http.StatusOK, status)
+
+		status, metrics := getFromTask(t, awsRegion, clusterName, taskARN, serviceName, metricsPath, false)
+		require.Equal(t, http.StatusOK, status, "Metrics endpoint should be served inside the VPC")
+
+		assert.Contains(t, metrics, "# TYPE django_http_requests_total counter")
+		assert.Regexp(t, `django_http_requests_total\{method="GET",status="200",pid="\d+"\} \d+`, metrics)
+		assert.Contains(t, metrics, "# TYPE django_db_connections_open gauge")
+		assert.Regexp(t, `django_db_connections_open\{alias="default",pid="\d+"\} [01]`, metrics)
+		t.Log("✅ Metrics endpoint serves request counters and database connection gauges")
+	})
+
+	t.Run("ForwardedRequestsRefused", func(t *testing.T) {
+		// Even without the ALB rule, Django refuses requests the ALB relayed
+		status, body := getFromTask(t, awsRegion, clusterName, taskARN, serviceName, metricsPath, true)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.NotContains(t, body, "django_http_requests_total")
+		t.Log("✅ Requests relayed by the ALB are refused")
+	})
+}
+
+// taskResponseStatus matches the status line getFromTask has curl print after the response body
+var taskResponseStatus = regexp.MustCompile(`HTTP_STATUS (\d+)`)
+
+// getFromTask requests path from Gunicorn inside the task, bypassing the ALB, and returns the status code and output.
+// With forwarded set, the request carries the X-Forwarded-For header the ALB adds.
+func getFromTask(t *testing.T, region, clusterName, taskARN, containerName, path string, forwarded bool) (int, string) {
+	t.Helper()
+
+	header := ""
+	if forwarded {
+		header = `-H "X-Forwarded-For: 203.0.113.1" `
+	}
+	command := fmt.Sprintf(`curl -s %s-w "\nHTTP_STATUS %%{http_code}\n" http://localhost:8000%s`, header, path)
+
+	var output string
+	var err error
+	for i := 0; i < 5; i++ {
+		output, err = helpers.RunECSExecCommandE(t, region, clusterName, taskARN, containerName, command)
+		if err == nil && taskResponseStatus.MatchString(output) {
+			break
+		}
+		t.Logf("Request for %s from inside the task failed (attempt %d/5): %v", path, i+1, err)
+		time.Sleep(10 * time.Second)
+	}
+	require.NoError(t, err, "ECS Exec request for %s failed", path)
+
+	match := taskResponseStatus.FindStringSubmatch(output)
+	require.NotNil(t, match, "No response status in ECS Exec output: %s", output)
+	status, err := strconv.Atoi(match[1])
+	require.NoError(t, err)
+
+	return status, output
+}
+
+// TestDjangoRedisOptional verifies Redis is an optional cache: when the service can no longer reach Redis, cache
+// misses fall through to the database and requests keep succeeding, only slower, instead of failing with 500s
+func TestDjangoRedisOptional(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	// The unit leaves the data store ingress rules to the sg-to-db-sg-rule unit, so apply it here as a stack would
+	dbRuleOptions := applyDataStoreRuleUnit(t, "../units/postgresql", 5432)
+	defer terraform.RunTerraformCommand(t, dbRuleOptions, "destroy", "-auto-approve")
+	redisRuleOptions := applyDataStoreRuleUnit(t, "../units/redis", 6379)
+	defer terraform.RunTerraformCommand(t, redisRuleOptions, "destroy", "-auto-approve")
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	var cachedLatency time.Duration
+
+	t.Run("ServedFromCache", func(t *testing.T) {
+		// The first request misses and fills the cache, after which the value comes from Redis
+		source := ""
+		for i := 0; i < 10 && source != "cache"; i++ {
+			var status int
+			status, source, cachedLatency = getCachedQuery(t, client, url)
+			require.Equal(t, http.StatusOK, status)
+		}
+		require.Equal(t, "cache", source, "The cache-backed endpoint never served from Redis")
+		t.Logf("✅ Cache hit served in %s", cachedLatency.Round(time.Millisecond))
+	})
+
+	// Security groups don't interrupt connections that are already open, so replace the tasks after removing the rule
+	// to make sure nothing still holds a connection to Redis
+	t.Log("Removing the Redis ingress rule to make Redis unreachable...")
+	terraform.Destroy(t, redisRuleOptions)
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	helpers.RedeployECSService(t, sess, clusterName, serviceName, 10*time.Minute)
+	waitForHealthyService(t, client, url)
+
+	t.Run("DegradesToDatabase", func(t *testing.T) {
+		var slowest time.Duration
+		for i := 0; i < 10; i++ {
+			status, source, latency := getCachedQuery(t, client, url)
+			require.Equal(t, http.StatusOK, status, "A cache outage should not fail requests")
+			assert.Equal(t, "database", source, "With Redis unreachable, every read should fall through to the database")
+			if latency > slowest {
+				slowest = latency
+			}
+		}
+		t.Logf("✅ Requests fell through to the database; slowest took %s (cache hit took %s)",
+			slowest.Round(time.Millisecond), cachedLatency.Round(time.Millisecond))
+	})
+
+	t.Run("ReadyWithoutCache", func(t *testing.T) {
+		resp, err := client.Get(fmt.Sprintf("%s/health/ready/", url))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "Readiness should not depend on the cache")
+
+		var result struct {
+			Checks map[string]bool `json:"checks"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.True(t, result.Checks["database"])
+		assert.False(t, result.Checks["cache"], "Readiness should report the cache as unavailable")
+	})
+
+	t.Run("CacheResumes", func(t *testing.T) {
+		t.Log("Restoring the Redis ingress rule...")
+		terraform.Apply(t, redisRuleOptions)
+
+		source := ""
+		for i := 0; i < 12 && source != "cache"; i++ {
+			time.Sleep(5 * time.Second)
+			var status int
+			status, source, _ = getCachedQuery(t, client, url)
+			require.Equal(t, http.StatusOK, status)
+		}
+		require.Equal(t, "cache", source, "The app did not go back to the cache once Redis was reachable")
+		t.Log("✅ The app reconnected to Redis without a restart")
+	})
+}
+
+// getCachedQuery requests the cache-backed endpoint and returns the status code, where the value came from (cache or
+// database), and how long the request took
+func getCachedQuery(t *testing.T, client *http.Client, baseURL string) (int, string, time.Duration) {
+	t.Helper()
+
+	start := time.Now()
+	resp, err := client.Get(fmt.Sprintf("%s/api/cached/", baseURL))
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result struct {
+		Source string `json:"source"`
+	}
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	}
+
+	return resp.StatusCode, result.Source, elapsed
+}
+
+// TestDjangoCSRFProtection verifies CSRF middleware is active in the deployed configuration by posting to the admin
+// login form, which authenticates with a session and is CSRF protected. A POST without a token, or with a token that
+// doesn't match the csrftoken cookie, is rejected with a 403; with the token from the GET that set the cookie it gets
+// through to the view.
+func TestDjangoCSRFProtection(t *testing.T) {
+	t.Parallel()
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	// The login page sets the csrftoken cookie. It is sent back explicitly rather than through a cookie jar, since
+	// CSRF_COOKIE_SECURE would keep a jar from returning it over plain HTTP.
+	resp, err := client.Get(fmt.Sprintf("%s/admin/login/", url))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var csrfCookie *http.Cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "csrftoken" {
+			csrfCookie = cookie
+		}
+	}
+	require.NotNil(t, csrfCookie, "GET /admin/login/ should set the csrftoken cookie")
+
+	t.Run("RejectedWithoutToken", func(t *testing.T) {
+		status, body := postAdminLogin(t, client, url, csrfCookie, "")
+		assert.Equal(t, http.StatusForbidden, status, "A POST without a CSRF token should be rejected")
+		assert.Contains(t, body, "CSRF", "The 403 should come from the CSRF check")
+		t.Log("✅ POST without a CSRF token rejected")
+	})
+
+	t.Run("RejectedWithMismatchedToken", func(t *testing.T) {
+		status, _ := postAdminLogin(t, client, url, csrfCookie, strings.Repeat("a", 64))
+		assert.Equal(t, http.StatusForbidden, status, "A POST with a token that doesn't match the cookie should be rejected")
+		t.Log("✅ POST with a mismatched CSRF token rejected")
+	})
+
+	t.Run("AcceptedWithToken", func(t *testing.T) {
+		// The credentials are wrong, so the view re-renders the form with a 200; that it ran at all means the CSRF
+		// check passed
+		status, body := postAdminLogin(t, client, url, csrfCookie, csrfCookie.Value)
+		assert.Equal(t, http.StatusOK, status, "A POST with a valid CSRF token should reach the login view")
+		assert.NotContains(t, body, "CSRF verification failed")
+		t.Log("✅ POST with a valid CSRF token accepted")
+	})
+}
+
+// postAdminLogin posts invalid credentials to the admin login form with the given csrftoken cookie and, unless token is
+// empty, the given CSRF token, and returns the status code and body. Django checks the Referer of HTTPS requests
+// against the host, so it is set to the login page as a browser would.
+func postAdminLogin(t *testing.T, client *http.Client, baseURL string, csrfCookie *http.Cookie, token string) (int, string) {
+	t.Helper()
+
+	loginURL := fmt.Sprintf("%s/admin/login/", baseURL)
+	form := url.Values{"username": {"invalid"}, "password": {"invalid"}}
+	if token != "" {
+		form.Set("csrfmiddlewaretoken", token)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, loginURL, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", loginURL)
+	req.AddCookie(&http.Cookie{Name: csrfCookie.Name, Value: csrfCookie.Value})
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+// TestDjangoMigrationGraceInteraction deploys the service with migrations slowed down well past the default container
+// health check start period, and verifies the ECS grace period and container start period keep the first task alive
+// until migrations finish and the app is ready. If either is too short, ECS kills the task mid-migration and the
+// service never becomes reachable, replacing tasks in a loop instead.
+func TestDjangoMigrationGraceInteraction(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	migrationDelay := 150 * time.Second
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			// Migrations, collectstatic, and Gunicorn boot must all fit in both windows
+			"health_check_grace_period_seconds":   300,
+			"container_health_check_start_period": 240,
+			"additional_environment_variables": map[string]string{
+				"MIGRATION_DELAY_SECONDS": strconv.Itoa(int(migrationDelay.Seconds())),
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	// waitForHealthyService gives up after 3 minutes, which a slow migration alone nearly uses up
+	client := createHTTPClient()
+	helpers.WaitForCondition(t, helpers.RetryConfig{
+		MaxRetries:    120,
+		RetryInterval: 5 * time.Second,
+		Description:   "service ready after slow migration",
+	}, func() bool {
+		resp, err := client.Get(fmt.Sprintf("%s/health/ready/", url))
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, "%s/health/ready/ to return 200", url)
+	readyAt := time.Now()
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("NoCrashLoop", func(t *testing.T) {
+		stopped := helpers.ListStoppedECSTasks(t, sess, clusterName, serviceName)
+		for _, task := range stopped {
+			t.Logf("Task %s stopped: %s", aws.StringValue(task.TaskArn), aws.StringValue(task.StoppedReason))
+		}
+		assert.Empty(t, stopped, "No task should have been killed while its migrations ran")
+	})
+
+	t.Run("MigrationsActuallySlow", func(t *testing.T) {
+		// The task that is serving must have waited out the delay, or the test didn't exercise a slow start at all
+		ecsClient := ecs.New(sess)
+		tasks, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+			Cluster:     aws.String(clusterName),
+			ServiceName: aws.String(serviceName),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, tasks.TaskArns, "The service should have a running task")
+
+		described, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{Cluster: aws.String(clusterName), Tasks: tasks.TaskArns})
+		require.NoError(t, err)
+		for _, task := range described.Tasks {
+			startup := readyAt.Sub(aws.TimeValue(task.StartedAt))
+			assert.GreaterOrEqual(t, startup, migrationDelay, "Task %s became ready before its migrations could have finished", aws.StringValue(task.TaskArn))
+			t.Logf("✅ Task %s survived a %s startup (migrations delayed %s)", aws.StringValue(task.TaskArn), startup.Round(time.Second), migrationDelay)
+		}
+	})
+}
+
+// TestDjangoDeploymentRollback deploys an image tag that doesn't exist and verifies the deployment circuit breaker
+// rolls the service back to the exact task definition revision it ran before, rather than registering a new revision
+// with the old config, so the audit trail shows a rollback and no revisions are churned
+func TestDjangoDeploymentRollback(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars:            map[string]interface{}{},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	priorARN := helpers.GetServiceTaskDefinitionARN(t, sess, clusterName, serviceName)
+	t.Logf("Service %s runs %s before the bad deploy", serviceName, priorARN)
+
+	// Every task of the new revision fails to pull its image, which trips the circuit breaker
+	terraformOptions.Vars["image_tag"] = fmt.Sprintf("does-not-exist-%d", time.Now().Unix())
+	terraform.Apply(t, terraformOptions)
+
+	failedARN := helpers.GetServiceTaskDefinitionARN(t, sess, clusterName, serviceName)
+	require.NotEqual(t, priorARN, failedARN, "The bad deploy should have registered a new task definition revision")
+
+	rollback := helpers.WaitForECSRollback(t, sess, clusterName, serviceName, failedARN, 30*time.Minute)
+
+	t.Run("ExactPriorRevision", func(t *testing.T) {
+		assert.Equal(t, priorARN, aws.StringValue(rollback.TaskDefinition), "The rollback deployment should reuse the prior revision")
+		assert.Equal(t, priorARN, helpers.GetServiceTaskDefinitionARN(t, sess, clusterName, serviceName),
+			"The service should be back on the prior revision, not a copy of it")
+		t.Logf("✅ Rolled back from %s to %s", failedARN, priorARN)
+	})
+
+	t.Run("NoNewRevision", func(t *testing.T) {
+		ecsClient := ecs.New(sess)
+		failed, err := ecsClient.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(failedARN)})
+		require.NoError(t, err)
+
+		// The bad deploy's revision must still be the newest; a re-deploy of the old config would have registered another
+		latest, err := ecsClient.ListTaskDefinitions(&ecs.ListTaskDefinitionsInput{
+			FamilyPrefix: failed.TaskDefinition.Family,
+			Sort:         aws.String(ecs.SortOrderDesc),
+			MaxResults:   aws.Int64(1),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, latest.TaskDefinitionArns)
+		assert.Equal(t, failedARN, aws.StringValue(latest.TaskDefinitionArns[0]), "No revision should be registered after the bad deploy")
+	})
+
+	t.Run("TasksRunPriorRevision", func(t *testing.T) {
+		ecsClient := ecs.New(sess)
+		tasks, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+			Cluster:     aws.String(clusterName),
+			ServiceName: aws.String(serviceName),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, tasks.TaskArns, "The service should have a running task")
+
+		described, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{Cluster: aws.String(clusterName), Tasks: tasks.TaskArns})
+		require.NoError(t, err)
+		for _, task := range described.Tasks {
+			assert.Equal(t, priorARN, aws.StringValue(task.TaskDefinitionArn), "Task %s runs the wrong revision", aws.StringValue(task.TaskArn))
+		}
+
+		waitForHealthyService(t, client, url)
+	})
+}
+
+// readinessSample is the status of one readiness check, or 0 when the request itself failed
+type readinessSample struct {
+	at     time.Time
+	status int
+}
+
+// TestDjangoSurvivesDBFailover fails the database over to its Multi-AZ standby while the service is serving traffic,
+// and verifies readiness degrades while the old primary is gone, then recovers on its own once the connection pool
+// reconnects to the new primary: no task is restarted, and no request fails with a 500 after recovery
+func TestDjangoSurvivesDBFailover(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	settleTime := 30 * time.Second
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	// The unit leaves the data store ingress rules to the sg-to-db-sg-rule unit, so apply the database one as a stack would
+	dbRuleOptions := applyDataStoreRuleUnit(t, "../units/postgresql", 5432)
+	defer terraform.RunTerraformCommand(t, dbRuleOptions, "destroy", "-auto-approve")
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	dbIdentifier := helpers.DBInstanceIdentifierFromARN(unitOutput(t, "../units/postgresql", "arn"))
+	tasksBefore := runningTaskARNs(t, sess, clusterName, serviceName)
+
+	// Check readiness, which queries the database, every second for the whole failover. samples is only read once the
+	// goroutine has stopped.
+	var samples []readinessSample
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		probe := createHTTPClient()
+		probe.Timeout = 5 * time.Second
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Second):
+			}
+
+			sample := readinessSample{at: time.Now()}
+			if resp, err := probe.Get(fmt.Sprintf("%s/health/ready/", url)); err == nil {
+				sample.status = resp.StatusCode
+				resp.Body.Close()
+			}
+			samples = append(samples, sample)
+		}
+	}()
+
+	failoverStart := time.Now()
+	helpers.FailoverRDSInstance(t, sess, dbIdentifier, 15*time.Minute)
+
+	// The app only recovers once its pool drops the dead connections, so give it time after RDS reports the failover done
+	waitForHealthyService(t, client, url)
+	time.Sleep(settleTime)
+	close(done)
+	<-stopped
+
+	recoveredAt := time.Time{}
+	degraded := 0
+	for _, sample := range samples {
+		if sample.status != http.StatusOK {
+			degraded++
+		} else if degraded > 0 && recoveredAt.IsZero() {
+			recoveredAt = sample.at
+		}
+	}
+
+	t.Run("ReadinessDegrades", func(t *testing.T) {
+		assert.Positive(t, degraded, "Readiness never reported the database unavailable, so the failover wasn't observed")
+		t.Logf("Readiness failed %d of %d checks during the failover", degraded, len(samples))
+	})
+
+	t.Run("Recovers", func(t *testing.T) {
+		require.False(t, recoveredAt.IsZero(), "Readiness never recovered after degrading")
+		t.Logf("✅ Readiness recovered %s after the failover started", recoveredAt.Sub(failoverStart).Round(time.Second))
+	})
+
+	t.Run("NoErrorsAfterRecovery", func(t *testing.T) {
+		failures := map[int]int{}
+		for _, sample := range samples {
+			if !sample.at.Before(recoveredAt) && sample.status != http.StatusOK {
+				failures[sample.status]++
+			}
+		}
+		assert.Zero(t, failures[http.StatusInternalServerError], "The app returned 500s after it recovered")
+		assert.Empty(t, failures, "Readiness failed again after it recovered (status counts, 0 = no response): %v", failures)
+	})
+
+	t.Run("NoTaskRestart", func(t *testing.T) {
+		assert.Equal(t, tasksBefore, runningTaskARNs(t, sess, clusterName, serviceName),
+			"The same tasks should still be running; the pool should reconnect without a restart")
+		for _, task := range helpers.ListStoppedECSTasks(t, sess, clusterName, serviceName) {
+			assert.True(t, aws.TimeValue(task.StoppingAt).Before(failoverStart),
+				"Task %s stopped during the failover: %s", aws.StringValue(task.TaskArn), aws.StringValue(task.StoppedReason))
+		}
+	})
+}
+
+// runningTaskARNs returns the ARNs of the service's running tasks, sorted
+func runningTaskARNs(t *testing.T, sess *session.Session, clusterName, serviceName string) []string {
+	t.Helper()
+
+	result, err := ecs.New(sess).ListTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterName),
+		ServiceName:   aws.String(serviceName),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	require.NoError(t, err, "Failed to list tasks of service %s", serviceName)
+
+	arns := aws.StringValueSlice(result.TaskArns)
+	sort.Strings(arns)
+	return arns
+}
+
+// celeryTaskCounts is the state of the drain test's tasks in the broker and result backend
+type celeryTaskCounts struct {
+	succeeded int
+	failed    int
+	queued    int
+	unacked   int
+}
+
+// celeryTaskCountsLine matches the counts line countCeleryTasks has Python print
+var celeryTaskCountsLine = regexp.MustCompile(`SUCCEEDED (\d+) FAILED (\d+) QUEUED (\d+) UNACKED (\d+)`)
+
+// TestDjangoCeleryWorkerDrain seeds a backlog of slow Celery tasks into the Redis broker, restarts the service while its
+// workers are part way through it, and verifies every task still completes exactly once: workers finish their current
+// task within stop_timeout of SIGTERM, and anything they hadn't started stays on the queue for the new tasks
+func TestDjangoCeleryWorkerDrain(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	runID := strings.ToLower(random.UniqueId())
+	backlog := 30
+	taskSeconds := 20
+	stopTimeout := 120
+
+	// Results go to the broker database so the test can count them with the same connection
+	brokerURL := unitOutput(t, "../units/redis", "celery_broker_url")
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"enable_execute_command": true,
+			"stop_timeout":           stopTimeout,
+			"feature_flags": map[string]bool{
+				"sleep_test_task": true,
+			},
+			"additional_environment_variables": map[string]string{
+				"CELERY_RESULT_BACKEND": brokerURL,
+				"CELERY_TASK_ACKS_LATE": "true",
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	clusterName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_cluster_name")
+	require.NoError(t, err)
+	serviceName, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "ecs_service_name")
+	require.NoError(t, err)
+
+	// The unit leaves the data store ingress rules to the sg-to-db-sg-rule unit, so apply the Redis one as a stack would
+	redisRuleOptions := applyDataStoreRuleUnit(t, "../units/redis", 6379)
+	defer terraform.RunTerraformCommand(t, redisRuleOptions, "destroy", "-auto-approve")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	taskARN := helpers.WaitForECSExecAgentRunning(t, sess, clusterName, serviceName, 10*time.Minute)
+
+	// Task IDs carry the run ID so counts only include this run's tasks
+	seed := fmt.Sprintf(
+		`python -c "import os,celery;a=celery.Celery(broker=os.environ['CELERY_BROKER_URL']);[a.send_task('debug.tasks.sleep',args=[%d],task_id='drain-%s-%%d'%%i) for i in range(%d)];print('SEEDED',%d)"`,
+		taskSeconds, runID, backlog, backlog,
+	)
+	output, err := helpers.RunECSExecCommandE(t, awsRegion, clusterName, taskARN, serviceName, seed)
+	require.NoError(t, err, "Failed to seed the Celery backlog")
+	require.Contains(t, output, fmt.Sprintf("SEEDED %d", backlog), "Unexpected output seeding the backlog: %s", output)
+
+	// Restart once the workers are busy, so some tasks are in flight when SIGTERM arrives
+	var before celeryTaskCounts
+	helpers.WaitForCondition(t, helpers.RetryConfig{
+		MaxRetries:    30,
+		RetryInterval: 5 * time.Second,
+		Description:   "Celery workers picking up the backlog",
+	}, func() bool {
+		before = countCeleryTasks(t, awsRegion, clusterName, taskARN, serviceName, runID)
+		return before.unacked > 0
+	}, "No worker started a task from the backlog")
+	t.Logf("Before the restart: %d succeeded, %d in flight, %d queued", before.succeeded, before.unacked, before.queued)
+
+	helpers.RedeployECSService(t, sess, clusterName, serviceName, 15*time.Minute)
+	taskARN = helpers.WaitForECSExecAgentRunning(t, sess, clusterName, serviceName, 10*time.Minute)
+
+	// The new tasks work through whatever the old ones left on the queue
+	var after celeryTaskCounts
+	helpers.WaitForCondition(t, helpers.RetryConfig{
+		MaxRetries:    60,
+		RetryInterval: 10 * time.Second,
+		Description:   "Celery backlog drained",
+	}, func() bool {
+		after = countCeleryTasks(t, awsRegion, clusterName, taskARN, serviceName, runID)
+		return after.queued == 0 && after.unacked == 0
+	}, "The backlog wasn't drained after the restart")
+
+	t.Run("NoTaskLost", func(t *testing.T) {
+		assert.Zero(t, after.failed, "Tasks failed, likely killed mid-run before stop_timeout")
+		assert.Equal(t, backlog, after.succeeded, "Every seeded task should complete after the restart")
+		assert.GreaterOrEqual(t, after.succeeded, before.succeeded, "Completed task count went down across the restart")
+		t.Logf("✅ All %d tasks completed; %d finished after the restart began", after.succeeded, after.succeeded-before.succeeded)
+	})
+
+	t.Run("InFlightTasksFinished", func(t *testing.T) {
+		for _, task := range helpers.ListStoppedECSTasks(t, sess, clusterName, serviceName) {
+			for _, container := range task.Containers {
+				assert.Zero(t, aws.Int64Value(container.ExitCode),
+					"Container %s in task %s was killed instead of exiting after its workers drained: %s",
+					aws.StringValue(container.Name), aws.StringValue(task.TaskArn), aws.StringValue(task.StoppedReason))
+			}
+		}
+	})
+}
+
+// countCeleryTasks counts the drain test's tasks by result status, along with the messages still waiting on the
+// default queue and those delivered to a worker but not yet acknowledged
+func countCeleryTasks(t *testing.T, region, clusterName, taskARN, containerName, runID string) celeryTaskCounts {
+	t.Helper()
+
+	command := fmt.Sprintf(
+		`python -c "import os,json,redis;r=redis.Redis.from_url(os.environ['CELERY_BROKER_URL']);s=[json.loads(r.get(k))['status'] for k in r.scan_iter('celery-task-meta-drain-%s-*')];print('SUCCEEDED',s.count('SUCCESS'),'FAILED',len(s)-s.count('SUCCESS'),'QUEUED',r.llen('celery'),'UNACKED',r.hlen('unacked'))"`,
+		runID,
+	)
+
+	var output string
+	var err error
+	for i := 0; i < 5; i++ {
+		output, err = helpers.RunECSExecCommandE(t, region, clusterName, taskARN, containerName, command)
+		if err == nil && celeryTaskCountsLine.MatchString(output) {
+			break
+		}
+		t.Logf("Counting Celery tasks failed (attempt %d/5): %v", i+1, err)
+		time.Sleep(10 * time.Second)
+	}
+	require.NoError(t, err, "ECS Exec task count failed")
+
+	match := celeryTaskCountsLine.FindStringSubmatch(output)
+	require.NotNil(t, match, "No task counts in ECS Exec output: %s", output)
+
+	var counts [4]int
+	for i := range counts {
+		counts[i], err = strconv.Atoi(match[i+1])
+		require.NoError(t, err)
+	}
+	return celeryTaskCounts{succeeded: counts[0], failed: counts[1], queued: counts[2], unacked: counts[3]}
+}
+
+// TestDjangoLargeRequestBody POSTs bodies just under and just over max_request_body_size and verifies the small one is
+// accepted while the large one is turned away with a clean 413, not a 500 or a dropped connection, so an oversized
+// upload can't exhaust a worker's memory
+func TestDjangoLargeRequestBody(t *testing.T) {
+	t.Parallel()
+
+	maxBodySize := 1024 * 1024
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			"max_request_body_size": maxBodySize,
+			"feature_flags": map[string]bool{
+				"echo_test_endpoint": true,
+			},
+		},
+	}
+
+	defer terraform.RunTerraformCommand(t, terraformOptions, "destroy", "-auto-approve")
+
+	terraform.Apply(t, terraformOptions)
+
+	url, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "output", "-raw", "url")
+	require.NoError(t, err)
+
+	client := createHTTPClient()
+	waitForHealthyService(t, client, url)
+
+	echoURL := fmt.Sprintf("%s/api/debug/echo/", url)
+
+	t.Run("UnderLimitAccepted", func(t *testing.T) {
+		status, body, err := postBodyOfSize(client, echoURL, maxBodySize-1024)
+		require.NoError(t, err, "Request under the limit should complete")
+		assert.Equal(t, http.StatusOK, status, "Body under the limit should be accepted: %s", body)
+		assert.Contains(t, body, strconv.Itoa(maxBodySize-1024), "The app should have read the whole body")
+		t.Logf("✅ %d byte body accepted", maxBodySize-1024)
+	})
+
+	t.Run("OverLimitRejected", func(t *testing.T) {
+		status, body, err := postBodyOfSize(client, echoURL, maxBodySize+1024)
+		require.NoError(t, err, "Request over the limit should get a response, not a dropped connection")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status, "Body over the limit should be rejected with a 413: %s", body)
+		t.Logf("✅ %d byte body rejected with %d", maxBodySize+1024, status)
+	})
+
+	t.Run("WorkerStillServes", func(t *testing.T) {
+		waitForHealthyService(t, client, url)
+	})
+}
+
+// postBodyOfSize POSTs a JSON body of exactly size bytes and returns the status code and response body
+func postBodyOfSize(client *http.Client, url string, size int) (int, string, error) {
+	prefix, suffix := `{"data":"`, `"}`
+	payload := prefix + strings.Repeat("x", size-len(prefix)-len(suffix)) + suffix
+
+	resp, err := client.Post(url, "application/json", strings.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/stretchr/testify/require"
+)
+
+// ImportSelfSignedCertificate generates a self-signed certificate for commonName, imports it into ACM, and returns its
+// ARN along with the PEM-encoded certificate, which clients can trust to verify the listener. A wildcard such as
+// *.us-east-1.elb.amazonaws.com covers every ALB's DNS name in the region. The certificate is valid for a day.
+func ImportSelfSignedCertificate(t *testing.T, sess *session.Session, commonName string) (string, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Failed to generate a certificate key")
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err, "Failed to create a self-signed certificate")
+
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	result, err := acm.New(sess).ImportCertificate(&acm.ImportCertificateInput{
+		Certificate: certificatePEM,
+		PrivateKey:  keyPEM,
+	})
+	require.NoError(t, err, "Failed to import a certificate for %s into ACM", commonName)
+
+	t.Logf("Imported a self-signed certificate for %s: %s", commonName, aws.StringValue(result.CertificateArn))
+	return aws.StringValue(result.CertificateArn), certificatePEM
+}
+
+// DeleteACMCertificate deletes a certificate, retrying while a load balancer that has just been destroyed still holds
+// it. Use it in a defer registered before the destroy, so it runs after.
+func DeleteACMCertificate(t *testing.T, sess *session.Session, certificateARN string) {
+	t.Helper()
+
+	acmClient := acm.New(sess)
+	RetryUntilNoError(t, RetryConfig{
+		MaxRetries:    30,
+		RetryInterval: 10 * time.Second,
+		Description:   "ACM certificate deleted",
+	}, func() error {
+		_, err := acmClient.DeleteCertificate(&acm.DeleteCertificateInput{CertificateArn: aws.String(certificateARN)})
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == acm.ErrCodeResourceNotFoundException {
+			return nil
+		}
+		return err
+	})
+	t.Logf("Deleted certificate %s", certificateARN)
+}
@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	// artifactsDirEnvVar points at the directory CI uploads as build artifacts
+	artifactsDirEnvVar = "TEST_ARTIFACTS_DIR"
+
+	// logExcerptWindow and logExcerptLimit bound how much of each CloudWatch log group is written on failure
+	logExcerptWindow = 30 * time.Minute
+	logExcerptLimit  = 500
+)
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// recordingLogger passes terratest's log lines through to the original logger while keeping a copy, so the full
+// output of every tofu/terragrunt command can be written out after the test fails
+type recordingLogger struct {
+	inner *logger.Logger
+
+	mu    sync.Mutex
+	lines strings.Builder
+}
+
+func (r *recordingLogger) Logf(t terratesting.TestingT, format string, args ...interface{}) {
+	r.mu.Lock()
+	fmt.Fprintf(&r.lines, format+"\n", args...)
+	r.mu.Unlock()
+
+	r.inner.Logf(t, format, args...)
+}
+
+func (r *recordingLogger) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lines.String()
+}
+
+// CaptureLogsOnFailure starts recording the stdout and stderr of every command run with opts, and returns a function
+// that, if the test has failed, writes the recorded output, a fresh plan, the outputs, and recent events from any
+// CloudWatch log groups in the outputs to $TEST_ARTIFACTS_DIR/<test name>. Call it before apply and defer the returned
+// function after the destroy defer, so it runs while the resources still exist:
+//
+//	defer terraform.Destroy(t, opts)
+//	defer helpers.CaptureLogsOnFailure(t, opts)()
+func CaptureLogsOnFailure(t *testing.T, opts *terraform.Options) func() {
+	t.Helper()
+
+	recorder := &recordingLogger{inner: opts.Logger}
+	opts.Logger = logger.New(recorder)
+
+	return func() {
+		if !t.Failed() {
+			return
+		}
+
+		dir := artifactsDir(t)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Logf("Failed to create artifacts directory %s: %v", dir, err)
+			return
+		}
+
+		writeArtifact(t, dir, "commands.log", recorder.String())
+
+		// A fresh plan shows which resources never reached their desired state. -lock=false lets it run even if a
+		// failed apply left the state locked.
+		planArgs := terraform.FormatArgs(opts, "plan", "-input=false", "-lock=false", "-no-color")
+		plan, err := terraform.RunTerraformCommandE(t, opts, planArgs...)
+		if err != nil {
+			plan += fmt.Sprintf("\n\nplan failed: %v", err)
+		}
+		writeArtifact(t, dir, "plan.log", plan)
+
+		outputs, err := terraform.OutputAllE(t, opts)
+		if err != nil {
+			t.Logf("Failed to read outputs for artifacts: %v", err)
+			return
+		}
+		if encoded, err := json.MarshalIndent(outputs, "", "  "); err == nil {
+			writeArtifact(t, dir, "outputs.json", string(encoded))
+		}
+
+		writeLogGroupExcerpts(t, dir, artifactRegion(opts), outputs)
+
+		t.Logf("📁 Wrote failure artifacts to %s", dir)
+	}
+}
+
+// artifactsDir returns the directory for this test's artifacts, under TEST_ARTIFACTS_DIR if set and the system temp
+// directory otherwise
+func artifactsDir(t *testing.T) string {
+	root := os.Getenv(artifactsDirEnvVar)
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "test-artifacts")
+	}
+	return filepath.Join(root, unsafePathChars.ReplaceAllString(t.Name(), "_"))
+}
+
+// artifactRegion returns the region the options deploy to, falling back to the environment and then us-east-1
+func artifactRegion(opts *terraform.Options) string {
+	if region := opts.EnvVars["AWS_DEFAULT_REGION"]; region != "" {
+		return region
+	}
+	if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+		return region
+	}
+	return "us-east-1"
+}
+
+func writeArtifact(t *testing.T, dir, name, contents string) {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Logf("Failed to write artifact %s: %v", path, err)
+	}
+}
+
+// writeLogGroupExcerpts writes recent events from every output whose name mentions a log group, e.g. the ECS
+// modules' cloudwatch_log_group_name
+func writeLogGroupExcerpts(t *testing.T, dir, region string, outputs map[string]interface{}) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		t.Logf("Failed to create AWS session for log excerpts: %v", err)
+		return
+	}
+	logsClient := cloudwatchlogs.New(sess)
+
+	for name, value := range outputs {
+		logGroupName, ok := value.(string)
+		if !ok || logGroupName == "" || !strings.Contains(name, "log_group") {
+			continue
+		}
+
+		var excerpt strings.Builder
+		events := 0
+		err := logsClient.FilterLogEventsPages(&cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName: aws.String(logGroupName),
+			StartTime:    aws.Int64(time.Now().Add(-logExcerptWindow).UnixMilli()),
+		}, func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+			for _, event := range page.Events {
+				timestamp := time.UnixMilli(aws.Int64Value(event.Timestamp)).UTC().Format(time.RFC3339)
+				fmt.Fprintf(&excerpt, "%s [%s] %s\n", timestamp, aws.StringValue(event.LogStreamName), aws.StringValue(event.Message))
+				events++
+			}
+			return events < logExcerptLimit
+		})
+		if err != nil {
+			t.Logf("Failed to read log group %s: %v", logGroupName, err)
+			continue
+		}
+
+		writeArtifact(t, dir, fmt.Sprintf("logs-%s.log", unsafePathChars.ReplaceAllString(name, "_")), excerpt.String())
+	}
+}
@@ -1,16 +1,43 @@
 package helpers
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,17 +51,19 @@ type AWSSessionConfig struct {
 func GetAWSSession(t *testing.T, config AWSSessionConfig) *session.Session {
 	t.Helper()
 
-	awsConfig := &aws.Config{
+	awsConfig := aws.Config{
 		Region: aws.String(config.Region),
 	}
 
-	// Add profile if specified
+	// A named profile, e.g. for another account in a multi-account test, is read from the shared config files
+	options := session.Options{Config: awsConfig}
 	if config.Profile != "" {
-		// Note: Profile configuration requires AWS_SDK_LOAD_CONFIG=1 environment variable
-		awsConfig.CredentialsChainVerboseErrors = aws.Bool(true)
+		options.Profile = config.Profile
+		options.SharedConfigState = session.SharedConfigEnable
+		options.Config.CredentialsChainVerboseErrors = aws.Bool(true)
 	}
 
-	sess, err := session.NewSession(awsConfig)
+	sess, err := session.NewSessionWithOptions(options)
 	require.NoError(t, err, "Failed to create AWS session")
 
 	return sess
@@ -79,6 +108,159 @@ func WaitForRDSInstanceAvailable(t *testing.T, sess *session.Session, dbIdentifi
 	require.Fail(t, "RDS instance did not become available within timeout")
 }
 
+// GenerateRDSAuthToken returns an IAM auth token that logs user in to the RDS instance at endpoint (hostname:port), signed
+// with the session's credentials. The token is used as the password, is valid for 15 minutes, and is only accepted over
+// TLS.
+func GenerateRDSAuthToken(t *testing.T, sess *session.Session, endpoint, region, user string) string {
+	t.Helper()
+
+	token, err := rdsutils.BuildAuthToken(endpoint, region, user, sess.Config.Credentials)
+	require.NoError(t, err, "Failed to build an auth token for %s at %s", user, endpoint)
+
+	return token
+}
+
+// rdsCACertBundles caches the CA bundles DownloadRDSCACertBundle has written, keyed by URL, so parallel tests download
+// each bundle once
+var rdsCACertBundles = struct {
+	sync.Mutex
+	paths map[string]string
+}{paths: map[string]string{}}
+
+// DownloadRDSCACertBundle downloads the RDS CA bundle that covers region, writes it to a temporary file, and returns the
+// file's path for use as sslrootcert. Every commercial region is covered by the global bundle; GovCloud has its own.
+// The file is shared by every test in the run and isn't removed afterwards.
+func DownloadRDSCACertBundle(t *testing.T, region string) string {
+	t.Helper()
+
+	url := "https://truststore.pki.rds.amazonaws.com/global/global-bundle.pem"
+	if strings.HasPrefix(region, "us-gov-") {
+		url = "https://truststore.pki.us-gov-west-1.rds.amazonaws.com/global/global-bundle.pem"
+	}
+
+	rdsCACertBundles.Lock()
+	defer rdsCACertBundles.Unlock()
+	if path, ok := rdsCACertBundles.paths[url]; ok {
+		return path
+	}
+
+	resp, err := http.Get(url)
+	require.NoError(t, err, "Failed to download the RDS CA bundle from %s", url)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Failed to download the RDS CA bundle from %s", url)
+
+	bundle, err := io.ReadAll(resp.Body)
+	require.NoError(t, err, "Failed to read the RDS CA bundle from %s", url)
+	require.True(t, x509.NewCertPool().AppendCertsFromPEM(bundle), "%s has no PEM certificates", url)
+
+	dir, err := os.MkdirTemp("", "rds-ca-")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "rds-ca-bundle.pem")
+	require.NoError(t, os.WriteFile(path, bundle, 0o600), "Failed to write the RDS CA bundle")
+
+	rdsCACertBundles.paths[url] = path
+	return path
+}
+
+// WaitForRDSReadReplica waits for a read replica to become available and report that it is replicating from its
+// source, and returns it. A replica is available before replication has caught up, so the status alone isn't enough.
+func WaitForRDSReadReplica(t *testing.T, sess *session.Session, replicaID string, timeout time.Duration) *rds.DBInstance {
+	t.Helper()
+
+	rdsClient := rds.New(sess)
+	var replica *rds.DBInstance
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (15 * time.Second)),
+		RetryInterval: 15 * time.Second,
+		Description:   "read replica replicating",
+	}, func() bool {
+		result, err := rdsClient.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(replicaID),
+		})
+		if err != nil {
+			t.Logf("Read replica not found yet: %v", err)
+			return false
+		}
+
+		instance := result.DBInstances[0]
+		status := aws.StringValue(instance.DBInstanceStatus)
+		require.NotContains(t, []string{"failed", "incompatible-parameters", "incompatible-restore"}, status,
+			"Read replica %s entered state %s", replicaID, status)
+		if status != "available" {
+			t.Logf("Read replica %s status: %s", replicaID, status)
+			return false
+		}
+
+		for _, info := range instance.StatusInfos {
+			if aws.StringValue(info.StatusType) != "read replication" {
+				continue
+			}
+			t.Logf("Read replica %s replication status: %s", replicaID, aws.StringValue(info.Status))
+			require.NotEqual(t, "error", aws.StringValue(info.Status), "Replication to %s failed: %s", replicaID, aws.StringValue(info.Message))
+			if aws.StringValue(info.Status) == "replicating" {
+				replica = instance
+				return true
+			}
+		}
+		return false
+	}, "Read replica %s was not replicating within %s", replicaID, timeout)
+
+	return replica
+}
+
+// FailoverRDSInstance forces a Multi-AZ instance to fail over to its standby by rebooting it with ForceFailover, waits
+// until it is available again in a different availability zone, and returns the new primary's zone. The endpoint's DNS
+// record is switched to the standby, so clients only recover once they reconnect and resolve it again.
+func FailoverRDSInstance(t *testing.T, sess *session.Session, dbIdentifier string, timeout time.Duration) string {
+	t.Helper()
+
+	rdsClient := rds.New(sess)
+	describe := func() (*rds.DBInstance, error) {
+		result, err := rdsClient.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(dbIdentifier),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.DBInstances) != 1 {
+			return nil, fmt.Errorf("DB instance %s not found", dbIdentifier)
+		}
+		return result.DBInstances[0], nil
+	}
+
+	before, err := describe()
+	require.NoError(t, err, "Failed to describe DB instance %s", dbIdentifier)
+	require.True(t, aws.BoolValue(before.MultiAZ), "DB instance %s is not Multi-AZ, so it has no standby to fail over to", dbIdentifier)
+	previousAZ := aws.StringValue(before.AvailabilityZone)
+
+	_, err = rdsClient.RebootDBInstance(&rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbIdentifier),
+		ForceFailover:        aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to fail over DB instance %s", dbIdentifier)
+	t.Logf("Failing over DB instance %s from %s...", dbIdentifier, previousAZ)
+
+	var currentAZ string
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (10 * time.Second)),
+		RetryInterval: 10 * time.Second,
+		Description:   "RDS failover",
+	}, func() bool {
+		instance, err := describe()
+		if err != nil {
+			t.Logf("Failed to describe DB instance %s: %v", dbIdentifier, err)
+			return false
+		}
+		currentAZ = aws.StringValue(instance.AvailabilityZone)
+		return aws.StringValue(instance.DBInstanceStatus) == "available" && currentAZ != previousAZ
+	}, "DB instance %s did not fail over within %s", dbIdentifier, timeout)
+
+	t.Logf("✅ DB instance %s failed over from %s to %s", dbIdentifier, previousAZ, currentAZ)
+
+	return currentAZ
+}
+
 // WaitForElastiCacheAvailable waits for an ElastiCache replication group to become available
 func WaitForElastiCacheAvailable(t *testing.T, sess *session.Session, replicationGroupID string, timeout time.Duration) {
 	t.Helper()
@@ -118,6 +300,104 @@ func WaitForElastiCacheAvailable(t *testing.T, sess *session.Session, replicatio
 	require.Fail(t, "ElastiCache cluster did not become available within timeout")
 }
 
+// GetElastiCacheParameter returns the value of a parameter in an ElastiCache parameter group, including system
+// parameters the group inherits from its family defaults
+func GetElastiCacheParameter(t *testing.T, sess *session.Session, parameterGroupName, parameterName string) string {
+	t.Helper()
+
+	var value *string
+	err := elasticache.New(sess).DescribeCacheParametersPages(&elasticache.DescribeCacheParametersInput{
+		CacheParameterGroupName: aws.String(parameterGroupName),
+	}, func(page *elasticache.DescribeCacheParametersOutput, lastPage bool) bool {
+		for _, parameter := range page.Parameters {
+			if aws.StringValue(parameter.ParameterName) == parameterName {
+				value = parameter.ParameterValue
+				return false
+			}
+		}
+		return true
+	})
+	require.NoError(t, err, "Failed to describe parameter group %s", parameterGroupName)
+	require.NotNil(t, value, "Parameter group %s has no %s parameter", parameterGroupName, parameterName)
+
+	return aws.StringValue(value)
+}
+
+// CreateSecret creates a Secrets Manager secret tagged with the test run ID and returns its ARN. Delete it with
+// DeleteSecret; the sweep removes it if the test doesn't.
+func CreateSecret(t *testing.T, sess *session.Session, name, value string) string {
+	t.Helper()
+
+	var tags []*secretsmanager.Tag
+	for key, tagValue := range RunIDTags() {
+		tags = append(tags, &secretsmanager.Tag{Key: aws.String(key), Value: aws.String(tagValue)})
+	}
+
+	result, err := secretsmanager.New(sess).CreateSecret(&secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+		Tags:         tags,
+	})
+	require.NoError(t, err, "Failed to create secret %s", name)
+
+	return aws.StringValue(result.ARN)
+}
+
+// DeleteSecret deletes a secret immediately, skipping the recovery window so its name can be reused
+func DeleteSecret(t *testing.T, sess *session.Session, secretARN string) {
+	t.Helper()
+
+	_, err := secretsmanager.New(sess).DeleteSecret(&secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(secretARN),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to delete secret %s", secretARN)
+}
+
+// CreateKMSKey creates a symmetric customer managed KMS key tagged with the test run ID and returns its ARN. Delete it
+// with ScheduleKMSKeyDeletion.
+func CreateKMSKey(t *testing.T, sess *session.Session, description string) string {
+	t.Helper()
+
+	var tags []*kms.Tag
+	for key, tagValue := range RunIDTags() {
+		tags = append(tags, &kms.Tag{TagKey: aws.String(key), TagValue: aws.String(tagValue)})
+	}
+
+	result, err := kms.New(sess).CreateKey(&kms.CreateKeyInput{
+		Description: aws.String(description),
+		Tags:        tags,
+	})
+	require.NoError(t, err, "Failed to create KMS key %q", description)
+
+	return aws.StringValue(result.KeyMetadata.Arn)
+}
+
+// ScheduleKMSKeyDeletion schedules a KMS key for deletion after the minimum 7 day waiting period; KMS keys can't be
+// deleted immediately
+func ScheduleKMSKeyDeletion(t *testing.T, sess *session.Session, keyARN string) {
+	t.Helper()
+
+	_, err := kms.New(sess).ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyARN),
+		PendingWindowInDays: aws.Int64(7),
+	})
+	require.NoError(t, err, "Failed to schedule deletion of KMS key %s", keyARN)
+}
+
+// GetSecretValue returns the current string value of a secret
+func GetSecretValue(t *testing.T, sess *session.Session, secretARN string) string {
+	t.Helper()
+
+	result, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretARN),
+	})
+	require.NoError(t, err, "Failed to read secret %s", secretARN)
+	require.NotNil(t, result.SecretString, "Secret %s has no string value", secretARN)
+
+	return aws.StringValue(result.SecretString)
+}
+
 // WaitForECSServiceStable waits for an ECS service to reach desired count
 func WaitForECSServiceStable(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) {
 	t.Helper()
@@ -157,6 +437,1308 @@ func WaitForECSServiceStable(t *testing.T, sess *session.Session, clusterARN, se
 	require.Fail(t, "ECS service did not stabilize within timeout")
 }
 
+// GetServiceTaskDefinitionARN returns the ARN of the task definition revision the service is currently deploying. While
+// a deployment is in progress this is the new revision; after a circuit breaker rollback it is the revision rolled back
+// to.
+func GetServiceTaskDefinitionARN(t *testing.T, sess *session.Session, clusterARN, serviceName string) string {
+	t.Helper()
+
+	result, err := ecs.New(sess).DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterARN),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err, "Failed to describe service %s", serviceName)
+	require.Len(t, result.Services, 1, "Service %s not found", serviceName)
+
+	return aws.StringValue(result.Services[0].TaskDefinition)
+}
+
+// GetECSTaskLogs returns the log events written to logGroup since the given time by streams starting with
+// logStreamPrefix, e.g. "ecs/<container>" for the ECS module's app log group, oldest first and formatted as
+// "<timestamp> [<stream>] <message>". Returns an empty slice if the group or stream doesn't exist yet, since a task that
+// never started has written no logs.
+func GetECSTaskLogs(t *testing.T, sess *session.Session, logGroup, logStreamPrefix string, since time.Time) []string {
+	t.Helper()
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroup),
+		StartTime:    aws.Int64(since.UnixMilli()),
+	}
+	if logStreamPrefix != "" {
+		input.LogStreamNamePrefix = aws.String(logStreamPrefix)
+	}
+
+	lines := []string{}
+	err := cloudwatchlogs.New(sess).FilterLogEventsPages(input, func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+		for _, event := range page.Events {
+			timestamp := time.UnixMilli(aws.Int64Value(event.Timestamp)).UTC().Format(time.RFC3339)
+			lines = append(lines, fmt.Sprintf("%s [%s] %s", timestamp, aws.StringValue(event.LogStreamName), aws.StringValue(event.Message)))
+		}
+		return true
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+		t.Logf("No logs in %s with stream prefix %q yet", logGroup, logStreamPrefix)
+		return []string{}
+	}
+	require.NoError(t, err, "Failed to read logs from %s", logGroup)
+
+	return lines
+}
+
+// GetECSRunningTaskDefinition returns the task definition revision the service's running tasks use, as opposed to the
+// revision it is deploying. Fails if the service has no running tasks or they run more than one revision, as they do
+// mid-deployment.
+func GetECSRunningTaskDefinition(t *testing.T, sess *session.Session, clusterARN, serviceName string) string {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	tasks, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterARN),
+		ServiceName:   aws.String(serviceName),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	require.NoError(t, err, "Failed to list tasks of service %s", serviceName)
+	require.NotEmpty(t, tasks.TaskArns, "Service %s has no running tasks", serviceName)
+
+	described, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{Cluster: aws.String(clusterARN), Tasks: tasks.TaskArns})
+	require.NoError(t, err, "Failed to describe tasks of service %s", serviceName)
+
+	revisions := make(map[string]bool)
+	for _, task := range described.Tasks {
+		if aws.StringValue(task.LastStatus) == ecs.DesiredStatusRunning {
+			revisions[aws.StringValue(task.TaskDefinitionArn)] = true
+		}
+	}
+	require.Len(t, revisions, 1, "Expected the running tasks of %s to share one revision, got %v", serviceName, revisions)
+
+	for revision := range revisions {
+		return revision
+	}
+	return ""
+}
+
+// WaitForECSRollback waits for the deployment circuit breaker to give up on failedTaskDefinitionARN and for the
+// rollback deployment to complete, and returns the rollback deployment. Fails fast if the deployment succeeds instead.
+func WaitForECSRollback(t *testing.T, sess *session.Session, clusterARN, serviceName, failedTaskDefinitionARN string, timeout time.Duration) *ecs.Deployment {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	var rollback *ecs.Deployment
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (15 * time.Second)),
+		RetryInterval: 15 * time.Second,
+		Description:   "circuit breaker rollback",
+	}, func() bool {
+		result, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterARN),
+			Services: []*string{aws.String(serviceName)},
+		})
+		if err != nil || len(result.Services) == 0 {
+			t.Logf("Failed to describe service %s: %v", serviceName, err)
+			return false
+		}
+
+		// Once the rollback finishes, the failed deployment is dropped and only the rollback deployment is left
+		deployments := result.Services[0].Deployments
+		for _, deployment := range deployments {
+			t.Logf("Deployment %s of %s: %s (%s)", aws.StringValue(deployment.Id), aws.StringValue(deployment.TaskDefinition),
+				aws.StringValue(deployment.RolloutState), aws.StringValue(deployment.RolloutStateReason))
+		}
+		if len(deployments) != 1 || aws.StringValue(deployments[0].RolloutState) != ecs.DeploymentRolloutStateCompleted {
+			return false
+		}
+
+		require.NotEqual(t, failedTaskDefinitionARN, aws.StringValue(deployments[0].TaskDefinition),
+			"Deployment of %s completed, so there was nothing to roll back", failedTaskDefinitionARN)
+		rollback = deployments[0]
+		return true
+	}, "Service %s did not roll back from %s within %s", serviceName, failedTaskDefinitionARN, timeout)
+
+	return rollback
+}
+
+// MonitorECSDeploymentMinRunningCount polls an ECS service until its deployment completes and returns the lowest running count observed
+func MonitorECSDeploymentMinRunningCount(t *testing.T, sess *session.Session, clusterARN, serviceName string, pollInterval, timeout time.Duration) int64 {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	deadline := time.Now().Add(timeout)
+	minRunning := int64(-1)
+
+	for time.Now().Before(deadline) {
+		input := &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterARN),
+			Services: []*string{aws.String(serviceName)},
+		}
+
+		result, err := ecsClient.DescribeServices(input)
+		require.NoError(t, err, "Failed to describe ECS service")
+		require.NotEmpty(t, result.Services, "No services returned")
+
+		service := result.Services[0]
+		runningCount := *service.RunningCount
+		if minRunning < 0 || runningCount < minRunning {
+			minRunning = runningCount
+		}
+
+		t.Logf("ECS deployment: Running=%d, Desired=%d, Deployments=%d, MinObserved=%d",
+			runningCount, *service.DesiredCount, len(service.Deployments), minRunning)
+
+		if len(service.Deployments) == 1 && aws.StringValue(service.Deployments[0].RolloutState) == ecs.DeploymentRolloutStateCompleted &&
+			runningCount == *service.DesiredCount {
+			return minRunning
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, "ECS deployment did not complete within timeout")
+	return minRunning
+}
+
+// AssertECSNoScaleDownDuringDeploy asserts the running count never drops below desired_count * minimum_healthy_percent / 100
+// while the service's current deployment rolls out
+func AssertECSNoScaleDownDuringDeploy(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	result, err := ecsClient.DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterARN),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err, "Failed to describe ECS service")
+	require.NotEmpty(t, result.Services, "No services returned")
+
+	service := result.Services[0]
+	require.NotNil(t, service.DeploymentConfiguration, "Service should have a deployment configuration")
+
+	desiredCount := *service.DesiredCount
+	minimumHealthyPercent := aws.Int64Value(service.DeploymentConfiguration.MinimumHealthyPercent)
+
+	// ECS rounds the minimum healthy task count up to the nearest integer
+	floor := (desiredCount*minimumHealthyPercent + 99) / 100
+	t.Logf("Deployment floor: %d task(s) (desired=%d, minimum_healthy_percent=%d)", floor, desiredCount, minimumHealthyPercent)
+
+	minRunning := MonitorECSDeploymentMinRunningCount(t, sess, clusterARN, serviceName, 2*time.Second, timeout)
+	require.GreaterOrEqual(t, minRunning, floor,
+		"Running count dropped to %d during deployment, below the floor of %d", minRunning, floor)
+	t.Logf("✅ Running count never dropped below %d during deployment (lowest observed: %d)", floor, minRunning)
+}
+
+// ECSCapacityProviderStrategyItem is one entry of a capacity provider strategy
+type ECSCapacityProviderStrategyItem struct {
+	CapacityProvider string
+	Weight           int64
+	Base             int64
+}
+
+// ECSClusterCapacityProviders describes the capacity providers associated with an ECS cluster
+type ECSClusterCapacityProviders struct {
+	CapacityProviders []string
+	DefaultStrategy   []ECSCapacityProviderStrategyItem
+}
+
+// GetClusterCapacityProviders returns the capacity providers associated with a cluster and its default strategy
+func GetClusterCapacityProviders(t *testing.T, sess *session.Session, clusterName string) ECSClusterCapacityProviders {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	result, err := ecsClient.DescribeClusters(&ecs.DescribeClustersInput{
+		Clusters: []*string{aws.String(clusterName)},
+	})
+	require.NoError(t, err, "Failed to describe ECS cluster %s", clusterName)
+	require.Len(t, result.Clusters, 1, "ECS cluster %s not found", clusterName)
+
+	cluster := result.Clusters[0]
+	providers := ECSClusterCapacityProviders{
+		CapacityProviders: aws.StringValueSlice(cluster.CapacityProviders),
+	}
+	for _, item := range cluster.DefaultCapacityProviderStrategy {
+		providers.DefaultStrategy = append(providers.DefaultStrategy, ECSCapacityProviderStrategyItem{
+			CapacityProvider: aws.StringValue(item.CapacityProvider),
+			Weight:           aws.Int64Value(item.Weight),
+			Base:             aws.Int64Value(item.Base),
+		})
+	}
+
+	return providers
+}
+
+// AssertClusterCapacityProviders asserts the cluster has exactly the expected capacity providers associated, in any
+// order, and the expected default capacity provider strategy
+func AssertClusterCapacityProviders(t *testing.T, sess *session.Session, clusterName string, expectedProviders []string, expectedStrategy []ECSCapacityProviderStrategyItem) {
+	t.Helper()
+
+	actual := GetClusterCapacityProviders(t, sess, clusterName)
+
+	require.ElementsMatch(t, expectedProviders, actual.CapacityProviders,
+		"Cluster %s has capacity providers %v, expected %v", clusterName, actual.CapacityProviders, expectedProviders)
+	require.ElementsMatch(t, expectedStrategy, actual.DefaultStrategy,
+		"Cluster %s has default strategy %+v, expected %+v", clusterName, actual.DefaultStrategy, expectedStrategy)
+
+	t.Logf("✅ Cluster %s has capacity providers %v with default strategy %+v", clusterName, actual.CapacityProviders, actual.DefaultStrategy)
+}
+
+// GetECSClusterSetting returns the value of a cluster setting such as containerInsights, or an empty string if the
+// cluster doesn't have it
+func GetECSClusterSetting(t *testing.T, sess *session.Session, clusterName, name string) string {
+	t.Helper()
+
+	result, err := ecs.New(sess).DescribeClusters(&ecs.DescribeClustersInput{
+		Clusters: []*string{aws.String(clusterName)},
+		Include:  []*string{aws.String(ecs.ClusterFieldSettings)},
+	})
+	require.NoError(t, err, "Failed to describe ECS cluster %s", clusterName)
+	require.Len(t, result.Clusters, 1, "ECS cluster %s not found", clusterName)
+
+	for _, setting := range result.Clusters[0].Settings {
+		if aws.StringValue(setting.Name) == name {
+			return aws.StringValue(setting.Value)
+		}
+	}
+	return ""
+}
+
+// ECSScalingEvent is a successful autoscaling change to a service's desired count
+type ECSScalingEvent struct {
+	Start        time.Time
+	End          time.Time
+	DesiredCount int
+}
+
+// ecsScalingDescription matches the description Application Auto Scaling gives ECS scaling activities
+var ecsScalingDescription = regexp.MustCompile(`Setting desired count to (\d+)`)
+
+// ecsServiceResourceID is how Application Auto Scaling identifies an ECS service
+func ecsServiceResourceID(clusterName, serviceName string) string {
+	return fmt.Sprintf("service/%s/%s", clusterName, serviceName)
+}
+
+// GetECSServiceDesiredCount returns the service's current desired count, which autoscaling changes between applies
+func GetECSServiceDesiredCount(t *testing.T, sess *session.Session, clusterName, serviceName string) int {
+	t.Helper()
+
+	result, err := ecs.New(sess).DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err, "Failed to describe ECS service %s", serviceName)
+	require.Len(t, result.Services, 1, "ECS service %s not found", serviceName)
+
+	return int(aws.Int64Value(result.Services[0].DesiredCount))
+}
+
+// GetECSScalableTarget returns the Application Auto Scaling target registered for the service's desired count, or
+// fails if autoscaling isn't set up
+func GetECSScalableTarget(t *testing.T, sess *session.Session, clusterName, serviceName string) *applicationautoscaling.ScalableTarget {
+	t.Helper()
+
+	resourceID := ecsServiceResourceID(clusterName, serviceName)
+	result, err := applicationautoscaling.New(sess).DescribeScalableTargets(&applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace:  aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceIds:       []*string{aws.String(resourceID)},
+		ScalableDimension: aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+	})
+	require.NoError(t, err, "Failed to describe scalable targets for %s", resourceID)
+	require.Len(t, result.ScalableTargets, 1, "No scalable target registered for %s", resourceID)
+
+	return result.ScalableTargets[0]
+}
+
+// AssertScalingPolicyCooldowns asserts every scaling policy on the service uses the expected cooldowns. Target tracking
+// policies have both cooldowns; a step scaling policy has one, compared against scaleOutCooldown if its adjustments add
+// tasks and scaleInCooldown if they remove them.
+func AssertScalingPolicyCooldowns(t *testing.T, sess *session.Session, clusterName, serviceName string, scaleOutCooldown, scaleInCooldown int64) {
+	t.Helper()
+
+	resourceID := ecsServiceResourceID(clusterName, serviceName)
+	result, err := applicationautoscaling.New(sess).DescribeScalingPolicies(&applicationautoscaling.DescribeScalingPoliciesInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceId:       aws.String(resourceID),
+	})
+	require.NoError(t, err, "Failed to describe scaling policies for %s", resourceID)
+	require.NotEmpty(t, result.ScalingPolicies, "No scaling policies for %s", resourceID)
+
+	for _, policy := range result.ScalingPolicies {
+		name := aws.StringValue(policy.PolicyName)
+
+		switch aws.StringValue(policy.PolicyType) {
+		case applicationautoscaling.PolicyTypeTargetTrackingScaling:
+			config := policy.TargetTrackingScalingPolicyConfiguration
+			require.Equal(t, scaleOutCooldown, aws.Int64Value(config.ScaleOutCooldown), "Scale-out cooldown of %s", name)
+			require.Equal(t, scaleInCooldown, aws.Int64Value(config.ScaleInCooldown), "Scale-in cooldown of %s", name)
+
+		case applicationautoscaling.PolicyTypeStepScaling:
+			config := policy.StepScalingPolicyConfiguration
+			require.NotEmpty(t, config.StepAdjustments, "Step scaling policy %s has no steps", name)
+			if aws.Int64Value(config.StepAdjustments[0].ScalingAdjustment) > 0 {
+				require.Equal(t, scaleOutCooldown, aws.Int64Value(config.Cooldown), "Cooldown of scale-out policy %s", name)
+			} else {
+				require.Equal(t, scaleInCooldown, aws.Int64Value(config.Cooldown), "Cooldown of scale-in policy %s", name)
+			}
+		}
+
+		t.Logf("✅ Scaling policy %s (%s) uses the expected cooldowns", name, aws.StringValue(policy.PolicyType))
+	}
+}
+
+// GetECSScalingEvents returns the service's successful autoscaling changes to its desired count, oldest first
+func GetECSScalingEvents(t *testing.T, sess *session.Session, clusterName, serviceName string) []ECSScalingEvent {
+	t.Helper()
+
+	resourceID := ecsServiceResourceID(clusterName, serviceName)
+	var events []ECSScalingEvent
+	err := applicationautoscaling.New(sess).DescribeScalingActivitiesPages(&applicationautoscaling.DescribeScalingActivitiesInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceId:       aws.String(resourceID),
+	}, func(page *applicationautoscaling.DescribeScalingActivitiesOutput, lastPage bool) bool {
+		for _, activity := range page.ScalingActivities {
+			if aws.StringValue(activity.StatusCode) != applicationautoscaling.ScalingActivityStatusCodeSuccessful {
+				continue
+			}
+			match := ecsScalingDescription.FindStringSubmatch(aws.StringValue(activity.Description))
+			if match == nil {
+				continue
+			}
+			desiredCount, _ := strconv.Atoi(match[1])
+			events = append(events, ECSScalingEvent{
+				Start:        aws.TimeValue(activity.StartTime),
+				End:          aws.TimeValue(activity.EndTime),
+				DesiredCount: desiredCount,
+			})
+		}
+		return true
+	})
+	require.NoError(t, err, "Failed to describe scaling activities for %s", resourceID)
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// MeasureContainerWarmStart forces a new deployment of the service and returns the time between the new task reaching
+// RUNNING and its container health check first reporting HEALTHY. The healthy transition is observed by polling, so
+// the result is accurate to within the 2 second poll interval.
+func MeasureContainerWarmStart(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) time.Duration {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	pollInterval := 2 * time.Second
+
+	// Tasks from the initial deployment are already healthy, so only tasks started after this point are measured
+	oldTasks := forceNewECSDeployment(t, ecsClient, clusterARN, serviceName)
+
+	// Tasks seen RUNNING but not yet HEALTHY; a task must be seen in this state before its transition can be timed
+	seenUnhealthy := make(map[string]bool)
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		newTasks := describeNewECSTasks(t, ecsClient, clusterARN, serviceName, oldTasks)
+		if len(newTasks) == 0 {
+			t.Logf("Waiting for service %s to start a new task", serviceName)
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		for _, task := range newTasks {
+			if task.StartedAt == nil {
+				continue
+			}
+			taskARN := aws.StringValue(task.TaskArn)
+
+			if aws.StringValue(task.HealthStatus) != ecs.HealthStatusHealthy {
+				seenUnhealthy[taskARN] = true
+				t.Logf("Task %s: Status=%s, Health=%s", taskARN, aws.StringValue(task.LastStatus), aws.StringValue(task.HealthStatus))
+				continue
+			}
+
+			require.True(t, seenUnhealthy[taskARN],
+				"Task %s was already HEALTHY when first seen, so its warm start can't be measured", taskARN)
+
+			healthyAt := time.Now()
+			warmStart := healthyAt.Sub(*task.StartedAt)
+			t.Logf("⏱️  Container warm start: %s (RUNNING at %s, HEALTHY at %s)", warmStart,
+				task.StartedAt.Format(time.RFC3339), healthyAt.Format(time.RFC3339))
+			return warmStart
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, "No new ECS task became healthy within timeout")
+	return 0
+}
+
+// AssertContainerStartsAfter forces a new deployment of the service and watches the new task's containers, failing if
+// appContainer is ever RUNNING before initContainer has exited with code 0. DescribeTasks doesn't report per-container
+// start times, so the order is checked by polling container states every second; the init container must run long
+// enough to be seen in progress.
+func AssertContainerStartsAfter(t *testing.T, sess *session.Session, clusterARN, serviceName, initContainer, appContainer string, timeout time.Duration) {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	pollInterval := time.Second
+	oldTasks := forceNewECSDeployment(t, ecsClient, clusterARN, serviceName)
+
+	// Set once the app has been seen waiting while the init container is still in progress
+	sawAppWaiting := false
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		for _, task := range describeNewECSTasks(t, ecsClient, clusterARN, serviceName, oldTasks) {
+			var initState, appState *ecs.Container
+			for _, container := range task.Containers {
+				switch aws.StringValue(container.Name) {
+				case initContainer:
+					initState = container
+				case appContainer:
+					appState = container
+				}
+			}
+			require.NotNil(t, initState, "Task has no %s container", initContainer)
+			require.NotNil(t, appState, "Task has no %s container", appContainer)
+
+			initStatus := aws.StringValue(initState.LastStatus)
+			appStatus := aws.StringValue(appState.LastStatus)
+			initDone := initStatus == ecs.DesiredStatusStopped
+
+			if initDone && initState.ExitCode != nil {
+				require.Zero(t, aws.Int64Value(initState.ExitCode), "Init container %s failed: %s",
+					initContainer, aws.StringValue(initState.Reason))
+			}
+
+			if appStatus == ecs.DesiredStatusRunning {
+				require.True(t, initDone, "App container %s is RUNNING while init container %s is %s", appContainer, initContainer, initStatus)
+				require.True(t, sawAppWaiting, "Init container %s had already finished when the task was first seen, so the order wasn't observed", initContainer)
+				t.Logf("✅ %s started only after %s exited successfully", appContainer, initContainer)
+				return
+			}
+
+			if !initDone {
+				sawAppWaiting = true
+			}
+			t.Logf("Task %s: %s=%s, %s=%s", aws.StringValue(task.TaskArn), initContainer, initStatus, appContainer, appStatus)
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("App container %s did not start within %s", appContainer, timeout))
+}
+
+// RedeployECSService replaces the service's tasks with fresh ones and waits for the service to stabilize. Tests use it
+// to drop connections a security group change wouldn't close, since security groups don't interrupt tracked
+// connections.
+func RedeployECSService(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	oldTasks := forceNewECSDeployment(t, ecsClient, clusterARN, serviceName)
+	t.Logf("Forced a new deployment of service %s, waiting for its %d old tasks to stop...", serviceName, len(oldTasks))
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (10 * time.Second)),
+		RetryInterval: 10 * time.Second,
+		Description:   "old ECS tasks replaced",
+	}, func() bool {
+		result, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+			Cluster:     aws.String(clusterARN),
+			ServiceName: aws.String(serviceName),
+		})
+		if err != nil || len(result.TaskArns) == 0 {
+			return false
+		}
+		for _, arn := range result.TaskArns {
+			if oldTasks[aws.StringValue(arn)] {
+				return false
+			}
+		}
+		return true
+	}, "Old tasks of service %s were not replaced within %s", serviceName, timeout)
+
+	WaitForECSServiceStable(t, sess, clusterARN, serviceName, timeout)
+}
+
+// forceNewECSDeployment starts a new deployment of the service and returns the ARNs of the tasks that were already
+// running, so callers can tell the new tasks apart
+func forceNewECSDeployment(t *testing.T, ecsClient *ecs.ECS, clusterARN, serviceName string) map[string]bool {
+	t.Helper()
+
+	existing, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+		Cluster:     aws.String(clusterARN),
+		ServiceName: aws.String(serviceName),
+	})
+	require.NoError(t, err, "Failed to list ECS tasks for service %s", serviceName)
+	oldTasks := make(map[string]bool, len(existing.TaskArns))
+	for _, arn := range existing.TaskArns {
+		oldTasks[aws.StringValue(arn)] = true
+	}
+
+	_, err = ecsClient.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:            aws.String(clusterARN),
+		Service:            aws.String(serviceName),
+		ForceNewDeployment: aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to force a new deployment of service %s", serviceName)
+
+	return oldTasks
+}
+
+// describeNewECSTasks describes the service's tasks that aren't in oldTasks
+func describeNewECSTasks(t *testing.T, ecsClient *ecs.ECS, clusterARN, serviceName string, oldTasks map[string]bool) []*ecs.Task {
+	t.Helper()
+
+	listResult, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+		Cluster:     aws.String(clusterARN),
+		ServiceName: aws.String(serviceName),
+	})
+	require.NoError(t, err, "Failed to list ECS tasks for service %s", serviceName)
+
+	var newTasks []*string
+	for _, arn := range listResult.TaskArns {
+		if !oldTasks[aws.StringValue(arn)] {
+			newTasks = append(newTasks, arn)
+		}
+	}
+	if len(newTasks) == 0 {
+		return nil
+	}
+
+	describeResult, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{
+		Cluster: aws.String(clusterARN),
+		Tasks:   newTasks,
+	})
+	require.NoError(t, err, "Failed to describe ECS tasks")
+
+	return describeResult.Tasks
+}
+
+// WaitForStoppedECSTask waits until a task of the service has stopped and returns it. Its StoppedReason and its
+// containers' Reason and ExitCode say why, e.g. OutOfMemoryError when a container exceeded its memory limit.
+func WaitForStoppedECSTask(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) *ecs.Task {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	var stopped *ecs.Task
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (10 * time.Second)),
+		RetryInterval: 10 * time.Second,
+		Description:   "stopped ECS task",
+	}, func() bool {
+		tasks := describeECSTasks(t, ecsClient, clusterARN, serviceName, ecs.DesiredStatusStopped)
+		for _, task := range tasks {
+			// Container exit reasons are only filled in once the task has fully stopped
+			if aws.StringValue(task.LastStatus) == ecs.DesiredStatusStopped {
+				stopped = task
+				return true
+			}
+		}
+		return false
+	}, "a task of service %s to stop", serviceName)
+
+	t.Logf("Task %s stopped: %s", aws.StringValue(stopped.TaskArn), aws.StringValue(stopped.StoppedReason))
+
+	return stopped
+}
+
+// ListStoppedECSTasks returns the service's stopped tasks. ECS keeps stopped tasks visible for about an hour, so a
+// service that replaced tasks in a loop shows them here along with why each one stopped.
+func ListStoppedECSTasks(t *testing.T, sess *session.Session, clusterARN, serviceName string) []*ecs.Task {
+	t.Helper()
+
+	return describeECSTasks(t, ecs.New(sess), clusterARN, serviceName, ecs.DesiredStatusStopped)
+}
+
+// WaitForECSTaskReplaced waits until the service starts a task to replace the stopped one and returns the new task's
+// ARN. The replacement may itself have stopped by the time it is found.
+func WaitForECSTaskReplaced(t *testing.T, sess *session.Session, clusterARN, serviceName string, stopped *ecs.Task, timeout time.Duration) string {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	replacement := ""
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (10 * time.Second)),
+		RetryInterval: 10 * time.Second,
+		Description:   "replacement ECS task",
+	}, func() bool {
+		for _, status := range []string{ecs.DesiredStatusRunning, ecs.DesiredStatusStopped} {
+			for _, task := range describeECSTasks(t, ecsClient, clusterARN, serviceName, status) {
+				if aws.StringValue(task.TaskArn) != aws.StringValue(stopped.TaskArn) &&
+					!aws.TimeValue(task.CreatedAt).Before(aws.TimeValue(stopped.StoppingAt)) {
+					replacement = aws.StringValue(task.TaskArn)
+					return true
+				}
+			}
+		}
+		return false
+	}, "service %s to replace task %s", serviceName, aws.StringValue(stopped.TaskArn))
+
+	return replacement
+}
+
+// describeECSTasks describes the service's tasks with the given desired status
+func describeECSTasks(t *testing.T, ecsClient *ecs.ECS, clusterARN, serviceName, desiredStatus string) []*ecs.Task {
+	t.Helper()
+
+	listResult, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterARN),
+		ServiceName:   aws.String(serviceName),
+		DesiredStatus: aws.String(desiredStatus),
+	})
+	require.NoError(t, err, "Failed to list ECS tasks for service %s", serviceName)
+	if len(listResult.TaskArns) == 0 {
+		return nil
+	}
+
+	describeResult, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{
+		Cluster: aws.String(clusterARN),
+		Tasks:   listResult.TaskArns,
+	})
+	require.NoError(t, err, "Failed to describe ECS tasks")
+
+	return describeResult.Tasks
+}
+
+// WaitForECSExecAgentRunning waits until a task of the service has a running ECS Exec agent and returns its ARN
+func WaitForECSExecAgentRunning(t *testing.T, sess *session.Session, clusterARN, serviceName string, timeout time.Duration) string {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	pollInterval := 10 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		listResult, err := ecsClient.ListTasks(&ecs.ListTasksInput{
+			Cluster:       aws.String(clusterARN),
+			ServiceName:   aws.String(serviceName),
+			DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+		})
+		if err != nil || len(listResult.TaskArns) == 0 {
+			t.Logf("No running tasks found for service %s yet", serviceName)
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		describeResult, err := ecsClient.DescribeTasks(&ecs.DescribeTasksInput{
+			Cluster: aws.String(clusterARN),
+			Tasks:   listResult.TaskArns,
+		})
+		require.NoError(t, err, "Failed to describe ECS tasks")
+
+		for _, task := range describeResult.Tasks {
+			for _, container := range task.Containers {
+				for _, agent := range container.ManagedAgents {
+					if aws.StringValue(agent.Name) == ecs.ManagedAgentNameExecuteCommandAgent &&
+						aws.StringValue(agent.LastStatus) == "RUNNING" {
+						t.Logf("✅ ECS Exec agent running in task %s", aws.StringValue(task.TaskArn))
+						return aws.StringValue(task.TaskArn)
+					}
+				}
+			}
+		}
+
+		t.Logf("Waiting for ECS Exec agent in service %s...", serviceName)
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("No task in service %s had a running ECS Exec agent within %s", serviceName, timeout))
+	return ""
+}
+
+// RunECSExecCommandE runs a command inside a container via ECS Exec and returns its output. Requires the AWS CLI and
+// the Session Manager plugin on the machine running the tests.
+func RunECSExecCommandE(t *testing.T, region, clusterARN, taskARN, containerName, command string) (string, error) {
+	t.Helper()
+
+	return shell.RunCommandAndGetOutputE(t, shell.Command{
+		Command: "aws",
+		Args: []string{
+			"ecs", "execute-command",
+			"--region", region,
+			"--cluster", clusterARN,
+			"--task", taskARN,
+			"--container", containerName,
+			"--interactive",
+			"--command", command,
+		},
+	})
+}
+
+// ECSServiceConnectConfiguration is the Service Connect configuration of an ECS service deployment
+type ECSServiceConnectConfiguration struct {
+	Enabled   *bool   `locationName:"enabled" type:"boolean"`
+	Namespace *string `locationName:"namespace" type:"string"`
+	Services  []*struct {
+		PortName      *string `locationName:"portName" type:"string"`
+		DiscoveryName *string `locationName:"discoveryName" type:"string"`
+		ClientAliases []*struct {
+			DNSName *string `locationName:"dnsName" type:"string"`
+			Port    *int64  `locationName:"port" type:"integer"`
+		} `locationName:"clientAliases" type:"list"`
+	} `locationName:"services" type:"list"`
+}
+
+// describeServicesServiceConnectOutput decodes only the Service Connect fields of a DescribeServices response
+type describeServicesServiceConnectOutput struct {
+	Services []*struct {
+		Deployments []*struct {
+			Status                      *string                         `locationName:"status" type:"string"`
+			ServiceConnectConfiguration *ECSServiceConnectConfiguration `locationName:"serviceConnectConfiguration" type:"structure"`
+		} `locationName:"deployments" type:"list"`
+	} `locationName:"services" type:"list"`
+}
+
+// GetECSServiceConnectConfiguration returns the Service Connect configuration of the service's primary deployment.
+// The vendored SDK predates Service Connect, so the DescribeServices response is decoded into a local struct.
+func GetECSServiceConnectConfiguration(t *testing.T, sess *session.Session, clusterARN, serviceName string) *ECSServiceConnectConfiguration {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	output := &describeServicesServiceConnectOutput{}
+
+	req := ecsClient.NewRequest(&request.Operation{
+		Name:       "DescribeServices",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterARN),
+		Services: []*string{aws.String(serviceName)},
+	}, output)
+	require.NoError(t, req.Send(), "Failed to describe ECS service %s", serviceName)
+	require.Len(t, output.Services, 1, "ECS service %s not found", serviceName)
+
+	for _, deployment := range output.Services[0].Deployments {
+		if aws.StringValue(deployment.Status) == "PRIMARY" {
+			require.NotNil(t, deployment.ServiceConnectConfiguration, "Service %s has no Service Connect configuration", serviceName)
+			return deployment.ServiceConnectConfiguration
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("Service %s has no PRIMARY deployment", serviceName))
+	return nil
+}
+
+// GetALBListenerRules returns every rule on an ALB listener, including its default rule
+func GetALBListenerRules(t *testing.T, sess *session.Session, listenerARN string) []*elbv2.Rule {
+	t.Helper()
+
+	var rules []*elbv2.Rule
+	input := &elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerARN)}
+	for {
+		result, err := elbv2.New(sess).DescribeRules(input)
+		require.NoError(t, err, "Failed to describe rules for listener %s", listenerARN)
+
+		rules = append(rules, result.Rules...)
+		if result.NextMarker == nil {
+			return rules
+		}
+		input.Marker = result.NextMarker
+	}
+}
+
+// AssertALBDefaultFixedResponse asserts the listener's default rule answers requests no other rule matches with the
+// expected fixed response, rather than forwarding them to a target group
+func AssertALBDefaultFixedResponse(t *testing.T, sess *session.Session, listenerARN string, statusCode int, contentType, messageBody string) {
+	t.Helper()
+
+	for _, rule := range GetALBListenerRules(t, sess, listenerARN) {
+		if !aws.BoolValue(rule.IsDefault) {
+			continue
+		}
+
+		require.Len(t, rule.Actions, 1, "Default rule of listener %s should have a single action", listenerARN)
+		action := rule.Actions[0]
+		require.Equal(t, elbv2.ActionTypeEnumFixedResponse, aws.StringValue(action.Type),
+			"Default rule of listener %s should return a fixed response", listenerARN)
+		require.NotNil(t, action.FixedResponseConfig)
+
+		require.Equal(t, strconv.Itoa(statusCode), aws.StringValue(action.FixedResponseConfig.StatusCode))
+		require.Equal(t, contentType, aws.StringValue(action.FixedResponseConfig.ContentType))
+		require.Equal(t, messageBody, aws.StringValue(action.FixedResponseConfig.MessageBody))
+
+		t.Logf("✅ Listener %s answers unmatched requests with a %d %s fixed response", listenerARN, statusCode, contentType)
+		return
+	}
+
+	require.Fail(t, fmt.Sprintf("Listener %s has no default rule", listenerARN))
+}
+
+// TargetGroupConfig is how an ALB target group talks to its targets and decides whether they are healthy
+type TargetGroupConfig struct {
+	Protocol            string
+	ProtocolVersion     string
+	Port                int64
+	HealthCheckProtocol string
+	HealthCheckPath     string
+	HealthyThreshold    int64
+	// Matcher is the HTTP codes, or for GRPC target groups the gRPC codes, that count as healthy
+	Matcher string
+}
+
+// GetTargetGroupConfig returns the protocol and health check configuration of a target group
+func GetTargetGroupConfig(t *testing.T, sess *session.Session, tgArn string) TargetGroupConfig {
+	t.Helper()
+
+	result, err := elbv2.New(sess).DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{aws.String(tgArn)},
+	})
+	require.NoError(t, err, "Failed to describe target group %s", tgArn)
+	require.Len(t, result.TargetGroups, 1, "Target group %s not found", tgArn)
+
+	tg := result.TargetGroups[0]
+	config := TargetGroupConfig{
+		Protocol:            aws.StringValue(tg.Protocol),
+		ProtocolVersion:     aws.StringValue(tg.ProtocolVersion),
+		Port:                aws.Int64Value(tg.Port),
+		HealthCheckProtocol: aws.StringValue(tg.HealthCheckProtocol),
+		HealthCheckPath:     aws.StringValue(tg.HealthCheckPath),
+		HealthyThreshold:    aws.Int64Value(tg.HealthyThresholdCount),
+	}
+	if tg.Matcher != nil {
+		config.Matcher = aws.StringValue(tg.Matcher.HttpCode)
+		if config.ProtocolVersion == "GRPC" {
+			config.Matcher = aws.StringValue(tg.Matcher.GrpcCode)
+		}
+	}
+
+	return config
+}
+
+// CountHealthyTargets returns how many targets in the target group are passing their health checks
+func CountHealthyTargets(t *testing.T, sess *session.Session, tgArn string) int {
+	t.Helper()
+
+	healthy, err := countHealthyTargets(t, elbv2.New(sess), tgArn)
+	require.NoError(t, err, "Failed to describe target health of %s", tgArn)
+
+	return healthy
+}
+
+// WaitForHealthyTargets waits until at least count targets in the target group pass their health checks, logging the
+// reason each unhealthy target gives so protocol and matcher mismatches are easy to spot
+func WaitForHealthyTargets(t *testing.T, sess *session.Session, tgArn string, count int, timeout time.Duration) {
+	t.Helper()
+
+	client := elbv2.New(sess)
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (15 * time.Second)),
+		RetryInterval: 15 * time.Second,
+		Description:   "healthy targets",
+	}, func() bool {
+		healthy, err := countHealthyTargets(t, client, tgArn)
+		if err != nil {
+			t.Logf("Failed to describe target health: %v", err)
+			return false
+		}
+		return healthy >= count
+	}, "Fewer than %d targets in %s became healthy within %s", count, tgArn, timeout)
+
+	t.Logf("✅ %d target(s) healthy in %s", count, tgArn)
+}
+
+// countHealthyTargets counts the healthy targets in a target group and logs why any others aren't
+func countHealthyTargets(t *testing.T, client *elbv2.ELBV2, tgArn string) (int, error) {
+	result, err := client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(tgArn)})
+	if err != nil {
+		return 0, err
+	}
+
+	healthy := 0
+	for _, target := range result.TargetHealthDescriptions {
+		state := aws.StringValue(target.TargetHealth.State)
+		if state == elbv2.TargetHealthStateEnumHealthy {
+			healthy++
+		} else if target.TargetHealth.Reason != nil {
+			t.Logf("Target %s is %s: %s", aws.StringValue(target.Target.Id), state, aws.StringValue(target.TargetHealth.Description))
+		}
+	}
+
+	return healthy, nil
+}
+
+// S3EventNotification is the subset of the S3 event payload delivered to SQS that tests care about
+type S3EventNotification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key string `json:"key"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// WaitForS3EventNotification polls an SQS queue until an S3 event for the given object key arrives and returns it.
+// Other messages, such as the s3:TestEvent sent when the notification is configured, are deleted and skipped.
+func WaitForS3EventNotification(t *testing.T, sess *session.Session, queueURL, objectKey string, timeout time.Duration) S3EventNotification {
+	t.Helper()
+
+	sqsClient := sqs.New(sess)
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		result, err := sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: aws.Int64(10),
+			WaitTimeSeconds:     aws.Int64(10),
+		})
+		require.NoError(t, err, "Failed to receive messages from %s", queueURL)
+
+		for _, msg := range result.Messages {
+			_, err := sqsClient.DeleteMessage(&sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(queueURL),
+				ReceiptHandle: msg.ReceiptHandle,
+			})
+			require.NoError(t, err, "Failed to delete message from %s", queueURL)
+
+			var event S3EventNotification
+			if err := json.Unmarshal([]byte(aws.StringValue(msg.Body)), &event); err != nil {
+				t.Logf("Skipping non-JSON message: %s", aws.StringValue(msg.Body))
+				continue
+			}
+
+			for _, record := range event.Records {
+				if record.S3.Object.Key == objectKey {
+					t.Logf("✅ Received %s notification for %s", record.EventName, objectKey)
+					return event
+				}
+			}
+
+			t.Logf("Skipping unrelated message: %s", aws.StringValue(msg.Body))
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("No S3 event notification for %s arrived within %s", objectKey, timeout))
+	return S3EventNotification{}
+}
+
+// UploadLargeObject uploads size bytes of pseudo-random data to the bucket as a multipart upload with the given part
+// size and returns the SHA-256 of the data. Set accelerate to upload through the bucket's transfer acceleration
+// endpoint. The upload is retried because the accelerate endpoint can reject requests shortly after it is enabled.
+func UploadLargeObject(t *testing.T, sess *session.Session, bucket, key string, size, partSize int64, accelerate bool) string {
+	t.Helper()
+
+	uploader := s3manager.NewUploader(sess, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
+	}, func(u *s3manager.Uploader) {
+		u.S3 = s3.New(sess, &aws.Config{S3UseAccelerate: aws.Bool(accelerate)})
+	})
+
+	var checksum string
+	RetryUntilNoError(t, FastRetryConfig(fmt.Sprintf("multipart upload of s3://%s/%s", bucket, key)), func() error {
+		hash := sha256.New()
+		body := io.TeeReader(io.LimitReader(rand.New(rand.NewSource(size)), size), hash)
+
+		start := time.Now()
+		_, err := uploader.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   body,
+		})
+		if err != nil {
+			return err
+		}
+
+		checksum = hex.EncodeToString(hash.Sum(nil))
+		t.Logf("Uploaded %d MB to s3://%s/%s in %s (accelerate=%t)", size/(1024*1024), bucket, key, time.Since(start).Round(time.Second), accelerate)
+		return nil
+	})
+
+	return checksum
+}
+
+// DownloadObjectSHA256 downloads an object in parallel ranged parts and returns the SHA-256 of its contents
+func DownloadObjectSHA256(t *testing.T, sess *session.Session, bucket, key string) string {
+	t.Helper()
+
+	file, err := os.CreateTemp("", "s3-download-*")
+	require.NoError(t, err, "Failed to create temp file")
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	_, err = s3manager.NewDownloader(sess).Download(file, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	require.NoError(t, err, "Failed to download s3://%s/%s", bucket, key)
+
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	require.NoError(t, err, "Failed to hash downloaded object")
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// WaitForS3ObjectWithPrefix waits for at least one object whose key starts with prefix to appear in the bucket, for
+// output that AWS delivers asynchronously such as ALB access logs, and returns the first key found
+func WaitForS3ObjectWithPrefix(t *testing.T, sess *session.Session, bucket, prefix string, config RetryConfig) string {
+	t.Helper()
+
+	s3Client := s3.New(sess)
+	var key string
+
+	WaitForCondition(t, config, func() bool {
+		result, err := s3Client.ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket:  aws.String(bucket),
+			Prefix:  aws.String(prefix),
+			MaxKeys: aws.Int64(1),
+		})
+		if err != nil {
+			t.Logf("Failed to list s3://%s/%s: %v", bucket, prefix, err)
+			return false
+		}
+		if len(result.Contents) == 0 {
+			return false
+		}
+
+		key = aws.StringValue(result.Contents[0].Key)
+		return true
+	}, "an object with prefix s3://%s/%s", bucket, prefix)
+
+	t.Logf("✅ Found s3://%s/%s", bucket, key)
+	return key
+}
+
+// BucketPolicyStatement is a statement of an S3 bucket policy. Principal, Action, and Resource may each be a string or
+// a list, so they are left undecoded.
+type BucketPolicyStatement struct {
+	Sid       string                            `json:"Sid"`
+	Effect    string                            `json:"Effect"`
+	Principal interface{}                       `json:"Principal"`
+	Action    interface{}                       `json:"Action"`
+	Resource  interface{}                       `json:"Resource"`
+	Condition map[string]map[string]interface{} `json:"Condition"`
+}
+
+// WaitForCloudFrontDeployed waits for a CloudFront distribution's configuration to finish propagating to the edge
+// locations, which usually takes several minutes after it is created or changed
+func WaitForCloudFrontDeployed(t *testing.T, sess *session.Session, distributionID string, timeout time.Duration) {
+	t.Helper()
+
+	cloudfrontClient := cloudfront.New(sess)
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (30 * time.Second)),
+		RetryInterval: 30 * time.Second,
+		Description:   "CloudFront distribution deployed",
+	}, func() bool {
+		result, err := cloudfrontClient.GetDistribution(&cloudfront.GetDistributionInput{Id: aws.String(distributionID)})
+		if err != nil {
+			t.Logf("Failed to get distribution %s: %v", distributionID, err)
+			return false
+		}
+
+		status := aws.StringValue(result.Distribution.Status)
+		t.Logf("Distribution %s is %s", distributionID, status)
+		return status == "Deployed"
+	}, "distribution %s to be deployed", distributionID)
+}
+
+// GetBucketPolicyStatements returns the statements of a bucket's policy
+func GetBucketPolicyStatements(t *testing.T, sess *session.Session, bucket string) []BucketPolicyStatement {
+	t.Helper()
+
+	result, err := s3.New(sess).GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: aws.String(bucket)})
+	require.NoError(t, err, "Failed to get the policy of bucket %s", bucket)
+
+	var policy struct {
+		Statement []BucketPolicyStatement `json:"Statement"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(result.Policy)), &policy),
+		"Bucket %s has an invalid policy", bucket)
+
+	return policy.Statement
+}
+
+// AssertEgressRestricted fails if the security group allows egress to any destination outside allowedDestinations.
+// Destinations are CIDR blocks, security group IDs, or prefix list IDs; pass nil to require no egress at all.
+func AssertEgressRestricted(t *testing.T, sess *session.Session, sgID string, allowedDestinations []string) {
+	t.Helper()
+
+	ec2Client := ec2.New(sess)
+
+	result, err := ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String(sgID)},
+	})
+	require.NoError(t, err, "Failed to describe security group %s", sgID)
+	require.Len(t, result.SecurityGroups, 1, "Security group %s not found", sgID)
+
+	allowed := make(map[string]bool, len(allowedDestinations))
+	for _, destination := range allowedDestinations {
+		allowed[destination] = true
+	}
+
+	var violations []string
+	for _, rule := range result.SecurityGroups[0].IpPermissionsEgress {
+		var destinations []string
+		for _, ipRange := range rule.IpRanges {
+			destinations = append(destinations, aws.StringValue(ipRange.CidrIp))
+		}
+		for _, ipv6Range := range rule.Ipv6Ranges {
+			destinations = append(destinations, aws.StringValue(ipv6Range.CidrIpv6))
+		}
+		for _, pair := range rule.UserIdGroupPairs {
+			destinations = append(destinations, aws.StringValue(pair.GroupId))
+		}
+		for _, prefixList := range rule.PrefixListIds {
+			destinations = append(destinations, aws.StringValue(prefixList.PrefixListId))
+		}
+
+		for _, destination := range destinations {
+			if !allowed[destination] {
+				violations = append(violations, fmt.Sprintf("%s ports %d-%d to %s", aws.StringValue(rule.IpProtocol),
+					aws.Int64Value(rule.FromPort), aws.Int64Value(rule.ToPort), destination))
+			}
+		}
+	}
+
+	require.Empty(t, violations, "Security group %s allows unexpected egress", sgID)
+	t.Logf("✅ Security group %s egress is restricted to %v", sgID, allowedDestinations)
+}
+
+// orphanedENITimeout is how long AssertNoOrphanedENIs waits for network interfaces to be released. Fargate usually
+// detaches a stopped task's interface within a few minutes.
+const orphanedENITimeout = 10 * time.Minute
+
+// AssertNoOrphanedENIs fails if network interfaces still reference the security group after the service using it has
+// been deleted. Fargate releases task interfaces asynchronously, so it waits for them to go first. Any that remain
+// would make deleting the security group fail with DependencyViolation.
+func AssertNoOrphanedENIs(t *testing.T, sess *session.Session, sgID string) {
+	t.Helper()
+
+	ec2Client := ec2.New(sess)
+	pollInterval := 15 * time.Second
+	deadline := time.Now().Add(orphanedENITimeout)
+
+	var remaining []string
+	for {
+		result, err := ec2Client.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("group-id"), Values: []*string{aws.String(sgID)}}},
+		})
+		require.NoError(t, err, "Failed to describe network interfaces in security group %s", sgID)
+
+		remaining = nil
+		for _, eni := range result.NetworkInterfaces {
+			remaining = append(remaining, fmt.Sprintf("%s (%s: %s)", aws.StringValue(eni.NetworkInterfaceId),
+				aws.StringValue(eni.Status), aws.StringValue(eni.Description)))
+		}
+		if len(remaining) == 0 || time.Now().After(deadline) {
+			break
+		}
+
+		t.Logf("Waiting for %d network interface(s) in security group %s to be released...", len(remaining), sgID)
+		time.Sleep(pollInterval)
+	}
+
+	require.Empty(t, remaining, "Network interfaces still reference security group %s %s after the service was deleted",
+		sgID, orphanedENITimeout)
+	t.Logf("✅ No network interfaces reference security group %s", sgID)
+}
+
+// AssertDatastoreSGsIsolated audits the data tier as a whole: the database and cache must have separate security
+// groups, each allowing ingress only from the app security group on its own port, with no CIDR or prefix list
+// sources, no rules referencing each other, and no egress.
+func AssertDatastoreSGsIsolated(t *testing.T, sess *session.Session, rdsSgID, redisSgID, appSgID string, rdsPort, redisPort int64) {
+	t.Helper()
+
+	require.NotEqual(t, rdsSgID, redisSgID, "The database and cache should not share a security group")
+	require.NotEqual(t, appSgID, rdsSgID, "The app and database should not share a security group")
+	require.NotEqual(t, appSgID, redisSgID, "The app and cache should not share a security group")
+
+	ec2Client := ec2.New(sess)
+	result, err := ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String(rdsSgID), aws.String(redisSgID)},
+	})
+	require.NoError(t, err, "Failed to describe data store security groups")
+	require.Len(t, result.SecurityGroups, 2, "Data store security groups not found")
+
+	expectedPorts := map[string]int64{
+		rdsSgID:   rdsPort,
+		redisSgID: redisPort,
+	}
+
+	for _, sg := range result.SecurityGroups {
+		sgID := aws.StringValue(sg.GroupId)
+		port := expectedPorts[sgID]
+
+		var violations []string
+		allowsApp := false
+
+		for _, rule := range sg.IpPermissions {
+			ruleDescription := fmt.Sprintf("%s ports %d-%d", aws.StringValue(rule.IpProtocol), aws.Int64Value(rule.FromPort), aws.Int64Value(rule.ToPort))
+
+			if aws.StringValue(rule.IpProtocol) != "tcp" || aws.Int64Value(rule.FromPort) != port || aws.Int64Value(rule.ToPort) != port {
+				violations = append(violations, fmt.Sprintf("%s (only tcp %d is expected)", ruleDescription, port))
+			}
+			for _, ipRange := range rule.IpRanges {
+				violations = append(violations, fmt.Sprintf("%s from CIDR %s", ruleDescription, aws.StringValue(ipRange.CidrIp)))
+			}
+			for _, ipv6Range := range rule.Ipv6Ranges {
+				violations = append(violations, fmt.Sprintf("%s from CIDR %s", ruleDescription, aws.StringValue(ipv6Range.CidrIpv6)))
+			}
+			for _, prefixList := range rule.PrefixListIds {
+				violations = append(violations, fmt.Sprintf("%s from prefix list %s", ruleDescription, aws.StringValue(prefixList.PrefixListId)))
+			}
+			for _, pair := range rule.UserIdGroupPairs {
+				source := aws.StringValue(pair.GroupId)
+				if source == appSgID {
+					allowsApp = true
+					continue
+				}
+				violations = append(violations, fmt.Sprintf("%s from security group %s (only the app %s is expected)", ruleDescription, source, appSgID))
+			}
+		}
+
+		require.Empty(t, violations, "Security group %s allows ingress beyond the app on port %d", sgID, port)
+		require.True(t, allowsApp, "Security group %s should allow the app security group %s on port %d", sgID, appSgID, port)
+
+		// Without egress neither data store can open a connection to the other
+		AssertEgressRestricted(t, sess, sgID, nil)
+
+		t.Logf("✅ Security group %s only allows the app (%s) on tcp %d", sgID, appSgID, port)
+	}
+}
+
+// AssertVPCEndpointExists requires exactly one available endpoint for serviceName (e.g. com.amazonaws.us-east-1.s3) in
+// the VPC and returns it, so callers can check its type and placement
+func AssertVPCEndpointExists(t *testing.T, sess *session.Session, vpcID, serviceName string) *ec2.VpcEndpoint {
+	t.Helper()
+
+	ec2Client := ec2.New(sess)
+
+	result, err := ec2Client.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpcID)},
+			},
+			{
+				Name:   aws.String("service-name"),
+				Values: []*string{aws.String(serviceName)},
+			},
+		},
+	})
+	require.NoError(t, err, "Failed to describe VPC endpoints in %s", vpcID)
+	require.Len(t, result.VpcEndpoints, 1, "Expected one %s endpoint in VPC %s", serviceName, vpcID)
+
+	endpoint := result.VpcEndpoints[0]
+	require.Equal(t, "available", aws.StringValue(endpoint.State), "%s endpoint %s is not available",
+		serviceName, aws.StringValue(endpoint.VpcEndpointId))
+
+	t.Logf("✅ %s endpoint %s (%s) exists in %s", serviceName, aws.StringValue(endpoint.VpcEndpointId),
+		aws.StringValue(endpoint.VpcEndpointType), vpcID)
+
+	return endpoint
+}
+
 // GetVPCIDByTag finds a VPC ID by tag key and value
 func GetVPCIDByTag(t *testing.T, sess *session.Session, tagKey, tagValue string) string {
 	t.Helper()
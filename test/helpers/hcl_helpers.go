@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moduleMetaArguments are the arguments of a module block that configure the call rather than set a module variable
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"count":      true,
+	"for_each":   true,
+	"providers":  true,
+	"depends_on": true,
+}
+
+// ModuleVariable is a variable declared by a module
+type ModuleVariable struct {
+	Name string
+	// Required is true when the variable has no default, so every caller must set it
+	Required bool
+}
+
+// ModuleCall is a module block, with the directory its local source resolves to and the variables it sets
+type ModuleCall struct {
+	Name      string
+	Source    string
+	Arguments []string
+}
+
+// ParseModuleVariables parses the .tf files in dir and returns the variables they declare, keyed by name
+func ParseModuleVariables(t *testing.T, dir string) map[string]ModuleVariable {
+	t.Helper()
+
+	variables := map[string]ModuleVariable{}
+	for _, block := range parseTofuBlocks(t, dir, "variable") {
+		variables[block.Labels[0]] = ModuleVariable{
+			Name:     block.Labels[0],
+			Required: block.Body.Attributes["default"] == nil,
+		}
+	}
+
+	return variables
+}
+
+// ParseLocalModuleCalls parses the .tf files in dir and returns its module blocks with a local source, such as
+// ../../../modules/redis. Calls to registry or git sources are skipped, since there's no module on disk to check them
+// against.
+func ParseLocalModuleCalls(t *testing.T, dir string) []ModuleCall {
+	t.Helper()
+
+	var calls []ModuleCall
+	for _, block := range parseTofuBlocks(t, dir, "module") {
+		sourceAttr := block.Body.Attributes["source"]
+		require.NotNil(t, sourceAttr, "module %q in %s has no source", block.Labels[0], dir)
+
+		source, diags := sourceAttr.Expr.Value(nil)
+		require.False(t, diags.HasErrors(), "module %q in %s: source must be a literal string: %s", block.Labels[0], dir, diags)
+		require.Equal(t, cty.String, source.Type(), "module %q in %s: source must be a string", block.Labels[0], dir)
+		if !strings.HasPrefix(source.AsString(), "./") && !strings.HasPrefix(source.AsString(), "../") {
+			continue
+		}
+
+		call := ModuleCall{
+			Name:   block.Labels[0],
+			Source: filepath.Join(dir, source.AsString()),
+		}
+		for name := range block.Body.Attributes {
+			if !moduleMetaArguments[name] {
+				call.Arguments = append(call.Arguments, name)
+			}
+		}
+		sort.Strings(call.Arguments)
+
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+// FindDeprecatedArguments parses the .tf files in dir and returns a description of every resource that sets a
+// deprecated argument or nested block. deprecated maps a resource type to its deprecated arguments, each with the
+// replacement to suggest, e.g. {"aws_db_instance": {"name": "db_name"}}.
+func FindDeprecatedArguments(t *testing.T, dir string, deprecated map[string]map[string]string) []string {
+	t.Helper()
+
+	var found []string
+	for _, block := range parseTofuBlocks(t, dir, "resource") {
+		arguments, ok := deprecated[block.Labels[0]]
+		if !ok {
+			continue
+		}
+
+		address := strings.Join(block.Labels, ".")
+		for name, replacement := range arguments {
+			if block.Body.Attributes[name] != nil {
+				found = append(found, fmt.Sprintf("%s sets %s (use %s)", address, name, replacement))
+			}
+		}
+		for _, nested := range block.Body.Blocks {
+			if replacement, ok := arguments[nested.Type]; ok {
+				found = append(found, fmt.Sprintf("%s has a %s block (use %s)", address, nested.Type, replacement))
+			}
+		}
+	}
+	sort.Strings(found)
+
+	return found
+}
+
+// parseTofuBlocks returns the top-level blocks of the given type in the .tf files in dir
+func parseTofuBlocks(t *testing.T, dir, blockType string) []*hclsyntax.Block {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths, "No .tf files in %s", dir)
+
+	parser := hclparse.NewParser()
+	var blocks []*hclsyntax.Block
+	for _, path := range paths {
+		file, diags := parser.ParseHCLFile(path)
+		require.False(t, diags.HasErrors(), "Failed to parse %s: %s", path, diags)
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			if block.Type == blockType {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	return blocks
+}
@@ -0,0 +1,209 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/stretchr/testify/require"
+)
+
+// statePolicyAttributes maps each resource type that holds a policy document to the attribute holding it
+var statePolicyAttributes = map[string]string{
+	"aws_iam_role":         "assume_role_policy",
+	"aws_iam_role_policy":  "policy",
+	"aws_iam_policy":       "policy",
+	"aws_s3_bucket_policy": "policy",
+	"aws_sqs_queue_policy": "policy",
+	"aws_sns_topic_policy": "policy",
+}
+
+// StatePolicy is a policy document found in a state, with the address of the resource holding it
+type StatePolicy struct {
+	Address  string
+	Document string
+}
+
+// ExtractStatePolicies returns every IAM role trust policy, inline or managed IAM policy, and S3, SQS, or SNS resource
+// policy in a state returned by PullState, sorted by address
+func ExtractStatePolicies(t *testing.T, state map[string]interface{}) []StatePolicy {
+	t.Helper()
+
+	var policies []StatePolicy
+	resources, _ := state["resources"].([]interface{})
+	for _, r := range resources {
+		resource, _ := r.(map[string]interface{})
+		resourceType, _ := resource["type"].(string)
+		attribute, ok := statePolicyAttributes[resourceType]
+		if resource["mode"] != "managed" || !ok {
+			continue
+		}
+
+		address := fmt.Sprintf("%s.%s", resourceType, resource["name"])
+		if module, ok := resource["module"].(string); ok {
+			address = fmt.Sprintf("%s.%s", module, address)
+		}
+
+		instances, _ := resource["instances"].([]interface{})
+		for i, inst := range instances {
+			instance, _ := inst.(map[string]interface{})
+			attributes, _ := instance["attributes"].(map[string]interface{})
+			document, _ := attributes[attribute].(string)
+			require.NotEmpty(t, document, "%s[%d] has no %s in state", address, i, attribute)
+
+			instanceAddress := address
+			if indexKey, ok := instance["index_key"]; ok {
+				instanceAddress = fmt.Sprintf("%s[%v]", address, indexKey)
+			}
+			policies = append(policies, StatePolicy{Address: instanceAddress, Document: document})
+		}
+	}
+
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Address < policies[j].Address })
+	return policies
+}
+
+// AnalyzePolicy validates a policy document with IAM Access Analyzer and returns every finding. The policy type is
+// inferred from the document: a policy with a Principal is a resource policy, a trust policy if it only grants
+// sts:AssumeRole* actions, and an S3 bucket policy if it covers S3 ARNs; one without is an identity policy.
+func AnalyzePolicy(t *testing.T, sess *session.Session, policyJSON string) []*accessanalyzer.ValidatePolicyFinding {
+	t.Helper()
+
+	input := &accessanalyzer.ValidatePolicyInput{
+		PolicyDocument: aws.String(policyJSON),
+		PolicyType:     aws.String(accessanalyzer.PolicyTypeIdentityPolicy),
+	}
+	if resourceType, isResourcePolicy := policyResourceType(t, policyJSON); isResourcePolicy {
+		input.PolicyType = aws.String(accessanalyzer.PolicyTypeResourcePolicy)
+		if resourceType != "" {
+			input.ValidatePolicyResourceType = aws.String(resourceType)
+		}
+	}
+
+	var findings []*accessanalyzer.ValidatePolicyFinding
+	err := accessanalyzer.New(sess).ValidatePolicyPages(input, func(page *accessanalyzer.ValidatePolicyOutput, lastPage bool) bool {
+		findings = append(findings, page.Findings...)
+		return true
+	})
+	require.NoError(t, err, "Access Analyzer failed to validate policy: %s", policyJSON)
+
+	return findings
+}
+
+// policyResourceType reports whether a policy document is a resource policy, and if so the Access Analyzer resource
+// type to validate it as, or "" when Access Analyzer has no specific checks for it (e.g. SQS queue policies)
+func policyResourceType(t *testing.T, policyJSON string) (string, bool) {
+	t.Helper()
+
+	var document struct {
+		Statement []struct {
+			Principal interface{}
+			Action    interface{}
+			Resource  interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(policyJSON), &document), "Policy is not valid JSON: %s", policyJSON)
+
+	hasPrincipal, assumeRoleOnly, s3Only := false, true, true
+	for _, statement := range document.Statement {
+		if statement.Principal != nil {
+			hasPrincipal = true
+		}
+		for _, action := range policyStrings(statement.Action) {
+			if !strings.HasPrefix(action, "sts:AssumeRole") {
+				assumeRoleOnly = false
+			}
+		}
+		resources := policyStrings(statement.Resource)
+		if len(resources) == 0 {
+			s3Only = false
+		}
+		for _, resource := range resources {
+			if !strings.HasPrefix(resource, "arn:aws:s3:::") {
+				s3Only = false
+			}
+		}
+	}
+
+	switch {
+	case !hasPrincipal:
+		return "", false
+	case assumeRoleOnly:
+		return accessanalyzer.ValidatePolicyResourceTypeAwsIamAssumeRolePolicyDocument, true
+	case s3Only:
+		return accessanalyzer.ValidatePolicyResourceTypeAwsS3Bucket, true
+	default:
+		return "", true
+	}
+}
+
+// policyStrings returns a policy element that may be a single string or a list of strings as a list
+func policyStrings(element interface{}) []string {
+	switch value := element.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// GetRoleInlinePolicyResources returns the resources that the role's inline policies allow action on, sorted, so tests
+// can check a module granted access to exactly what it should
+func GetRoleInlinePolicyResources(t *testing.T, sess *session.Session, roleARN, action string) []string {
+	t.Helper()
+
+	iamClient := iam.New(sess)
+	roleName := roleARN[strings.LastIndex(roleARN, "/")+1:]
+
+	names, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list inline policies of role %s", roleName)
+
+	var resources []string
+	for _, name := range names.PolicyNames {
+		policy, err := iamClient.GetRolePolicy(&iam.GetRolePolicyInput{RoleName: aws.String(roleName), PolicyName: name})
+		require.NoError(t, err, "Failed to get policy %s of role %s", aws.StringValue(name), roleName)
+
+		// GetRolePolicy returns the document URL-encoded
+		document, err := url.QueryUnescape(aws.StringValue(policy.PolicyDocument))
+		require.NoError(t, err)
+
+		var parsed struct {
+			Statement []struct {
+				Effect   string
+				Action   interface{}
+				Resource interface{}
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(document), &parsed), "Policy %s is not valid JSON", aws.StringValue(name))
+
+		for _, statement := range parsed.Statement {
+			if statement.Effect != "Allow" {
+				continue
+			}
+			for _, allowed := range policyStrings(statement.Action) {
+				if allowed == action {
+					resources = append(resources, policyStrings(statement.Resource)...)
+					break
+				}
+			}
+		}
+	}
+
+	sort.Strings(resources)
+	return resources
+}
@@ -0,0 +1,261 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/require"
+)
+
+// MetricFilterNamespace is the CloudWatch namespace of the metrics published by filters from CreateMetricFilter
+const MetricFilterNamespace = "Terratest/LogMetrics"
+
+// CreateMetricFilter creates a log metric filter that adds 1 to the metric filterName in MetricFilterNamespace for every
+// log line matching pattern. Callers should defer DeleteMetricFilter to clean it up.
+func CreateMetricFilter(t *testing.T, sess *session.Session, logGroupName, filterName, pattern string) {
+	t.Helper()
+
+	logsClient := cloudwatchlogs.New(sess)
+
+	_, err := logsClient.PutMetricFilter(&cloudwatchlogs.PutMetricFilterInput{
+		LogGroupName:  aws.String(logGroupName),
+		FilterName:    aws.String(filterName),
+		FilterPattern: aws.String(pattern),
+		MetricTransformations: []*cloudwatchlogs.MetricTransformation{
+			{
+				MetricNamespace: aws.String(MetricFilterNamespace),
+				MetricName:      aws.String(filterName),
+				MetricValue:     aws.String("1"),
+				DefaultValue:    aws.Float64(0),
+			},
+		},
+	})
+	require.NoError(t, err, "Failed to create metric filter %s", filterName)
+	t.Logf("✅ Created metric filter %s on %s", filterName, logGroupName)
+}
+
+// DeleteMetricFilter deletes a log metric filter created by CreateMetricFilter
+func DeleteMetricFilter(t *testing.T, sess *session.Session, logGroupName, filterName string) {
+	t.Helper()
+
+	logsClient := cloudwatchlogs.New(sess)
+
+	_, err := logsClient.DeleteMetricFilter(&cloudwatchlogs.DeleteMetricFilterInput{
+		LogGroupName: aws.String(logGroupName),
+		FilterName:   aws.String(filterName),
+	})
+	require.NoError(t, err, "Failed to delete metric filter %s", filterName)
+}
+
+// AssertMetricFilterExists verifies a metric filter exists on the log group and returns it
+func AssertMetricFilterExists(t *testing.T, sess *session.Session, logGroupName, filterName string) *cloudwatchlogs.MetricFilter {
+	t.Helper()
+
+	logsClient := cloudwatchlogs.New(sess)
+
+	result, err := logsClient.DescribeMetricFilters(&cloudwatchlogs.DescribeMetricFiltersInput{
+		LogGroupName:     aws.String(logGroupName),
+		FilterNamePrefix: aws.String(filterName),
+	})
+	require.NoError(t, err, "Failed to describe metric filters for %s", logGroupName)
+
+	for _, filter := range result.MetricFilters {
+		if aws.StringValue(filter.FilterName) == filterName {
+			require.NotEmpty(t, filter.MetricTransformations, "Metric filter %s has no metric transformations", filterName)
+			t.Logf("✅ Metric filter %s exists (pattern: %q)", filterName, aws.StringValue(filter.FilterPattern))
+			return filter
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("Metric filter %s not found on log group %s", filterName, logGroupName))
+	return nil
+}
+
+// AssertLogGroupExists verifies a CloudWatch log group exists and returns it
+func AssertLogGroupExists(t *testing.T, sess *session.Session, name string) *cloudwatchlogs.LogGroup {
+	t.Helper()
+
+	var found *cloudwatchlogs.LogGroup
+	err := cloudwatchlogs.New(sess).DescribeLogGroupsPages(&cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(name),
+	}, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		for _, group := range page.LogGroups {
+			if aws.StringValue(group.LogGroupName) == name {
+				found = group
+				return false
+			}
+		}
+		return true
+	})
+	require.NoError(t, err, "Failed to describe log groups with prefix %s", name)
+	require.NotNil(t, found, "Log group %s not found", name)
+
+	t.Logf("✅ Log group %s exists", name)
+	return found
+}
+
+// WaitForMetricSum waits until the sum of a metric over the last 15 minutes reaches at least minSum and returns it
+func WaitForMetricSum(t *testing.T, sess *session.Session, namespace, metricName string, minSum float64, timeout time.Duration) float64 {
+	t.Helper()
+
+	cwClient := cloudwatch.New(sess)
+	pollInterval := 30 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	var sum float64
+	for time.Now().Before(deadline) {
+		result, err := cwClient.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String(namespace),
+			MetricName: aws.String(metricName),
+			StartTime:  aws.Time(time.Now().Add(-15 * time.Minute)),
+			EndTime:    aws.Time(time.Now()),
+			Period:     aws.Int64(60),
+			Statistics: []*string{aws.String(cloudwatch.StatisticSum)},
+		})
+		require.NoError(t, err, "Failed to get statistics for %s/%s", namespace, metricName)
+
+		sum = 0
+		for _, datapoint := range result.Datapoints {
+			sum += aws.Float64Value(datapoint.Sum)
+		}
+
+		if sum >= minSum {
+			t.Logf("✅ Metric %s/%s reached %.0f (wanted >= %.0f)", namespace, metricName, sum, minSum)
+			return sum
+		}
+
+		t.Logf("Metric %s/%s is %.0f, waiting for >= %.0f...", namespace, metricName, sum, minSum)
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("Metric %s/%s only reached %.0f within %s (wanted >= %.0f)", namespace, metricName, sum, timeout, minSum))
+	return sum
+}
+
+// WaitForAlarmState waits until a CloudWatch alarm reaches the given state (OK, ALARM, or INSUFFICIENT_DATA)
+func WaitForAlarmState(t *testing.T, sess *session.Session, alarmName, state string, timeout time.Duration) {
+	t.Helper()
+
+	cwClient := cloudwatch.New(sess)
+	pollInterval := 15 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		result, err := cwClient.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+			AlarmNames: []*string{aws.String(alarmName)},
+		})
+		require.NoError(t, err, "Failed to describe alarm %s", alarmName)
+		require.Len(t, result.MetricAlarms, 1, "Alarm %s not found", alarmName)
+
+		current := aws.StringValue(result.MetricAlarms[0].StateValue)
+		if current == state {
+			t.Logf("✅ Alarm %s is %s: %s", alarmName, state, aws.StringValue(result.MetricAlarms[0].StateReason))
+			return
+		}
+
+		t.Logf("Alarm %s is %s, waiting for %s...", alarmName, current, state)
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("Alarm %s did not reach %s within %s", alarmName, state, timeout))
+}
+
+// GetCloudWatchAlarmState returns the current state of a CloudWatch alarm (OK, ALARM, or INSUFFICIENT_DATA), failing
+// if the alarm doesn't exist
+func GetCloudWatchAlarmState(t *testing.T, sess *session.Session, alarmName string) string {
+	t.Helper()
+
+	result, err := cloudwatch.New(sess).DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []*string{aws.String(alarmName)},
+	})
+	require.NoError(t, err, "Failed to describe alarm %s", alarmName)
+	require.Len(t, result.MetricAlarms, 1, "Alarm %s not found", alarmName)
+
+	return aws.StringValue(result.MetricAlarms[0].StateValue)
+}
+
+// WaitForLogEvents waits until at least one event in the log group matches the filter pattern and returns the matches
+func WaitForLogEvents(t *testing.T, sess *session.Session, logGroupName, filterPattern string, timeout time.Duration) []*cloudwatchlogs.FilteredLogEvent {
+	t.Helper()
+
+	logsClient := cloudwatchlogs.New(sess)
+	pollInterval := 15 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		result, err := logsClient.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName:  aws.String(logGroupName),
+			FilterPattern: aws.String(filterPattern),
+		})
+		require.NoError(t, err, "Failed to filter log events in %s", logGroupName)
+
+		if len(result.Events) > 0 {
+			t.Logf("✅ Found %d log events in %s matching %q", len(result.Events), logGroupName, filterPattern)
+			return result.Events
+		}
+
+		t.Logf("No log events in %s match %q yet, waiting...", logGroupName, filterPattern)
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("No log events in %s matched %q within %s", logGroupName, filterPattern, timeout))
+	return nil
+}
+
+// AssertDashboardExists verifies a CloudWatch dashboard exists and returns its JSON body
+func AssertDashboardExists(t *testing.T, sess *session.Session, dashboardName string) string {
+	t.Helper()
+
+	result, err := cloudwatch.New(sess).GetDashboard(&cloudwatch.GetDashboardInput{
+		DashboardName: aws.String(dashboardName),
+	})
+	require.NoError(t, err, "Failed to get dashboard %s", dashboardName)
+	require.NotEmpty(t, aws.StringValue(result.DashboardBody), "Dashboard %s has no body", dashboardName)
+
+	t.Logf("✅ Dashboard %s exists", dashboardName)
+
+	return aws.StringValue(result.DashboardBody)
+}
+
+// AssertDashboardReferences verifies every resource ID appears as a dimension value in at least one of the dashboard's
+// metric widgets, so the dashboard graphs the deployed resources rather than stale or placeholder ones
+func AssertDashboardReferences(t *testing.T, dashboardName, body string, resourceIDs ...string) {
+	t.Helper()
+
+	var dashboard struct {
+		Widgets []struct {
+			Type       string `json:"type"`
+			Properties struct {
+				Metrics [][]interface{} `json:"metrics"`
+			} `json:"properties"`
+		} `json:"widgets"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &dashboard), "Dashboard %s body is not valid JSON", dashboardName)
+	require.NotEmpty(t, dashboard.Widgets, "Dashboard %s has no widgets", dashboardName)
+
+	referenced := map[string]bool{}
+	for _, widget := range dashboard.Widgets {
+		if widget.Type != "metric" {
+			continue
+		}
+		for _, metric := range widget.Properties.Metrics {
+			for _, value := range metric {
+				if s, ok := value.(string); ok {
+					referenced[s] = true
+				}
+			}
+		}
+	}
+
+	for _, id := range resourceIDs {
+		require.True(t, referenced[id], "Dashboard %s has no metric for %s", dashboardName, id)
+	}
+
+	t.Logf("✅ Dashboard %s graphs %v", dashboardName, resourceIDs)
+}
@@ -0,0 +1,210 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// approvedMaintenanceWindowEnvVar overrides the off-hours window maintenance must fall within
+	approvedMaintenanceWindowEnvVar = "APPROVED_MAINTENANCE_WINDOW"
+
+	// defaultApprovedMaintenanceWindow is the change-freeze policy's off-hours window. It covers the postgresql and
+	// redis module defaults.
+	defaultApprovedMaintenanceWindow = "Sun:04:00-Sun:07:00"
+
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// businessHours are the UTC ranges no maintenance may overlap, whatever the approved window is set to
+var businessHours = []string{
+	"Mon:08:00-Mon:18:00",
+	"Tue:08:00-Tue:18:00",
+	"Wed:08:00-Wed:18:00",
+	"Thu:08:00-Thu:18:00",
+	"Fri:08:00-Fri:18:00",
+}
+
+// MaintenanceWindow is a weekly UTC time range, as RDS and ElastiCache express maintenance windows, stored as minutes
+// since Sunday 00:00. End is before Start when the window wraps from Saturday into Sunday.
+type MaintenanceWindow struct {
+	Start int
+	End   int
+}
+
+// ParseMaintenanceWindow parses a window in the ddd:hh24:mi-ddd:hh24:mi format, e.g. Sun:05:00-Sun:06:00. Day names
+// are case-insensitive, since RDS reports them in lowercase.
+func ParseMaintenanceWindow(window string) (MaintenanceWindow, error) {
+	bounds := strings.Split(window, "-")
+	if len(bounds) != 2 {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window %q is not in ddd:hh24:mi-ddd:hh24:mi format", window)
+	}
+
+	start, err := parseWeekMinute(bounds[0])
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window %q: %w", window, err)
+	}
+	end, err := parseWeekMinute(bounds[1])
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window %q: %w", window, err)
+	}
+	if start == end {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window %q is empty", window)
+	}
+
+	return MaintenanceWindow{Start: start, End: end}, nil
+}
+
+// parseWeekMinute converts ddd:hh24:mi to minutes since Sunday 00:00
+func parseWeekMinute(value string) (int, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("%q is not in ddd:hh24:mi format", value)
+	}
+
+	day := -1
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(parts[0], d.String()[:3]) {
+			day = int(d)
+		}
+	}
+	if day < 0 {
+		return 0, fmt.Errorf("%q is not a three-letter day name", parts[0])
+	}
+
+	clock, err := time.Parse("15:04", parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an hh24:mi time", parts[1])
+	}
+
+	return day*minutesPerDay + clock.Hour()*60 + clock.Minute(), nil
+}
+
+// String formats the window back into ddd:hh24:mi-ddd:hh24:mi
+func (w MaintenanceWindow) String() string {
+	format := func(minute int) string {
+		day := time.Weekday(minute / minutesPerDay)
+		return fmt.Sprintf("%s:%02d:%02d", day.String()[:3], minute/60%24, minute%60)
+	}
+	return fmt.Sprintf("%s-%s", format(w.Start), format(w.End))
+}
+
+// unwrapped returns the window's bounds with End moved into the following week when the window wraps
+func (w MaintenanceWindow) unwrapped() (int, int) {
+	if w.End < w.Start {
+		return w.Start, w.End + minutesPerWeek
+	}
+	return w.Start, w.End
+}
+
+// Within reports whether the window lies entirely inside other
+func (w MaintenanceWindow) Within(other MaintenanceWindow) bool {
+	start, end := w.unwrapped()
+	otherStart, otherEnd := other.unwrapped()
+
+	// Compare against other in the previous, current, and next week, so windows that wrap line up
+	for _, shift := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+		if otherStart+shift <= start && end <= otherEnd+shift {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps reports whether the two windows share any time
+func (w MaintenanceWindow) Overlaps(other MaintenanceWindow) bool {
+	start, end := w.unwrapped()
+	otherStart, otherEnd := other.unwrapped()
+
+	for _, shift := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+		if start < otherEnd+shift && otherStart+shift < end {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovedMaintenanceWindow returns the off-hours window maintenance must fall within, from
+// APPROVED_MAINTENANCE_WINDOW if set and the change-freeze policy's default otherwise
+func ApprovedMaintenanceWindow(t *testing.T) MaintenanceWindow {
+	t.Helper()
+
+	window := os.Getenv(approvedMaintenanceWindowEnvVar)
+	if window == "" {
+		window = defaultApprovedMaintenanceWindow
+	}
+
+	approved, err := ParseMaintenanceWindow(window)
+	require.NoError(t, err, "Invalid %s", approvedMaintenanceWindowEnvVar)
+
+	return approved
+}
+
+// AssertMaintenanceWindowCompliant asserts a resource's maintenance window falls within the approved window and
+// overlaps no business hours
+func AssertMaintenanceWindowCompliant(t *testing.T, resource, window string, approved MaintenanceWindow) {
+	t.Helper()
+
+	parsed, err := ParseMaintenanceWindow(window)
+	require.NoError(t, err, "%s has an unparseable maintenance window", resource)
+
+	require.True(t, parsed.Within(approved),
+		"%s has maintenance window %s, outside the approved window %s", resource, parsed, approved)
+
+	for _, hours := range businessHours {
+		business, err := ParseMaintenanceWindow(hours)
+		require.NoError(t, err)
+		require.False(t, parsed.Overlaps(business),
+			"%s has maintenance window %s, which overlaps business hours %s", resource, parsed, business)
+	}
+
+	t.Logf("✅ %s maintenance window %s is within %s", resource, parsed, approved)
+}
+
+// GetRDSMaintenanceWindow returns the preferred maintenance window of an RDS instance
+func GetRDSMaintenanceWindow(t *testing.T, sess *session.Session, dbIdentifier string) string {
+	t.Helper()
+
+	result, err := rds.New(sess).DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbIdentifier),
+	})
+	require.NoError(t, err, "Failed to describe RDS instance %s", dbIdentifier)
+	require.Len(t, result.DBInstances, 1, "RDS instance %s not found", dbIdentifier)
+
+	return aws.StringValue(result.DBInstances[0].PreferredMaintenanceWindow)
+}
+
+// GetElastiCacheMaintenanceWindows returns the preferred maintenance window of every node in a replication group,
+// keyed by cache cluster ID. The window is set per node, so a group can drift if nodes were modified individually.
+func GetElastiCacheMaintenanceWindows(t *testing.T, sess *session.Session, replicationGroupID string) map[string]string {
+	t.Helper()
+
+	client := elasticache.New(sess)
+	groups, err := client.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroupID),
+	})
+	require.NoError(t, err, "Failed to describe replication group %s", replicationGroupID)
+	require.Len(t, groups.ReplicationGroups, 1, "Replication group %s not found", replicationGroupID)
+
+	windows := map[string]string{}
+	for _, clusterID := range groups.ReplicationGroups[0].MemberClusters {
+		clusters, err := client.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{CacheClusterId: clusterID})
+		require.NoError(t, err, "Failed to describe cache cluster %s", aws.StringValue(clusterID))
+		require.Len(t, clusters.CacheClusters, 1, "Cache cluster %s not found", aws.StringValue(clusterID))
+
+		windows[aws.StringValue(clusterID)] = aws.StringValue(clusters.CacheClusters[0].PreferredMaintenanceWindow)
+	}
+	require.NotEmpty(t, windows, "Replication group %s has no member clusters", replicationGroupID)
+
+	return windows
+}
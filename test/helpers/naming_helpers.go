@@ -0,0 +1,103 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// NamingConstraints describes the names an AWS resource type accepts
+type NamingConstraints struct {
+	Description string
+	MaxLength   int
+	Pattern     *regexp.Regexp
+}
+
+// Naming constraints for the resources the tests create whose names must be unique per account or globally
+var (
+	S3BucketNaming = NamingConstraints{
+		Description: "S3 bucket",
+		MaxLength:   63,
+		Pattern:     regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`),
+	}
+	RDSIdentifierNaming = NamingConstraints{
+		Description: "RDS DB instance identifier",
+		MaxLength:   63,
+		Pattern:     regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	}
+	ElastiCacheNaming = NamingConstraints{
+		Description: "ElastiCache replication group ID",
+		MaxLength:   40,
+		Pattern:     regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	}
+	ALBNaming = NamingConstraints{
+		Description: "load balancer",
+		MaxLength:   32,
+		Pattern:     regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`),
+	}
+)
+
+const (
+	// uniqueSuffixLength gives 36^8 (~2.8 trillion) possible suffixes, so collisions are negligible even across
+	// thousands of names per run
+	uniqueSuffixLength = 8
+	uniqueSuffixChars  = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+var repeatedHyphens = regexp.MustCompile(`-{2,}`)
+
+// UniqueResourceName returns base with a random suffix appended, sanitized and truncated to satisfy the constraints.
+// Use it instead of random.UniqueId() for globally-unique names: UniqueId is only 6 characters and loses entropy when
+// lowercased, which causes collisions across parallel CI runs.
+func UniqueResourceName(base string, constraints NamingConstraints) string {
+	suffix := randomSuffix()
+
+	// Lowercase and hyphen-only is accepted by every resource type above
+	base = strings.ToLower(base)
+	base = invalidNameChars.ReplaceAllString(base, "-")
+	base = repeatedHyphens.ReplaceAllString(base, "-")
+	base = strings.Trim(base, "-")
+
+	// Several resource types require a leading letter
+	if base == "" || base[0] < 'a' || base[0] > 'z' {
+		base = "t" + base
+	}
+
+	maxBaseLength := constraints.MaxLength - len(suffix) - 1
+	if len(base) > maxBaseLength {
+		base = strings.TrimRight(base[:maxBaseLength], "-")
+	}
+
+	return fmt.Sprintf("%s-%s", base, suffix)
+}
+
+// ValidateResourceName returns an error if the name doesn't satisfy the constraints
+func ValidateResourceName(name string, constraints NamingConstraints) error {
+	if len(name) > constraints.MaxLength {
+		return fmt.Errorf("%s name %q is %d characters, max is %d", constraints.Description, name, len(name), constraints.MaxLength)
+	}
+	if !constraints.Pattern.MatchString(name) {
+		return fmt.Errorf("%s name %q doesn't match %s", constraints.Description, name, constraints.Pattern)
+	}
+	return nil
+}
+
+// randomSuffix returns uniqueSuffixLength characters from a cryptographically secure source, so concurrent callers
+// never share a seed
+func randomSuffix() string {
+	var suffix strings.Builder
+	max := big.NewInt(int64(len(uniqueSuffixChars)))
+
+	for i := 0; i < uniqueSuffixLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(fmt.Sprintf("failed to read random bytes: %v", err))
+		}
+		suffix.WriteByte(uniqueSuffixChars[n.Int64()])
+	}
+
+	return suffix.String()
+}
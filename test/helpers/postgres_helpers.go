@@ -0,0 +1,161 @@
+package helpers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// PostgreSQLConnectionString builds a lib/pq connection string for an RDS PostgreSQL instance
+func PostgreSQLConnectionString(address, port, username, password, dbName string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=require",
+		address, port, username, password, dbName)
+}
+
+// PostgreSQLVerifiedConnectionString builds a lib/pq connection string that verifies the server certificate chains to
+// a CA in rootCertPath, such as the bundle from DownloadRDSCACertBundle, and that it was issued for address.
+// sslmode=require only encrypts, so it would connect to anything presenting a certificate.
+func PostgreSQLVerifiedConnectionString(address, port, username, password, dbName, rootCertPath string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=verify-full sslrootcert='%s'",
+		address, port, username, password, dbName, rootCertPath)
+}
+
+// OpenPostgreSQL opens a lib/pq connection pool and waits until the database accepts connections. The caller must
+// close the returned pool.
+func OpenPostgreSQL(t *testing.T, connStr string, timeout time.Duration) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err, "Failed to open PostgreSQL connection")
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (10 * time.Second)),
+		RetryInterval: 10 * time.Second,
+		Description:   "PostgreSQL connection",
+	}, func() bool {
+		return db.Ping() == nil
+	}, "PostgreSQL did not accept connections within %s", timeout)
+
+	return db
+}
+
+// CreateRoleWithConnectionLimit seeds an application login role that can open at most connectionLimit concurrent
+// connections to dbName, so a single misbehaving service can't exhaust the instance's max_connections
+func CreateRoleWithConnectionLimit(t *testing.T, db *sql.DB, role, password, dbName string, connectionLimit int) {
+	t.Helper()
+
+	// Identifiers and passwords can't be bound as parameters in DDL, so they are quoted explicitly
+	statements := []string{
+		fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD %s CONNECTION LIMIT %d",
+			pq.QuoteIdentifier(role), pq.QuoteLiteral(password), connectionLimit),
+		fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(role)),
+	}
+
+	for _, statement := range statements {
+		_, err := db.Exec(statement)
+		require.NoError(t, err, "Failed to seed role %s", role)
+	}
+
+	t.Logf("✅ Created role %s with CONNECTION LIMIT %d", role, connectionLimit)
+}
+
+// PostgreSQLDatabase is an extra database to create on an instance. When Owner is set, a login role with
+// OwnerPassword is created to own it; otherwise the connected user owns it.
+type PostgreSQLDatabase struct {
+	Name          string
+	Owner         string
+	OwnerPassword string
+}
+
+// CreateDatabases runs CREATE DATABASE for each of databases, as a module's additional_databases are provisioned after
+// the instance is up. CONNECT is revoked from PUBLIC so owner roles can only connect to their own database.
+func CreateDatabases(t *testing.T, db *sql.DB, databases []PostgreSQLDatabase) {
+	t.Helper()
+
+	for _, database := range databases {
+		var statements []string
+		if database.Owner != "" {
+			// RDS master users aren't superusers, so they must be a member of a role to create databases it owns
+			statements = append(statements,
+				fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD %s",
+					pq.QuoteIdentifier(database.Owner), pq.QuoteLiteral(database.OwnerPassword)),
+				fmt.Sprintf("GRANT %s TO CURRENT_USER", pq.QuoteIdentifier(database.Owner)),
+				fmt.Sprintf("CREATE DATABASE %s OWNER %s",
+					pq.QuoteIdentifier(database.Name), pq.QuoteIdentifier(database.Owner)),
+			)
+		} else {
+			statements = append(statements, fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(database.Name)))
+		}
+		statements = append(statements,
+			fmt.Sprintf("REVOKE CONNECT ON DATABASE %s FROM PUBLIC", pq.QuoteIdentifier(database.Name)))
+
+		for _, statement := range statements {
+			_, err := db.Exec(statement)
+			require.NoError(t, err, "Failed to create database %s", database.Name)
+		}
+
+		t.Logf("✅ Created database %s", database.Name)
+	}
+}
+
+// LatencyDistribution is a set of latency samples, sorted from fastest to slowest
+type LatencyDistribution []time.Duration
+
+// Percentile returns the sample at percentile p (0-100) using the nearest-rank method
+func (d LatencyDistribution) Percentile(p float64) time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(d))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(d) {
+		rank = len(d) - 1
+	}
+	return d[rank]
+}
+
+// String summarizes the distribution for logs
+func (d LatencyDistribution) String() string {
+	return fmt.Sprintf("n=%d min=%s p50=%s p90=%s p99=%s max=%s", len(d),
+		d.Percentile(0), d.Percentile(50), d.Percentile(90), d.Percentile(99), d.Percentile(100))
+}
+
+// MeasureCommitLatency inserts count rows, each in its own transaction, and returns how long each COMMIT took. Only
+// the COMMIT is timed, since that is where synchronous replication to a Multi-AZ standby waits. Every sample includes
+// the network round trip from the test to the database, so compare distributions measured from the same place.
+func MeasureCommitLatency(t *testing.T, db *sql.DB, count int) LatencyDistribution {
+	t.Helper()
+
+	// A regular table, since writes to temporary and unlogged tables skip the WAL and so aren't replicated
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS commit_latency (id BIGSERIAL PRIMARY KEY, payload TEXT NOT NULL)")
+	require.NoError(t, err, "Failed to create commit_latency table")
+
+	// Use one connection throughout so connection setup never lands in a sample
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err, "Failed to get a database connection")
+	defer conn.Close()
+
+	latencies := make(LatencyDistribution, 0, count)
+	for i := 0; i < count; i++ {
+		tx, err := conn.BeginTx(context.Background(), nil)
+		require.NoError(t, err, "Failed to begin transaction")
+		_, err = tx.Exec("INSERT INTO commit_latency (payload) VALUES ($1)", fmt.Sprintf("write %d", i))
+		require.NoError(t, err, "Failed to insert row")
+
+		start := time.Now()
+		require.NoError(t, tx.Commit(), "Failed to commit")
+		latencies = append(latencies, time.Since(start))
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	return latencies
+}
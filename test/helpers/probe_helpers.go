@@ -0,0 +1,246 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// probeHandlerSource opens a TCP connection to the requested host and port and reports whether it succeeded
+	probeHandlerSource = `import socket
+
+
+def handler(event, context):
+    try:
+        with socket.create_connection((event["host"], int(event["port"])), timeout=event.get("timeout", 5)):
+            return {"connected": True, "error": ""}
+    except OSError as e:
+        return {"connected": False, "error": str(e)}
+`
+
+	probeRuntime = "python3.12"
+
+	probeAssumeRolePolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "sts:AssumeRole"}]
+}`
+
+	// probeVPCAccessPolicyARN lets the function create the ENIs it needs in the VPC and write its logs
+	probeVPCAccessPolicyARN = "arn:aws:iam::aws:policy/service-role/AWSLambdaVPCAccessExecutionRole"
+)
+
+// lambdaUnsupportedAZIDs are availability zones Lambda can't place VPC functions in. The default VPC has a subnet in
+// every zone, so subnets in these are skipped rather than failing CreateFunction.
+var lambdaUnsupportedAZIDs = map[string]bool{
+	"use1-az3": true,
+}
+
+// ProbeVPCConfig is where a connectivity probe runs. The probe's connections are subject to the security groups'
+// egress rules and the destination's ingress rules exactly as the app's would be, so use the app's security group to
+// check what the app can reach.
+type ProbeVPCConfig struct {
+	Name             string
+	SubnetIDs        []string
+	SecurityGroupIDs []string
+}
+
+// ConnectivityProbe is a Lambda function running inside a VPC that reports whether it can open TCP connections,
+// letting tests check private-subnet reachability without a bastion host
+type ConnectivityProbe struct {
+	sess         *session.Session
+	functionName string
+	roleName     string
+}
+
+// DeployConnectivityProbeLambda deploys a small Python Lambda function into the subnets and security groups in config
+// and waits for it to become active. Defer Destroy on the result; it waits for the function's network interfaces to
+// be released, so the security groups can be deleted afterwards.
+func DeployConnectivityProbeLambda(t *testing.T, sess *session.Session, config ProbeVPCConfig) *ConnectivityProbe {
+	t.Helper()
+
+	probe := &ConnectivityProbe{
+		sess:         sess,
+		functionName: fmt.Sprintf("%s-probe", config.Name),
+		roleName:     fmt.Sprintf("%s-probe", config.Name),
+	}
+
+	subnetIDs := lambdaSupportedSubnets(t, sess, config.SubnetIDs)
+
+	iamClient := iam.New(sess)
+	var iamTags []*iam.Tag
+	for key, value := range RunIDTags() {
+		iamTags = append(iamTags, &iam.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	role, err := iamClient.CreateRole(&iam.CreateRoleInput{
+		RoleName:                 aws.String(probe.roleName),
+		AssumeRolePolicyDocument: aws.String(probeAssumeRolePolicy),
+		Tags:                     iamTags,
+	})
+	require.NoError(t, err, "Failed to create IAM role %s", probe.roleName)
+
+	// The caller can only defer Destroy once the probe is returned, so if a later step fails, clean up the role (and
+	// the function, if it was created) here instead of leaving them behind
+	deployed := false
+	defer func() {
+		if !deployed {
+			probe.Destroy(t)
+		}
+	}()
+
+	_, err = iamClient.AttachRolePolicy(&iam.AttachRolePolicyInput{
+		RoleName:  aws.String(probe.roleName),
+		PolicyArn: aws.String(probeVPCAccessPolicyARN),
+	})
+	require.NoError(t, err, "Failed to attach VPC access policy to %s", probe.roleName)
+
+	input := &lambda.CreateFunctionInput{
+		FunctionName: aws.String(probe.functionName),
+		Runtime:      aws.String(probeRuntime),
+		Handler:      aws.String("probe.handler"),
+		Role:         role.Role.Arn,
+		Code:         &lambda.FunctionCode{ZipFile: probeZip(t)},
+		Timeout:      aws.Int64(30),
+		VpcConfig: &lambda.VpcConfig{
+			SubnetIds:        aws.StringSlice(subnetIDs),
+			SecurityGroupIds: aws.StringSlice(config.SecurityGroupIDs),
+		},
+		Tags: aws.StringMap(RunIDTags()),
+	}
+
+	// A new role takes a few seconds to become assumable by Lambda
+	lambdaClient := lambda.New(sess)
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    12,
+		RetryInterval: 5 * time.Second,
+		Description:   "probe Lambda creation",
+	}, func() bool {
+		_, err = lambdaClient.CreateFunction(input)
+		return err == nil || !strings.Contains(err.Error(), "cannot be assumed")
+	}, "IAM role %s never became assumable by Lambda", probe.roleName)
+	require.NoError(t, err, "Failed to create probe Lambda %s", probe.functionName)
+
+	t.Logf("Waiting for probe Lambda %s to create its network interfaces...", probe.functionName)
+	err = lambdaClient.WaitUntilFunctionActive(&lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(probe.functionName),
+	})
+	require.NoError(t, err, "Probe Lambda %s did not become active", probe.functionName)
+
+	t.Logf("✅ Deployed probe Lambda %s in subnets %v with security groups %v", probe.functionName,
+		subnetIDs, config.SecurityGroupIDs)
+
+	deployed = true
+	return probe
+}
+
+// CanConnect reports whether the probe opened a TCP connection to host:port within five seconds
+func (p *ConnectivityProbe) CanConnect(t *testing.T, host string, port int) bool {
+	t.Helper()
+
+	payload, err := json.Marshal(map[string]interface{}{"host": host, "port": port, "timeout": 5})
+	require.NoError(t, err)
+
+	result, err := lambda.New(p.sess).Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(p.functionName),
+		Payload:      payload,
+	})
+	require.NoError(t, err, "Failed to invoke probe Lambda %s", p.functionName)
+	require.Empty(t, aws.StringValue(result.FunctionError), "Probe Lambda failed: %s", string(result.Payload))
+
+	var response struct {
+		Connected bool   `json:"connected"`
+		Error     string `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(result.Payload, &response), "Unexpected probe response: %s", string(result.Payload))
+
+	if response.Connected {
+		t.Logf("Probe connected to %s:%d", host, port)
+	} else {
+		t.Logf("Probe could not connect to %s:%d: %s", host, port, response.Error)
+	}
+
+	return response.Connected
+}
+
+// Destroy deletes the probe Lambda and its role, then waits for Lambda to release the function's network interfaces.
+// Until they are gone, the probe's security groups and subnets can't be deleted.
+func (p *ConnectivityProbe) Destroy(t *testing.T) {
+	t.Helper()
+
+	_, err := lambda.New(p.sess).DeleteFunction(&lambda.DeleteFunctionInput{FunctionName: aws.String(p.functionName)})
+	if err != nil {
+		t.Logf("Failed to delete probe Lambda %s: %v", p.functionName, err)
+	}
+
+	iamClient := iam.New(p.sess)
+	if _, err := iamClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
+		RoleName:  aws.String(p.roleName),
+		PolicyArn: aws.String(probeVPCAccessPolicyARN),
+	}); err != nil {
+		t.Logf("Failed to detach policy from %s: %v", p.roleName, err)
+	}
+	if _, err := iamClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(p.roleName)}); err != nil {
+		t.Logf("Failed to delete IAM role %s: %v", p.roleName, err)
+	}
+
+	ec2Client := ec2.New(p.sess)
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    50,
+		RetryInterval: 30 * time.Second,
+		Description:   "probe Lambda network interfaces released",
+	}, func() bool {
+		result, err := ec2Client.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("description"),
+					Values: []*string{aws.String(fmt.Sprintf("AWS Lambda VPC ENI-%s-*", p.functionName))},
+				},
+			},
+		})
+		return err == nil && len(result.NetworkInterfaces) == 0
+	}, "Network interfaces of probe Lambda %s were not released", p.functionName)
+}
+
+// lambdaSupportedSubnets drops the subnets in availability zones Lambda doesn't support
+func lambdaSupportedSubnets(t *testing.T, sess *session.Session, subnetIDs []string) []string {
+	result, err := ec2.New(sess).DescribeSubnets(&ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice(subnetIDs)})
+	require.NoError(t, err, "Failed to describe probe subnets")
+
+	var supported []string
+	for _, subnet := range result.Subnets {
+		if lambdaUnsupportedAZIDs[aws.StringValue(subnet.AvailabilityZoneId)] {
+			t.Logf("Skipping subnet %s: Lambda doesn't support %s", aws.StringValue(subnet.SubnetId), aws.StringValue(subnet.AvailabilityZoneId))
+			continue
+		}
+		supported = append(supported, aws.StringValue(subnet.SubnetId))
+	}
+	require.NotEmpty(t, supported, "None of the subnets %v are in an availability zone Lambda supports", subnetIDs)
+
+	return supported
+}
+
+// probeZip packages the probe handler as a Lambda deployment package
+func probeZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	file, err := archive.Create("probe.py")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(probeHandlerSource))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ram"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertResourceShared verifies an active Resource Access Manager share associates resourceArn with
+// principalAccountID, and returns the share's ARN. sess must belong to the account that owns the resource, since only
+// the owner can see a share's principals. The account must be associated directly; shares to an organization or OU
+// are not resolved.
+func AssertResourceShared(t *testing.T, sess *session.Session, resourceArn, principalAccountID string) string {
+	t.Helper()
+
+	ramClient := ram.New(sess)
+
+	resources, err := ramClient.GetResourceShareAssociations(&ram.GetResourceShareAssociationsInput{
+		AssociationType:   aws.String(ram.ResourceShareAssociationTypeResource),
+		ResourceArn:       aws.String(resourceArn),
+		AssociationStatus: aws.String(ram.ResourceShareAssociationStatusAssociated),
+	})
+	require.NoError(t, err, "Failed to get resource share associations for %s", resourceArn)
+
+	var shareArns []*string
+	for _, association := range resources.ResourceShareAssociations {
+		shareArns = append(shareArns, association.ResourceShareArn)
+	}
+	require.NotEmpty(t, shareArns, "%s is not in any resource share", resourceArn)
+
+	principals, err := ramClient.GetResourceShareAssociations(&ram.GetResourceShareAssociationsInput{
+		AssociationType:   aws.String(ram.ResourceShareAssociationTypePrincipal),
+		ResourceShareArns: shareArns,
+		Principal:         aws.String(principalAccountID),
+		AssociationStatus: aws.String(ram.ResourceShareAssociationStatusAssociated),
+	})
+	require.NoError(t, err, "Failed to get principal associations for the shares of %s", resourceArn)
+
+	for _, association := range principals.ResourceShareAssociations {
+		shareArn := aws.StringValue(association.ResourceShareArn)
+
+		shares, err := ramClient.GetResourceShares(&ram.GetResourceSharesInput{
+			ResourceOwner:     aws.String(ram.ResourceOwnerSelf),
+			ResourceShareArns: []*string{association.ResourceShareArn},
+		})
+		require.NoError(t, err, "Failed to describe resource share %s", shareArn)
+
+		if len(shares.ResourceShares) == 1 && aws.StringValue(shares.ResourceShares[0].Status) == ram.ResourceShareStatusActive {
+			t.Logf("✅ %s is shared with account %s by %s (%s)", resourceArn, principalAccountID, shareArn,
+				aws.StringValue(shares.ResourceShares[0].Name))
+			return shareArn
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("%s is not shared with account %s by any active resource share", resourceArn, principalAccountID))
+	return ""
+}
+
+// GetAccountID returns the ID of the account the session's credentials belong to
+func GetAccountID(t *testing.T, sess *session.Session) string {
+	t.Helper()
+
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	require.NoError(t, err, "Failed to get caller identity")
+
+	return aws.StringValue(identity.Account)
+}
@@ -0,0 +1,323 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/stretchr/testify/require"
+)
+
+// Blue/green deployment statuses
+const (
+	BlueGreenStatusAvailable           = "AVAILABLE"
+	BlueGreenStatusSwitchoverCompleted = "SWITCHOVER_COMPLETED"
+)
+
+// RDSBlueGreenDeployment is an RDS blue/green deployment. The vendored SDK predates blue/green deployments, so the
+// RDS API is called directly and the responses are decoded into local structs.
+type RDSBlueGreenDeployment struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeploymentIdentifier *string `type:"string"`
+	BlueGreenDeploymentName       *string `type:"string"`
+	Source                        *string `type:"string"`
+	Target                        *string `type:"string"`
+	Status                        *string `type:"string"`
+	StatusDetails                 *string `type:"string"`
+	SwitchoverDetails             []*struct {
+		SourceMember *string `type:"string"`
+		TargetMember *string `type:"string"`
+		Status       *string `type:"string"`
+	} `type:"list"`
+}
+
+type createBlueGreenDeploymentInput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeploymentName *string `type:"string"`
+	Source                  *string `type:"string"`
+	TargetEngineVersion     *string `type:"string"`
+}
+
+type blueGreenDeploymentOutput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeployment *RDSBlueGreenDeployment `type:"structure"`
+}
+
+type describeBlueGreenDeploymentsInput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeploymentIdentifier *string `type:"string"`
+}
+
+type describeBlueGreenDeploymentsOutput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeployments []*RDSBlueGreenDeployment `type:"list"`
+}
+
+type switchoverBlueGreenDeploymentInput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeploymentIdentifier *string `type:"string"`
+	SwitchoverTimeout             *int64  `type:"integer"`
+}
+
+type deleteBlueGreenDeploymentInput struct {
+	_ struct{} `type:"structure"`
+
+	BlueGreenDeploymentIdentifier *string `type:"string"`
+	DeleteTarget                  *bool   `type:"boolean"`
+}
+
+// sendRDSRequest calls an RDS API operation that the vendored SDK doesn't know about
+func sendRDSRequest(sess *session.Session, operation string, input, output interface{}) error {
+	rdsClient := rds.New(sess)
+
+	req := rdsClient.NewRequest(&request.Operation{
+		Name:       operation,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, input, output)
+
+	return req.Send()
+}
+
+// CreateRDSBlueGreenDeployment creates a blue/green deployment that copies the source DB instance into a green
+// environment running targetEngineVersion, kept in sync with logical replication. The source must use a parameter
+// group with rds.logical_replication = 1.
+func CreateRDSBlueGreenDeployment(t *testing.T, sess *session.Session, name, sourceARN, targetEngineVersion string) *RDSBlueGreenDeployment {
+	t.Helper()
+
+	output := &blueGreenDeploymentOutput{}
+	err := sendRDSRequest(sess, "CreateBlueGreenDeployment", &createBlueGreenDeploymentInput{
+		BlueGreenDeploymentName: aws.String(name),
+		Source:                  aws.String(sourceARN),
+		TargetEngineVersion:     aws.String(targetEngineVersion),
+	}, output)
+	require.NoError(t, err, "Failed to create blue/green deployment %s", name)
+	require.NotNil(t, output.BlueGreenDeployment, "CreateBlueGreenDeployment returned no deployment")
+
+	t.Logf("✅ Created blue/green deployment %s (%s) targeting engine version %s",
+		name, aws.StringValue(output.BlueGreenDeployment.BlueGreenDeploymentIdentifier), targetEngineVersion)
+
+	return output.BlueGreenDeployment
+}
+
+// DescribeRDSBlueGreenDeployment returns the current state of a blue/green deployment
+func DescribeRDSBlueGreenDeployment(t *testing.T, sess *session.Session, deploymentID string) *RDSBlueGreenDeployment {
+	t.Helper()
+
+	output := &describeBlueGreenDeploymentsOutput{}
+	err := sendRDSRequest(sess, "DescribeBlueGreenDeployments", &describeBlueGreenDeploymentsInput{
+		BlueGreenDeploymentIdentifier: aws.String(deploymentID),
+	}, output)
+	require.NoError(t, err, "Failed to describe blue/green deployment %s", deploymentID)
+	require.Len(t, output.BlueGreenDeployments, 1, "Blue/green deployment %s not found", deploymentID)
+
+	return output.BlueGreenDeployments[0]
+}
+
+// WaitForBlueGreenDeploymentStatus waits until a blue/green deployment reaches the given status. Failure statuses
+// such as INVALID_CONFIGURATION and SWITCHOVER_FAILED fail immediately.
+func WaitForBlueGreenDeploymentStatus(t *testing.T, sess *session.Session, deploymentID, status string, timeout time.Duration) *RDSBlueGreenDeployment {
+	t.Helper()
+
+	pollInterval := 30 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		deployment := DescribeRDSBlueGreenDeployment(t, sess, deploymentID)
+		current := aws.StringValue(deployment.Status)
+
+		if current == status {
+			t.Logf("✅ Blue/green deployment %s is %s", deploymentID, status)
+			return deployment
+		}
+
+		if current == "INVALID_CONFIGURATION" || strings.HasSuffix(current, "_FAILED") {
+			require.Fail(t, fmt.Sprintf("Blue/green deployment %s is %s: %s", deploymentID, current, aws.StringValue(deployment.StatusDetails)))
+		}
+
+		t.Logf("Blue/green deployment %s is %s, waiting for %s...", deploymentID, current, status)
+		time.Sleep(pollInterval)
+	}
+
+	require.Fail(t, fmt.Sprintf("Blue/green deployment %s did not reach %s within %s", deploymentID, status, timeout))
+	return nil
+}
+
+// SwitchoverBlueGreen promotes the green environment of an available blue/green deployment and waits for the
+// switchover to complete. The green DB instance takes over the blue instance's identifier and endpoint, and the blue
+// instance is renamed with an -old1 suffix.
+func SwitchoverBlueGreen(t *testing.T, sess *session.Session, deploymentID string, timeout time.Duration) *RDSBlueGreenDeployment {
+	t.Helper()
+
+	err := sendRDSRequest(sess, "SwitchoverBlueGreenDeployment", &switchoverBlueGreenDeploymentInput{
+		BlueGreenDeploymentIdentifier: aws.String(deploymentID),
+		SwitchoverTimeout:             aws.Int64(int64(timeout.Seconds())),
+	}, &blueGreenDeploymentOutput{})
+	require.NoError(t, err, "Failed to start switchover of blue/green deployment %s", deploymentID)
+	t.Logf("Started switchover of blue/green deployment %s", deploymentID)
+
+	return WaitForBlueGreenDeploymentStatus(t, sess, deploymentID, BlueGreenStatusSwitchoverCompleted, timeout+5*time.Minute)
+}
+
+// DeleteRDSBlueGreenDeployment deletes a blue/green deployment. The DB instances are left in place; use
+// DeleteRDSInstanceAndWait to remove whichever one is no longer needed.
+func DeleteRDSBlueGreenDeployment(t *testing.T, sess *session.Session, deploymentID string) {
+	t.Helper()
+
+	err := sendRDSRequest(sess, "DeleteBlueGreenDeployment", &deleteBlueGreenDeploymentInput{
+		BlueGreenDeploymentIdentifier: aws.String(deploymentID),
+		DeleteTarget:                  aws.Bool(false),
+	}, &blueGreenDeploymentOutput{})
+	require.NoError(t, err, "Failed to delete blue/green deployment %s", deploymentID)
+	t.Logf("✅ Deleted blue/green deployment %s", deploymentID)
+}
+
+// DeleteRDSInstanceAndWait deletes a DB instance without a final snapshot and waits until it is gone, so the subnet
+// and parameter groups it uses can be destroyed afterwards
+func DeleteRDSInstanceAndWait(t *testing.T, sess *session.Session, dbIdentifier string, timeout time.Duration) {
+	t.Helper()
+
+	rdsClient := rds.New(sess)
+
+	_, err := rdsClient.DeleteDBInstance(&rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier:   aws.String(dbIdentifier),
+		SkipFinalSnapshot:      aws.Bool(true),
+		DeleteAutomatedBackups: aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to delete DB instance %s", dbIdentifier)
+
+	t.Logf("Deleting DB instance %s...", dbIdentifier)
+	err = rdsClient.WaitUntilDBInstanceDeletedWithContext(aws.BackgroundContext(), &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbIdentifier),
+	}, request.WithWaiterDelay(request.ConstantWaiterDelay(30*time.Second)),
+		request.WithWaiterMaxAttempts(int(timeout/(30*time.Second))))
+	require.NoError(t, err, "DB instance %s was not deleted within %s", dbIdentifier, timeout)
+
+	t.Logf("✅ Deleted DB instance %s", dbIdentifier)
+}
+
+// AssertRDSUsesCustomParameterGroup fails if the DB instance uses one of the AWS default.postgres* parameter groups,
+// which can't be modified, so tuning any parameter would first mean switching groups and rebooting. It returns the name
+// of the instance's parameter group.
+func AssertRDSUsesCustomParameterGroup(t *testing.T, sess *session.Session, dbIdentifier string) string {
+	t.Helper()
+
+	result, err := rds.New(sess).DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbIdentifier),
+	})
+	require.NoError(t, err, "Failed to describe DB instance %s", dbIdentifier)
+	require.Len(t, result.DBInstances, 1, "DB instance %s not found", dbIdentifier)
+
+	groups := result.DBInstances[0].DBParameterGroups
+	require.Len(t, groups, 1, "DB instance %s should have exactly one parameter group", dbIdentifier)
+
+	groupName := aws.StringValue(groups[0].DBParameterGroupName)
+	require.False(t, strings.HasPrefix(groupName, "default."),
+		"DB instance %s uses the default parameter group %s, which can't be modified", dbIdentifier, groupName)
+
+	t.Logf("✅ DB instance %s uses custom parameter group %s (%s)", dbIdentifier, groupName, aws.StringValue(groups[0].ParameterApplyStatus))
+
+	return groupName
+}
+
+// AssertRDSEncrypted fails unless the DB instance's storage is encrypted, and returns the ARN of the KMS key encrypting
+// it so callers can check it is the key they passed in
+func AssertRDSEncrypted(t *testing.T, sess *session.Session, dbIdentifier string) string {
+	t.Helper()
+
+	result, err := rds.New(sess).DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbIdentifier),
+	})
+	require.NoError(t, err, "Failed to describe DB instance %s", dbIdentifier)
+	require.Len(t, result.DBInstances, 1, "DB instance %s not found", dbIdentifier)
+	instance := result.DBInstances[0]
+
+	require.True(t, aws.BoolValue(instance.StorageEncrypted), "DB instance %s storage is not encrypted", dbIdentifier)
+	require.NotEmpty(t, aws.StringValue(instance.KmsKeyId), "DB instance %s is encrypted but has no KMS key", dbIdentifier)
+
+	t.Logf("✅ DB instance %s storage is encrypted with %s", dbIdentifier, aws.StringValue(instance.KmsKeyId))
+
+	return aws.StringValue(instance.KmsKeyId)
+}
+
+// DBInstanceIdentifierFromARN returns the DB instance identifier from an RDS DB instance ARN
+func DBInstanceIdentifierFromARN(dbARN string) string {
+	return dbARN[strings.LastIndex(dbARN, ":")+1:]
+}
+
+// RestoreRDSToPointInTime restores a DB instance's automated backups, as of restoreTime, into a new single-AZ instance
+// named targetIdentifier in the source's subnet group and security groups, and waits until it is available. RDS can
+// only restore up to the source's latest restorable time, which trails the present by about 5 minutes, so this first
+// waits for that to pass restoreTime. Use DeleteRDSInstanceAndWait to remove the restored instance.
+func RestoreRDSToPointInTime(t *testing.T, sess *session.Session, sourceIdentifier, targetIdentifier string, restoreTime time.Time, timeout time.Duration) *rds.DBInstance {
+	t.Helper()
+
+	rdsClient := rds.New(sess)
+	var source *rds.DBInstance
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    20,
+		RetryInterval: 30 * time.Second,
+		Description:   "latest restorable time",
+	}, func() bool {
+		result, err := rdsClient.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(sourceIdentifier),
+		})
+		if err != nil || len(result.DBInstances) != 1 {
+			t.Logf("Failed to describe DB instance %s: %v", sourceIdentifier, err)
+			return false
+		}
+		source = result.DBInstances[0]
+		return !aws.TimeValue(source.LatestRestorableTime).Before(restoreTime)
+	}, "DB instance %s latest restorable time reached %s", sourceIdentifier, restoreTime.Format(time.RFC3339))
+
+	var securityGroupIDs []*string
+	for _, group := range source.VpcSecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, group.VpcSecurityGroupId)
+	}
+
+	var tags []*rds.Tag
+	for key, value := range RunIDTags() {
+		tags = append(tags, &rds.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	_, err := rdsClient.RestoreDBInstanceToPointInTime(&rds.RestoreDBInstanceToPointInTimeInput{
+		SourceDBInstanceIdentifier: aws.String(sourceIdentifier),
+		TargetDBInstanceIdentifier: aws.String(targetIdentifier),
+		RestoreTime:                aws.Time(restoreTime),
+		DBInstanceClass:            source.DBInstanceClass,
+		DBSubnetGroupName:          source.DBSubnetGroup.DBSubnetGroupName,
+		VpcSecurityGroupIds:        securityGroupIDs,
+		PubliclyAccessible:         source.PubliclyAccessible,
+		MultiAZ:                    aws.Bool(false),
+		Tags:                       tags,
+	})
+	require.NoError(t, err, "Failed to restore DB instance %s to %s", sourceIdentifier, restoreTime.Format(time.RFC3339))
+
+	t.Logf("Restoring DB instance %s to %s as %s...", sourceIdentifier, restoreTime.Format(time.RFC3339), targetIdentifier)
+	describeTarget := &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(targetIdentifier)}
+	err = rdsClient.WaitUntilDBInstanceAvailableWithContext(aws.BackgroundContext(), describeTarget,
+		request.WithWaiterDelay(request.ConstantWaiterDelay(30*time.Second)),
+		request.WithWaiterMaxAttempts(int(timeout/(30*time.Second))))
+	require.NoError(t, err, "Restored DB instance %s did not become available within %s", targetIdentifier, timeout)
+
+	result, err := rdsClient.DescribeDBInstances(describeTarget)
+	require.NoError(t, err, "Failed to describe restored DB instance %s", targetIdentifier)
+	require.Len(t, result.DBInstances, 1)
+
+	t.Logf("✅ Restored DB instance %s is available at %s", targetIdentifier,
+		aws.StringValue(result.DBInstances[0].Endpoint.Address))
+
+	return result.DBInstances[0]
+}
@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+// WaitForRedisReplicaValue reads key through the reader endpoint at readerAddr (host:port) until it returns want.
+// Replication to the replicas is asynchronous, so a value written to the primary shows up there after a short lag.
+func WaitForRedisReplicaValue(t *testing.T, readerAddr, key, want string, cfg RetryConfig) {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        readerAddr,
+		DialTimeout: 10 * time.Second,
+		ReadTimeout: 10 * time.Second,
+	})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	RetryUntilNoError(t, cfg, func() error {
+		got, err := rdb.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("replica has %s = %q, want %q", key, got, want)
+		}
+		return nil
+	})
+}
+
+// WaitForRedisSnapshot polls until the replication group has a manual snapshot, such as one started with CreateSnapshot,
+// that is available, and returns it. Automatic snapshots are ignored, since they only run in the daily window.
+func WaitForRedisSnapshot(t *testing.T, sess *session.Session, replicationGroupID string, cfg RetryConfig) *elasticache.Snapshot {
+	t.Helper()
+
+	client := elasticache.New(sess)
+	var snapshot *elasticache.Snapshot
+
+	WaitForCondition(t, cfg, func() bool {
+		result, err := client.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{
+			ReplicationGroupId: aws.String(replicationGroupID),
+			SnapshotSource:     aws.String("manual"),
+		})
+		if err != nil {
+			t.Logf("Failed to describe snapshots: %v", err)
+			return false
+		}
+
+		for _, candidate := range result.Snapshots {
+			status := aws.StringValue(candidate.SnapshotStatus)
+			require.NotEqual(t, "failed", status, "Snapshot %s failed", aws.StringValue(candidate.SnapshotName))
+			if status == "available" {
+				snapshot = candidate
+				return true
+			}
+		}
+		return false
+	}, "a manual snapshot of replication group %s to become available", replicationGroupID)
+
+	return snapshot
+}
+
+// WaitForReplicationGroupModification polls a replication group through a modification, such as one started with
+// IncreaseReplicaCount, until it goes from modifying back to available, and returns the group. It fails if the group
+// is already available when polling starts without having been seen modifying, since then there was nothing to wait for.
+func WaitForReplicationGroupModification(t *testing.T, sess *session.Session, replicationGroupID string, cfg RetryConfig) *elasticache.ReplicationGroup {
+	t.Helper()
+
+	client := elasticache.New(sess)
+	var group *elasticache.ReplicationGroup
+	sawModifying := false
+
+	WaitForCondition(t, cfg, func() bool {
+		result, err := client.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(replicationGroupID),
+		})
+		if err != nil {
+			t.Logf("Failed to describe replication group %s: %v", replicationGroupID, err)
+			return false
+		}
+		require.Len(t, result.ReplicationGroups, 1, "Replication group %s not found", replicationGroupID)
+		group = result.ReplicationGroups[0]
+
+		switch status := aws.StringValue(group.Status); status {
+		case "modifying":
+			sawModifying = true
+			return false
+		case "available":
+			require.True(t, sawModifying, "Replication group %s was available before it was seen modifying", replicationGroupID)
+			return true
+		default:
+			require.Fail(t, fmt.Sprintf("Replication group %s entered status %s while modifying", replicationGroupID, status))
+			return false
+		}
+	}, "replication group %s to finish modifying", replicationGroupID)
+
+	return group
+}
@@ -0,0 +1,214 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/require"
+)
+
+// ECSScaleOutTimeline records when each stage of an autoscaling scale-out happened, from the service's CPU first
+// crossing the target to the new task passing its load balancer health check
+type ECSScaleOutTimeline struct {
+	ThresholdBreached time.Time
+	AlarmTriggered    time.Time
+	ScalingStarted    time.Time
+	TaskRunning       time.Time
+	TargetHealthy     time.Time
+}
+
+// Total is the time from the threshold breach to the new target becoming healthy, which is when the ALB starts routing
+// traffic to it
+func (tl ECSScaleOutTimeline) Total() time.Duration {
+	return tl.TargetHealthy.Sub(tl.ThresholdBreached)
+}
+
+// String breaks the total down by stage
+func (tl ECSScaleOutTimeline) String() string {
+	return fmt.Sprintf("breach → alarm %s, alarm → scaling activity %s, scaling activity → task RUNNING %s, task RUNNING → target healthy %s (total %s)",
+		tl.AlarmTriggered.Sub(tl.ThresholdBreached),
+		tl.ScalingStarted.Sub(tl.AlarmTriggered),
+		tl.TaskRunning.Sub(tl.ScalingStarted),
+		tl.TargetHealthy.Sub(tl.TaskRunning),
+		tl.Total())
+}
+
+// MeasureECSScaleOut waits for a task the service started after since to pass its target group health check, then
+// reconstructs when each earlier stage of the scale-out happened from CloudWatch and Application Auto Scaling history.
+// The healthy transition is observed by polling, so it is accurate to within the 5 second poll interval; CloudWatch
+// only knows the CPU breach to the minute.
+func MeasureECSScaleOut(t *testing.T, sess *session.Session, clusterName, serviceName, tgArn string, targetCPU float64, since time.Time, timeout time.Duration) ECSScaleOutTimeline {
+	t.Helper()
+
+	ecsClient := ecs.New(sess)
+	elbClient := elbv2.New(sess)
+	pollInterval := 5 * time.Second
+
+	var timeline ECSScaleOutTimeline
+	var scaledOut *ecs.Task
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / pollInterval),
+		RetryInterval: pollInterval,
+		Description:   "scaled-out task healthy",
+	}, func() bool {
+		healthyIPs, err := healthyTargetIDs(elbClient, tgArn)
+		if err != nil {
+			t.Logf("Failed to describe target health: %v", err)
+			return false
+		}
+
+		for _, task := range describeECSTasks(t, ecsClient, clusterName, serviceName, ecs.DesiredStatusRunning) {
+			if aws.TimeValue(task.CreatedAt).Before(since) {
+				continue
+			}
+			if healthyIPs[ecsTaskPrivateIP(task)] {
+				scaledOut = task
+				timeline.TargetHealthy = time.Now()
+				return true
+			}
+		}
+		return false
+	}, "a task started by scaling out service %s to pass its health check", serviceName)
+
+	timeline.TaskRunning = aws.TimeValue(scaledOut.StartedAt)
+	timeline.ScalingStarted = firstECSScalingEventAfter(t, sess, clusterName, serviceName, since)
+	timeline.AlarmTriggered = firstScaleOutAlarmAfter(t, sess, clusterName, serviceName, since)
+	timeline.ThresholdBreached = firstECSCPUBreachAfter(t, sess, clusterName, serviceName, targetCPU, since)
+
+	t.Logf("Scale-out of %s: %s", serviceName, timeline)
+
+	return timeline
+}
+
+// healthyTargetIDs returns the IDs of the healthy targets in a target group, which are task IPs for Fargate services
+func healthyTargetIDs(client *elbv2.ELBV2, tgArn string) (map[string]bool, error) {
+	result, err := client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(tgArn)})
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := map[string]bool{}
+	for _, target := range result.TargetHealthDescriptions {
+		if aws.StringValue(target.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+			healthy[aws.StringValue(target.Target.Id)] = true
+		}
+	}
+	return healthy, nil
+}
+
+// ecsTaskPrivateIP returns the private IP of an awsvpc task's network interface, or "" if it has none yet
+func ecsTaskPrivateIP(task *ecs.Task) string {
+	for _, attachment := range task.Attachments {
+		for _, detail := range attachment.Details {
+			if aws.StringValue(detail.Name) == "privateIPv4Address" {
+				return aws.StringValue(detail.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// firstECSScalingEventAfter returns the start of the first scaling activity on the service after since
+func firstECSScalingEventAfter(t *testing.T, sess *session.Session, clusterName, serviceName string, since time.Time) time.Time {
+	t.Helper()
+
+	for _, event := range GetECSScalingEvents(t, sess, clusterName, serviceName) {
+		if event.Start.After(since) {
+			return event.Start
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("No scaling activity on service %s after %s", serviceName, since.Format(time.RFC3339)))
+	return time.Time{}
+}
+
+// firstScaleOutAlarmAfter returns when the high alarm of the service's target tracking policy first went into ALARM
+// after since. Target tracking creates a high alarm to scale out and a low alarm to scale in; only the high one matters.
+func firstScaleOutAlarmAfter(t *testing.T, sess *session.Session, clusterName, serviceName string, since time.Time) time.Time {
+	t.Helper()
+
+	resourceID := ecsServiceResourceID(clusterName, serviceName)
+	policies, err := applicationautoscaling.New(sess).DescribeScalingPolicies(&applicationautoscaling.DescribeScalingPoliciesInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceId:       aws.String(resourceID),
+	})
+	require.NoError(t, err, "Failed to describe scaling policies for %s", resourceID)
+
+	cwClient := cloudwatch.New(sess)
+	var triggered []time.Time
+	for _, policy := range policies.ScalingPolicies {
+		for _, alarm := range policy.Alarms {
+			alarmName := aws.StringValue(alarm.AlarmName)
+			if !strings.Contains(alarmName, "AlarmHigh") {
+				continue
+			}
+
+			err := cwClient.DescribeAlarmHistoryPages(&cloudwatch.DescribeAlarmHistoryInput{
+				AlarmName:       aws.String(alarmName),
+				HistoryItemType: aws.String(cloudwatch.HistoryItemTypeStateUpdate),
+				StartDate:       aws.Time(since),
+			}, func(page *cloudwatch.DescribeAlarmHistoryOutput, lastPage bool) bool {
+				for _, item := range page.AlarmHistoryItems {
+					var data struct {
+						NewState struct {
+							StateValue string `json:"stateValue"`
+						} `json:"newState"`
+					}
+					if json.Unmarshal([]byte(aws.StringValue(item.HistoryData)), &data) == nil &&
+						data.NewState.StateValue == cloudwatch.StateValueAlarm {
+						triggered = append(triggered, aws.TimeValue(item.Timestamp))
+					}
+				}
+				return true
+			})
+			require.NoError(t, err, "Failed to describe history of alarm %s", alarmName)
+		}
+	}
+	require.NotEmpty(t, triggered, "No scale-out alarm of service %s went into ALARM after %s", serviceName, since.Format(time.RFC3339))
+
+	sort.Slice(triggered, func(i, j int) bool { return triggered[i].Before(triggered[j]) })
+	return triggered[0]
+}
+
+// firstECSCPUBreachAfter returns the start of the first minute after since in which the service's average CPU was
+// above targetCPU
+func firstECSCPUBreachAfter(t *testing.T, sess *session.Session, clusterName, serviceName string, targetCPU float64, since time.Time) time.Time {
+	t.Helper()
+
+	result, err := cloudwatch.New(sess).GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/ECS"),
+		MetricName: aws.String("CPUUtilization"),
+		Dimensions: []*cloudwatch.Dimension{
+			{Name: aws.String("ClusterName"), Value: aws.String(clusterName)},
+			{Name: aws.String("ServiceName"), Value: aws.String(serviceName)},
+		},
+		StartTime:  aws.Time(since.Truncate(time.Minute)),
+		EndTime:    aws.Time(time.Now()),
+		Period:     aws.Int64(60),
+		Statistics: []*string{aws.String(cloudwatch.StatisticAverage)},
+	})
+	require.NoError(t, err, "Failed to get CPU utilization of service %s", serviceName)
+
+	sort.Slice(result.Datapoints, func(i, j int) bool {
+		return aws.TimeValue(result.Datapoints[i].Timestamp).Before(aws.TimeValue(result.Datapoints[j].Timestamp))
+	})
+	for _, datapoint := range result.Datapoints {
+		if aws.Float64Value(datapoint.Average) > targetCPU {
+			return aws.TimeValue(datapoint.Timestamp)
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("CPU of service %s never rose above %.0f%% after %s", serviceName, targetCPU, since.Format(time.RFC3339)))
+	return time.Time{}
+}
@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// Secrets Manager version staging labels
+const (
+	secretStageCurrent = "AWSCURRENT"
+	secretStagePending = "AWSPENDING"
+)
+
+// RDSMasterUserCredentials is the JSON RDS stores in the secret of an instance with a managed master password
+type RDSMasterUserCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// GetRDSMasterUserCredentials reads the current master credentials from an RDS-managed secret
+func GetRDSMasterUserCredentials(t *testing.T, sess *session.Session, secretARN string) RDSMasterUserCredentials {
+	t.Helper()
+
+	result, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretARN),
+	})
+	require.NoError(t, err, "Failed to read secret %s", secretARN)
+
+	var credentials RDSMasterUserCredentials
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(result.SecretString)), &credentials),
+		"Secret %s is not RDS master user credentials", secretARN)
+	require.NotEmpty(t, credentials.Password, "Secret %s has no password", secretARN)
+
+	return credentials
+}
+
+// AssertSecretRotationScheduled asserts the secret has rotation enabled on a schedule and returns its description.
+// RDS-managed secrets are rotated by RDS itself, so they have no RotationLambdaARN.
+func AssertSecretRotationScheduled(t *testing.T, sess *session.Session, secretARN string) *secretsmanager.DescribeSecretOutput {
+	t.Helper()
+
+	secret, err := secretsmanager.New(sess).DescribeSecret(&secretsmanager.DescribeSecretInput{SecretId: aws.String(secretARN)})
+	require.NoError(t, err, "Failed to describe secret %s", secretARN)
+
+	require.True(t, aws.BoolValue(secret.RotationEnabled), "Secret %s does not have rotation enabled", secretARN)
+	require.NotNil(t, secret.RotationRules, "Secret %s has no rotation rules", secretARN)
+	require.True(t,
+		aws.Int64Value(secret.RotationRules.AutomaticallyAfterDays) > 0 || aws.StringValue(secret.RotationRules.ScheduleExpression) != "",
+		"Secret %s has no rotation schedule", secretARN)
+
+	t.Logf("✅ Secret %s rotates automatically (every %d days, schedule %q)", secretARN,
+		aws.Int64Value(secret.RotationRules.AutomaticallyAfterDays), aws.StringValue(secret.RotationRules.ScheduleExpression))
+
+	return secret
+}
+
+// RotateSecretAndWait starts an immediate rotation and waits until a new version is AWSCURRENT and no version is left
+// AWSPENDING, which is when the rotation has finished setting and testing the new value
+func RotateSecretAndWait(t *testing.T, sess *session.Session, secretARN string, timeout time.Duration) {
+	t.Helper()
+
+	client := secretsmanager.New(sess)
+	previousVersion := currentSecretVersion(t, client, secretARN)
+
+	_, err := client.RotateSecret(&secretsmanager.RotateSecretInput{SecretId: aws.String(secretARN)})
+	require.NoError(t, err, "Failed to start rotation of secret %s", secretARN)
+	t.Logf("Started rotation of secret %s", secretARN)
+
+	WaitForCondition(t, RetryConfig{
+		MaxRetries:    int(timeout / (15 * time.Second)),
+		RetryInterval: 15 * time.Second,
+		Description:   "secret rotation",
+	}, func() bool {
+		secret, err := client.DescribeSecret(&secretsmanager.DescribeSecretInput{SecretId: aws.String(secretARN)})
+		if err != nil {
+			t.Logf("Failed to describe secret: %v", err)
+			return false
+		}
+
+		rotated := false
+		for versionID, stages := range secret.VersionIdsToStages {
+			for _, stage := range aws.StringValueSlice(stages) {
+				if stage == secretStagePending {
+					return false
+				}
+				if stage == secretStageCurrent && versionID != previousVersion {
+					rotated = true
+				}
+			}
+		}
+		return rotated
+	}, "Secret %s was not rotated within %s", secretARN, timeout)
+
+	t.Logf("✅ Rotated secret %s", secretARN)
+}
+
+// currentSecretVersion returns the ID of the secret's AWSCURRENT version
+func currentSecretVersion(t *testing.T, client *secretsmanager.SecretsManager, secretARN string) string {
+	secret, err := client.DescribeSecret(&secretsmanager.DescribeSecretInput{SecretId: aws.String(secretARN)})
+	require.NoError(t, err, "Failed to describe secret %s", secretARN)
+
+	for versionID, stages := range secret.VersionIdsToStages {
+		for _, stage := range aws.StringValueSlice(stages) {
+			if stage == secretStageCurrent {
+				return versionID
+			}
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("Secret %s has no AWSCURRENT version", secretARN))
+	return ""
+}
+
+// CreateTestSecret creates a Secrets Manager secret holding value and returns its ARN. Pair it with DeleteTestSecret.
+func CreateTestSecret(t *testing.T, sess *session.Session, name, value string) string {
+	t.Helper()
+
+	result, err := secretsmanager.New(sess).CreateSecret(&secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+		Description:  aws.String("Temporary secret created by Terratest"),
+	})
+	require.NoError(t, err, "Failed to create secret %s", name)
+
+	t.Logf("Created secret %s", aws.StringValue(result.ARN))
+	return aws.StringValue(result.ARN)
+}
+
+// DeleteTestSecret deletes a secret immediately, skipping the recovery window so its name can be reused
+func DeleteTestSecret(t *testing.T, sess *session.Session, secretARN string) {
+	t.Helper()
+
+	_, err := secretsmanager.New(sess).DeleteSecret(&secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(secretARN),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	if err != nil {
+		t.Logf("Failed to delete secret %s: %v", secretARN, err)
+	}
+}
@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// SizingPolicy is the largest, and so most expensive, configuration a plan may create. Zero values don't limit
+// anything. Instance classes and node types are compared by size (large, xlarge, 2xlarge, ...) regardless of family,
+// so "db.r6g.2xlarge" also caps db.m6g and db.t4g instances at 2xlarge.
+type SizingPolicy struct {
+	// MaxRDSInstanceClass is the largest RDS instance class, e.g. "db.r6g.2xlarge"
+	MaxRDSInstanceClass string
+	// MaxRDSStorageGB caps both allocated_storage and the max_allocated_storage autoscaling limit
+	MaxRDSStorageGB int
+	// MaxElastiCacheNodeType is the largest ElastiCache node type, e.g. "cache.r6g.xlarge"
+	MaxElastiCacheNodeType string
+	// RequireS3LifecycleRules requires every bucket to have an enabled lifecycle rule in the same module
+	RequireS3LifecycleRules bool
+}
+
+// AssertResourceSizingWithinPolicy fails if any resource the plan creates or updates is larger than the policy allows.
+// Use it as a plan-time gate so an oversized instance is caught before it is applied and billed.
+func AssertResourceSizingWithinPolicy(t *testing.T, plan *terraform.PlanStruct, policy SizingPolicy) {
+	t.Helper()
+
+	violations := FindSizingViolations(plan, policy)
+	if assert.Empty(t, violations, "Plan exceeds the sizing policy:\n%s", strings.Join(violations, "\n")) {
+		t.Logf("✅ Plan is within the sizing policy (%d resource changes inspected)", len(plan.ResourceChangesMap))
+	}
+}
+
+// FindSizingViolations returns a description of every way the resources a plan creates or updates exceed the policy,
+// sorted by address. Values that aren't known until apply are skipped.
+func FindSizingViolations(plan *terraform.PlanStruct, policy SizingPolicy) []string {
+	var violations []string
+	bucketModules := map[string]string{}
+	lifecycleModules := map[string]bool{}
+
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil || change.Change.Actions.Delete() {
+			continue
+		}
+		after, ok := change.Change.After.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch change.Type {
+		case "aws_db_instance":
+			if class, ok := after["instance_class"].(string); ok && exceedsInstanceSize(class, policy.MaxRDSInstanceClass) {
+				violations = append(violations, fmt.Sprintf("%s: instance_class %s is larger than %s", address, class, policy.MaxRDSInstanceClass))
+			}
+			for _, attribute := range []string{"allocated_storage", "max_allocated_storage"} {
+				if size, ok := after[attribute].(float64); ok && policy.MaxRDSStorageGB > 0 && int(size) > policy.MaxRDSStorageGB {
+					violations = append(violations, fmt.Sprintf("%s: %s %d GB is more than %d GB", address, attribute, int(size), policy.MaxRDSStorageGB))
+				}
+			}
+		case "aws_elasticache_replication_group", "aws_elasticache_cluster":
+			if nodeType, ok := after["node_type"].(string); ok && exceedsInstanceSize(nodeType, policy.MaxElastiCacheNodeType) {
+				violations = append(violations, fmt.Sprintf("%s: node_type %s is larger than %s", address, nodeType, policy.MaxElastiCacheNodeType))
+			}
+		case "aws_s3_bucket":
+			bucketModules[address] = change.ModuleAddress
+		case "aws_s3_bucket_lifecycle_configuration":
+			if hasEnabledLifecycleRule(after) {
+				lifecycleModules[change.ModuleAddress] = true
+			}
+		}
+	}
+
+	if policy.RequireS3LifecycleRules {
+		for address, module := range bucketModules {
+			if !lifecycleModules[module] {
+				violations = append(violations, fmt.Sprintf("%s: bucket has no enabled lifecycle rule", address))
+			}
+		}
+	}
+
+	sort.Strings(violations)
+	return violations
+}
+
+// hasEnabledLifecycleRule reports whether a planned aws_s3_bucket_lifecycle_configuration has a rule with status Enabled
+func hasEnabledLifecycleRule(after map[string]interface{}) bool {
+	rules, _ := after["rule"].([]interface{})
+	for _, r := range rules {
+		if rule, ok := r.(map[string]interface{}); ok && rule["status"] == "Enabled" {
+			return true
+		}
+	}
+	return false
+}
+
+// exceedsInstanceSize reports whether an instance class or node type such as db.r6g.4xlarge is a larger size than max.
+// An empty max allows any size. Sizes that can't be ranked, such as an unknown suffix, count as exceeding the limit so
+// the policy fails closed.
+func exceedsInstanceSize(class, max string) bool {
+	if max == "" {
+		return false
+	}
+
+	maxUnits, ok := instanceSizeUnits(max)
+	if !ok {
+		return true
+	}
+	units, ok := instanceSizeUnits(class)
+	return !ok || units > maxUnits
+}
+
+// instanceSizeUnits returns the relative size of an instance class or node type, taken from its last component: large
+// is 1, xlarge 2, and Nxlarge 2N, which tracks the vCPU and memory within a family
+func instanceSizeUnits(class string) (float64, bool) {
+	parts := strings.Split(class, ".")
+	size := parts[len(parts)-1]
+
+	switch size {
+	case "nano":
+		return 0.125, true
+	case "micro":
+		return 0.25, true
+	case "small":
+		return 0.5, true
+	case "medium":
+		return 0.75, true
+	case "large":
+		return 1, true
+	case "xlarge":
+		return 2, true
+	case "metal":
+		return 1 << 10, true
+	}
+
+	multiplier, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge"))
+	if !strings.HasSuffix(size, "xlarge") || err != nil || multiplier <= 0 {
+		return 0, false
+	}
+	return float64(2 * multiplier), true
+}
@@ -0,0 +1,388 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// RunIDTagKey is the tag applied to every resource created by a test run
+	RunIDTagKey = "TerratestRunID"
+
+	// runIDEnvVar is read by the Terragrunt root.hcl to add the run ID to the provider's default_tags
+	runIDEnvVar = "TERRATEST_RUN_ID"
+
+	// runIDTofuVar is the variable every tofu example uses to add the run ID to the provider's default_tags
+	runIDTofuVar = "TF_VAR_terratest_run_id"
+)
+
+var (
+	runID     string
+	runIDOnce sync.Once
+)
+
+// RunID returns the ID shared by every test in this process. It is generated on first use, and exported to the
+// environment so tofu and terragrunt tag everything they create with it.
+func RunID() string {
+	runIDOnce.Do(func() {
+		runID = fmt.Sprintf("run-%s-%s", time.Now().UTC().Format("20060102150405"), strings.ToLower(random.UniqueId()))
+		os.Setenv(runIDEnvVar, runID)
+		os.Setenv(runIDTofuVar, runID)
+	})
+
+	return runID
+}
+
+// RunIDTags returns the tags identifying resources created by this test run
+func RunIDTags() map[string]string {
+	return map[string]string{RunIDTagKey: RunID()}
+}
+
+// RunWithSweep runs the tests in a package and then deletes any resources tagged with this run's ID that the tests
+// failed to destroy. Call it from TestMain: os.Exit(helpers.RunWithSweep(m, "us-east-1")).
+func RunWithSweep(m *testing.M, region string) int {
+	id := RunID()
+	code := m.Run()
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		fmt.Printf("Skipping sweep for %s: failed to create AWS session: %v\n", id, err)
+		return code
+	}
+
+	remaining, err := SweepByRunIDE(sess, id, func(format string, args ...interface{}) {
+		fmt.Printf(format+"\n", args...)
+	})
+	if err != nil {
+		fmt.Printf("Sweep for %s failed: %v\n", id, err)
+	}
+	for _, resourceARN := range remaining {
+		fmt.Printf("WARNING: could not sweep %s\n", resourceARN)
+	}
+
+	return code
+}
+
+// SweepByRunID deletes every resource tagged with the given run ID and fails if any could not be deleted
+func SweepByRunID(t *testing.T, sess *session.Session, runID string) {
+	t.Helper()
+
+	remaining, err := SweepByRunIDE(sess, runID, t.Logf)
+	require.NoError(t, err, "Failed to sweep resources for run %s", runID)
+	require.Empty(t, remaining, "Resources tagged %s=%s could not be deleted", RunIDTagKey, runID)
+}
+
+// SweepByRunIDE deletes every resource tagged with the given run ID and returns the ARNs it could not delete.
+// Resources often depend on each other (e.g. a security group on the database using it), so deletion is retried over
+// several passes until nothing more can be removed.
+func SweepByRunIDE(sess *session.Session, runID string, logf func(format string, args ...interface{})) ([]string, error) {
+	pending, err := findResourcesByTag(sess, RunIDTagKey, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		logf("No leftover resources tagged %s=%s", RunIDTagKey, runID)
+		return nil, nil
+	}
+
+	logf("Sweeping %d leftover resources tagged %s=%s", len(pending), RunIDTagKey, runID)
+
+	maxPasses := 10
+	for pass := 1; pass <= maxPasses && len(pending) > 0; pass++ {
+		var failed []string
+
+		for _, resourceARN := range pending {
+			if err := deleteResourceByARN(sess, resourceARN); err != nil {
+				logf("Pass %d: failed to delete %s: %v", pass, resourceARN, err)
+				failed = append(failed, resourceARN)
+				continue
+			}
+			logf("Deleted %s", resourceARN)
+		}
+
+		if len(failed) == len(pending) && pass > 1 {
+			// No progress; wait longer for in-flight deletions (RDS, ElastiCache, load balancers) to release dependencies
+			time.Sleep(60 * time.Second)
+		} else if len(failed) > 0 {
+			time.Sleep(15 * time.Second)
+		}
+
+		pending = failed
+	}
+
+	return pending, nil
+}
+
+// findResourcesByTag returns the ARNs of all resources in the session's region carrying the tag
+func findResourcesByTag(sess *session.Session, key, value string) ([]string, error) {
+	taggingClient := resourcegroupstaggingapi.New(sess)
+
+	var arns []string
+	err := taggingClient.GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{
+				Key:    aws.String(key),
+				Values: []*string{aws.String(value)},
+			},
+		},
+	}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, mapping := range page.ResourceTagMappingList {
+			arns = append(arns, aws.StringValue(mapping.ResourceARN))
+		}
+		return true
+	})
+
+	return arns, err
+}
+
+// deleteResourceByARN deletes a single resource using the API of the service that owns it
+func deleteResourceByARN(sess *session.Session, resourceARN string) error {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return err
+	}
+
+	// Resources are either "type/id" or "type:id" depending on the service
+	resourceType, resourceID := parsed.Resource, ""
+	if i := strings.IndexAny(parsed.Resource, "/:"); i >= 0 {
+		resourceType, resourceID = parsed.Resource[:i], parsed.Resource[i+1:]
+	}
+
+	switch parsed.Service {
+	case "ec2":
+		client := ec2.New(sess)
+		switch resourceType {
+		case "security-group":
+			_, err = client.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(resourceID)})
+			return err
+		case "vpc-endpoint":
+			_, err = client.DeleteVpcEndpoints(&ec2.DeleteVpcEndpointsInput{VpcEndpointIds: []*string{aws.String(resourceID)}})
+			return err
+		case "subnet":
+			_, err = client.DeleteSubnet(&ec2.DeleteSubnetInput{SubnetId: aws.String(resourceID)})
+			return err
+		case "route-table":
+			// Subnet associations have to go first; the main route table is deleted along with its VPC
+			tables, err := client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{RouteTableIds: []*string{aws.String(resourceID)}})
+			if err != nil {
+				return err
+			}
+			for _, table := range tables.RouteTables {
+				for _, association := range table.Associations {
+					if aws.BoolValue(association.Main) {
+						return nil
+					}
+					if _, err := client.DisassociateRouteTable(&ec2.DisassociateRouteTableInput{AssociationId: association.RouteTableAssociationId}); err != nil {
+						return err
+					}
+				}
+			}
+			_, err = client.DeleteRouteTable(&ec2.DeleteRouteTableInput{RouteTableId: aws.String(resourceID)})
+			return err
+		case "vpc":
+			_, err = client.DeleteVpc(&ec2.DeleteVpcInput{VpcId: aws.String(resourceID)})
+			return err
+		}
+
+	case "rds":
+		client := rds.New(sess)
+		switch resourceType {
+		case "db":
+			_, err = client.DeleteDBInstance(&rds.DeleteDBInstanceInput{
+				DBInstanceIdentifier:   aws.String(resourceID),
+				SkipFinalSnapshot:      aws.Bool(true),
+				DeleteAutomatedBackups: aws.Bool(true),
+			})
+			if err != nil && strings.Contains(err.Error(), "already being deleted") {
+				return nil
+			}
+			return err
+		case "subgrp":
+			_, err = client.DeleteDBSubnetGroup(&rds.DeleteDBSubnetGroupInput{DBSubnetGroupName: aws.String(resourceID)})
+			return err
+		case "pg":
+			_, err = client.DeleteDBParameterGroup(&rds.DeleteDBParameterGroupInput{DBParameterGroupName: aws.String(resourceID)})
+			return err
+		case "snapshot":
+			_, err = client.DeleteDBSnapshot(&rds.DeleteDBSnapshotInput{DBSnapshotIdentifier: aws.String(resourceID)})
+			return err
+		}
+
+	case "elasticache":
+		client := elasticache.New(sess)
+		switch resourceType {
+		case "replicationgroup":
+			_, err = client.DeleteReplicationGroup(&elasticache.DeleteReplicationGroupInput{ReplicationGroupId: aws.String(resourceID)})
+			if err != nil && strings.Contains(err.Error(), "not in available state") {
+				return nil
+			}
+			return err
+		case "cluster":
+			// Member clusters are deleted along with their replication group
+			return nil
+		case "subnetgroup":
+			_, err = client.DeleteCacheSubnetGroup(&elasticache.DeleteCacheSubnetGroupInput{CacheSubnetGroupName: aws.String(resourceID)})
+			return err
+		case "parametergroup":
+			_, err = client.DeleteCacheParameterGroup(&elasticache.DeleteCacheParameterGroupInput{CacheParameterGroupName: aws.String(resourceID)})
+			return err
+		}
+
+	case "ecs":
+		client := ecs.New(sess)
+		switch resourceType {
+		case "service":
+			// service/<cluster>/<service>
+			parts := strings.SplitN(resourceID, "/", 2)
+			if len(parts) != 2 {
+				break
+			}
+			_, err = client.DeleteService(&ecs.DeleteServiceInput{
+				Cluster: aws.String(parts[0]),
+				Service: aws.String(parts[1]),
+				Force:   aws.Bool(true),
+			})
+			return err
+		case "cluster":
+			_, err = client.DeleteCluster(&ecs.DeleteClusterInput{Cluster: aws.String(resourceID)})
+			return err
+		case "task-definition":
+			_, err = client.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{TaskDefinition: aws.String(resourceID)})
+			return err
+		}
+
+	case "elasticloadbalancing":
+		client := elbv2.New(sess)
+		switch resourceType {
+		case "loadbalancer":
+			_, err = client.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(resourceARN)})
+			return err
+		case "targetgroup":
+			_, err = client.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(resourceARN)})
+			return err
+		}
+
+	case "logs":
+		if resourceType == "log-group" {
+			_, err = cloudwatchlogs.New(sess).DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{
+				LogGroupName: aws.String(strings.TrimSuffix(resourceID, ":*")),
+			})
+			return err
+		}
+
+	case "cloudwatch":
+		if resourceType == "alarm" {
+			_, err = cloudwatch.New(sess).DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: []*string{aws.String(resourceID)}})
+			return err
+		}
+
+	case "s3":
+		return deleteS3Bucket(sess, parsed.Resource)
+
+	case "sqs":
+		client := sqs.New(sess)
+		queueURL, err := client.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(parsed.Resource)})
+		if err != nil {
+			return err
+		}
+		_, err = client.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: queueURL.QueueUrl})
+		return err
+
+	case "sns":
+		_, err = sns.New(sess).DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(resourceARN)})
+		return err
+
+	case "ecr":
+		if resourceType == "repository" {
+			_, err = ecr.New(sess).DeleteRepository(&ecr.DeleteRepositoryInput{
+				RepositoryName: aws.String(resourceID),
+				Force:          aws.Bool(true),
+			})
+			return err
+		}
+
+	case "lambda":
+		if resourceType == "function" {
+			_, err = lambda.New(sess).DeleteFunction(&lambda.DeleteFunctionInput{FunctionName: aws.String(resourceID)})
+			return err
+		}
+
+	case "secretsmanager":
+		if resourceType == "secret" {
+			_, err = secretsmanager.New(sess).DeleteSecret(&secretsmanager.DeleteSecretInput{
+				SecretId:                   aws.String(resourceARN),
+				ForceDeleteWithoutRecovery: aws.Bool(true),
+			})
+			return err
+		}
+
+	case "servicediscovery":
+		if resourceType == "namespace" {
+			_, err = servicediscovery.New(sess).DeleteNamespace(&servicediscovery.DeleteNamespaceInput{Id: aws.String(resourceID)})
+			return err
+		}
+	}
+
+	return fmt.Errorf("don't know how to delete %s resources of type %q", parsed.Service, resourceType)
+}
+
+// deleteS3Bucket empties a bucket, including all object versions, and then deletes it
+func deleteS3Bucket(sess *session.Session, bucket string) error {
+	client := s3.New(sess)
+
+	var deleteErr error
+	err := client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: aws.String(bucket)},
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			var objects []*s3.ObjectIdentifier
+			for _, version := range page.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+			}
+			for _, marker := range page.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+			}
+			if len(objects) == 0 {
+				return true
+			}
+
+			_, deleteErr = client.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			return deleteErr == nil
+		})
+	if err != nil {
+		return err
+	}
+	if deleteErr != nil {
+		return deleteErr
+	}
+
+	_, err = client.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/stretchr/testify/require"
+)
+
+// GetTaggedResources returns the tags of every resource in the session's region that carries the tag, keyed by ARN.
+// The tagging API is eventually consistent, so resources created moments ago may not be listed yet.
+func GetTaggedResources(t *testing.T, sess *session.Session, key, value string) map[string]map[string]string {
+	t.Helper()
+
+	resources := map[string]map[string]string{}
+	err := resourcegroupstaggingapi.New(sess).GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{
+				Key:    aws.String(key),
+				Values: []*string{aws.String(value)},
+			},
+		},
+	}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, mapping := range page.ResourceTagMappingList {
+			tags := make(map[string]string, len(mapping.Tags))
+			for _, tag := range mapping.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			resources[aws.StringValue(mapping.ResourceARN)] = tags
+		}
+		return true
+	})
+	require.NoError(t, err, "Failed to list resources tagged %s=%s", key, value)
+
+	return resources
+}
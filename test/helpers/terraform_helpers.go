@@ -1,8 +1,13 @@
 package helpers
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
@@ -82,6 +87,127 @@ func GetOutputMap(t *testing.T, opts *terraform.Options, outputs []string) map[s
 	return result
 }
 
+// OutputDiff describes an output whose value differs between two captures. Before or After is empty when the output
+// was added or removed.
+type OutputDiff struct {
+	Name   string
+	Before string
+	After  string
+}
+
+// CaptureOutputs retrieves all outputs as strings so they can be compared across applies with DiffOutputs
+func CaptureOutputs(t *testing.T, opts *terraform.Options) map[string]string {
+	t.Helper()
+
+	all := terraform.OutputAll(t, opts)
+
+	result := make(map[string]string, len(all))
+	for name, value := range all {
+		result[name] = fmt.Sprint(value)
+	}
+
+	return result
+}
+
+// DiffOutputs returns the outputs that changed between two captures, sorted by name
+func DiffOutputs(before, after map[string]string) []OutputDiff {
+	var diffs []OutputDiff
+
+	for name, beforeValue := range before {
+		if afterValue, ok := after[name]; !ok || afterValue != beforeValue {
+			diffs = append(diffs, OutputDiff{Name: name, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	for name, afterValue := range after {
+		if _, ok := before[name]; !ok {
+			diffs = append(diffs, OutputDiff{Name: name, After: afterValue})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+
+	return diffs
+}
+
+// AssertOutputsUnchanged fails if any of the given outputs appear in diffs. Use it to check that values clients depend
+// on, such as endpoints, survive a change. Identifiers derived from a resource's name stay the same even when the
+// resource is replaced, so use AssertNoResourcesReplaced to check a change is applied in place.
+func AssertOutputsUnchanged(t *testing.T, diffs []OutputDiff, outputs ...string) {
+	t.Helper()
+
+	for _, diff := range diffs {
+		for _, output := range outputs {
+			require.NotEqual(t, output, diff.Name, "Output '%s' changed from %q to %q", diff.Name, diff.Before, diff.After)
+		}
+	}
+
+	t.Logf("✅ Outputs unchanged: %v", outputs)
+}
+
+// OutputContract classifies every output of a module by whether downstream consumers, such as Terragrunt units reading
+// it through a dependency block, may see it change when an innocuous input like a tag changes
+type OutputContract struct {
+	// Stable outputs, such as identifiers, endpoints, and ARNs, must keep their value
+	Stable []string
+	// Volatile outputs are expected to change, so consumers must tolerate it
+	Volatile []string
+}
+
+// AssertOutputContract fails if an output in contract.Stable changed between two captures, or if the module has an
+// output the contract doesn't classify, so new outputs must be added to the contract before anyone depends on them
+func AssertOutputContract(t *testing.T, before, after map[string]string, contract OutputContract) {
+	t.Helper()
+
+	classified := map[string]bool{}
+	for _, output := range append(append([]string{}, contract.Stable...), contract.Volatile...) {
+		classified[output] = true
+	}
+	for _, outputs := range []map[string]string{before, after} {
+		for name := range outputs {
+			require.True(t, classified[name], "Output '%s' is not in the stability contract; add it to Stable or Volatile", name)
+		}
+	}
+
+	diffs := DiffOutputs(before, after)
+	AssertOutputsUnchanged(t, diffs, contract.Stable...)
+
+	for _, diff := range diffs {
+		t.Logf("Volatile output '%s' changed from %q to %q", diff.Name, diff.Before, diff.After)
+	}
+}
+
+// AssertNoResourcesReplaced fails if the plan deletes or replaces any resource whose address starts with
+// addressPrefix, e.g. "module.redis.aws_elasticache_replication_group."
+func AssertNoResourcesReplaced(t *testing.T, plan *terraform.PlanStruct, addressPrefix string) {
+	t.Helper()
+
+	matched := 0
+	for address, change := range plan.ResourceChangesMap {
+		if !strings.HasPrefix(address, addressPrefix) {
+			continue
+		}
+		matched++
+		require.False(t, change.Change.Actions.Delete() || change.Change.Actions.Replace(),
+			"Plan would destroy %s (actions: %v)", address, change.Change.Actions)
+	}
+
+	require.NotZero(t, matched, "Plan has no resources matching %s", addressPrefix)
+	t.Logf("✅ Plan changes %d resource(s) matching %s in place", matched, addressPrefix)
+}
+
+// AssertResourceReplaced fails unless the plan replaces the resource at address, e.g. to pin down which inputs force a
+// stateful resource to be recreated
+func AssertResourceReplaced(t *testing.T, plan *terraform.PlanStruct, address string) {
+	t.Helper()
+
+	change, ok := plan.ResourceChangesMap[address]
+	require.True(t, ok, "Plan has no changes for %s", address)
+	require.True(t, change.Change.Actions.Replace(), "Plan should replace %s (actions: %v)", address, change.Change.Actions)
+
+	t.Logf("✅ Plan replaces %s", address)
+}
+
 // DeployAndTest deploys infrastructure and runs test functions
 func DeployAndTest(t *testing.T, opts *terraform.Options, tests map[string]func(*testing.T)) {
 	t.Helper()
@@ -98,6 +224,169 @@ func DeployAndTest(t *testing.T, opts *terraform.Options, tests map[string]func(
 	}
 }
 
+// MeasureInfraProvisionTime runs init and apply and returns how long the apply took
+func MeasureInfraProvisionTime(t *testing.T, opts *terraform.Options) time.Duration {
+	t.Helper()
+
+	startTime := time.Now()
+	terraform.InitAndApply(t, opts)
+	duration := time.Since(startTime)
+
+	t.Logf("⏱️  Infrastructure provisioned in %s", duration)
+	return duration
+}
+
+// ImportResource imports an existing resource into the given Terraform resource address
+func ImportResource(t *testing.T, opts *terraform.Options, address, resourceID string) string {
+	t.Helper()
+
+	output, err := ImportResourceE(t, opts, address, resourceID)
+	require.NoError(t, err, "Failed to import %s into %s", resourceID, address)
+	t.Logf("✅ Imported %s into %s", resourceID, address)
+
+	return output
+}
+
+// ImportResourceE imports an existing resource into the given Terraform resource address. Vars are formatted here
+// instead of via FormatArgs because import requires all flags to come before the positional arguments.
+func ImportResourceE(t *testing.T, opts *terraform.Options, address, resourceID string) (string, error) {
+	t.Helper()
+
+	args := []string{"import", "-input=false"}
+	args = append(args, terraform.FormatTerraformVarsAsArgs(opts.Vars)...)
+	args = append(args, address, resourceID)
+
+	return terraform.RunTerraformCommandE(t, opts, args...)
+}
+
+// TofuDiagnostic is an error or warning reported by tofu validate -json
+type TofuDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Range    *struct {
+		Filename string `json:"filename"`
+		Start    struct {
+			Line int `json:"line"`
+		} `json:"start"`
+	} `json:"range"`
+}
+
+// String formats the diagnostic with its location, when it has one
+func (d TofuDiagnostic) String() string {
+	if d.Range == nil {
+		return fmt.Sprintf("%s: %s: %s", d.Severity, d.Summary, d.Detail)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s: %s", d.Range.Filename, d.Range.Start.Line, d.Severity, d.Summary, d.Detail)
+}
+
+// ValidateDiagnostics runs validate on an initialized configuration and returns its diagnostics. Validate checks
+// arguments against the provider schemas without calling AWS, so it reports deprecations without credentials.
+func ValidateDiagnostics(t *testing.T, opts *terraform.Options) []TofuDiagnostic {
+	t.Helper()
+
+	// Validate exits non-zero when there are errors, but still prints them as JSON
+	output, _ := terraform.RunTerraformCommandAndGetStdoutE(t, opts, "validate", "-json")
+
+	var result struct {
+		Valid       bool             `json:"valid"`
+		Diagnostics []TofuDiagnostic `json:"diagnostics"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &result), "validate -json output is not valid JSON: %s", output)
+
+	return result.Diagnostics
+}
+
+// DeprecationWarnings returns the diagnostics that report a deprecated argument, attribute, or resource, such as the
+// provider's "Argument is deprecated" warnings
+func DeprecationWarnings(diagnostics []TofuDiagnostic) []TofuDiagnostic {
+	var deprecations []TofuDiagnostic
+	for _, diagnostic := range diagnostics {
+		if strings.Contains(strings.ToLower(diagnostic.Summary), "deprecated") {
+			deprecations = append(deprecations, diagnostic)
+		}
+	}
+	return deprecations
+}
+
+// PullState returns the raw state of the working directory. Unlike show -json, it holds every attribute exactly as
+// persisted, sensitive ones included, so it's what someone with read access to the backend would see.
+func PullState(t *testing.T, opts *terraform.Options) map[string]interface{} {
+	t.Helper()
+
+	output, err := terraform.RunTerraformCommandAndGetStdoutE(t, opts, "state", "pull")
+	require.NoError(t, err, "Failed to pull state")
+
+	var state map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &state), "State is not valid JSON")
+
+	return state
+}
+
+// FindStateStrings returns the path of every string in the state that contains value, as dot-separated keys and
+// indexes, e.g. resources.3.instances.0.attributes.password
+func FindStateStrings(state interface{}, value string) []string {
+	var paths []string
+
+	var walk func(node interface{}, path string)
+	walk = func(node interface{}, path string) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			for key, child := range node {
+				walk(child, joinStatePath(path, key))
+			}
+		case []interface{}:
+			for i, child := range node {
+				walk(child, joinStatePath(path, strconv.Itoa(i)))
+			}
+		case string:
+			if strings.Contains(node, value) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	walk(state, "")
+
+	sort.Strings(paths)
+	return paths
+}
+
+func joinStatePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// AssertStateExcludesSecret fails if secret appears anywhere in the state in plaintext, naming the attributes that
+// hold it
+func AssertStateExcludesSecret(t *testing.T, state map[string]interface{}, description, secret string) {
+	t.Helper()
+
+	require.NotEmpty(t, secret, "Refusing to search the state for an empty %s", description)
+
+	paths := FindStateStrings(state, secret)
+	require.Empty(t, paths, "The %s is stored in plaintext in the state at: %s", description, strings.Join(paths, ", "))
+
+	t.Logf("✅ The %s does not appear in the state", description)
+}
+
+// AssertNoDestructiveChanges fails if the plan deletes or replaces any resource
+func AssertNoDestructiveChanges(t *testing.T, plan *terraform.PlanStruct) {
+	t.Helper()
+
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil {
+			continue
+		}
+
+		require.False(t, change.Change.Actions.Delete() || change.Change.Actions.Replace(),
+			"Plan proposes a destructive change to %s: %v", address, change.Change.Actions)
+	}
+
+	t.Logf("✅ Plan contains no destructive changes (%d resource changes inspected)", len(plan.ResourceChangesMap))
+}
+
 // PrintTerraformOutputs prints all Terraform outputs for debugging
 func PrintTerraformOutputs(t *testing.T, opts *terraform.Options) {
 	t.Helper()
@@ -109,7 +398,8 @@ func PrintTerraformOutputs(t *testing.T, opts *terraform.Options) {
 	}
 }
 
-// GetModuleTestDefaults returns default configuration for module tests
+// GetModuleTestDefaults returns default configuration for module tests. Resources are tagged with the run ID so
+// SweepByRunID can clean up anything the test fails to destroy.
 func GetModuleTestDefaults(modulePath, uniqueID string) TerraformTestConfig {
 	return TerraformTestConfig{
 		TerraformDir:    modulePath,
@@ -119,11 +409,13 @@ func GetModuleTestDefaults(modulePath, uniqueID string) TerraformTestConfig {
 		},
 		EnvVars: map[string]string{
 			"AWS_DEFAULT_REGION": "us-east-1",
+			runIDTofuVar:         RunID(),
 		},
 	}
 }
 
-// GetUnitTestDefaults returns default configuration for unit (Terragrunt) tests
+// GetUnitTestDefaults returns default configuration for unit (Terragrunt) tests. Resources are tagged with the run ID
+// so SweepByRunID can clean up anything the test fails to destroy.
 func GetUnitTestDefaults(unitPath string) TerraformTestConfig {
 	return TerraformTestConfig{
 		TerraformDir:    unitPath,
@@ -132,6 +424,7 @@ func GetUnitTestDefaults(unitPath string) TerraformTestConfig {
 		EnvVars: map[string]string{
 			"AWS_DEFAULT_REGION": "us-east-1",
 			"AWS_PROFILE":        "lightwave-admin-new",
+			runIDEnvVar:          RunID(),
 		},
 	}
 }
@@ -0,0 +1,96 @@
+package modules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInsufficientAZs verifies that Multi-AZ data stores deployed into a single AZ fail at plan time with an
+// actionable error, instead of failing minutes into an apply
+func TestInsufficientAZs(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	singleAZSubnet := getDefaultSubnetInOneAZ(t, sess)
+
+	t.Run("PostgreSQLMultiAZ", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/postgresql",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":            helpers.UniqueResourceName("pg-az", helpers.RDSIdentifierNaming),
+				"master_username": "testadmin",
+				"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+				"multi_az":        true,
+				"subnet_ids":      []string{singleAZSubnet},
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		_, err := terraform.InitAndPlanE(t, terraformOptions)
+		require.Error(t, err, "Plan should fail when Multi-AZ RDS only has subnets in one AZ")
+		assert.Contains(t, err.Error(), "at least 2 availability zones")
+		assert.Contains(t, err.Error(), "Add a subnet in another AZ")
+		t.Log("✅ Multi-AZ RDS with a single AZ rejected at plan time")
+	})
+
+	t.Run("RedisMultiAZ", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":               helpers.UniqueResourceName("redis-az", helpers.ElastiCacheNaming),
+				"num_cache_nodes":    2,
+				"automatic_failover": true,
+				"multi_az":           true,
+				"subnet_ids":         []string{singleAZSubnet},
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		_, err := terraform.InitAndPlanE(t, terraformOptions)
+		require.Error(t, err, "Plan should fail when Multi-AZ ElastiCache only has subnets in one AZ")
+		assert.Contains(t, err.Error(), "at least 2 availability zones")
+		assert.Contains(t, err.Error(), "Add a subnet in another AZ")
+		t.Log("✅ Multi-AZ ElastiCache with a single AZ rejected at plan time")
+	})
+}
+
+// getDefaultSubnetInOneAZ returns the ID of a single default-VPC subnet, giving a subnet list that spans one AZ
+func getDefaultSubnetInOneAZ(t *testing.T, sess *session.Session) string {
+	ec2Client := ec2.New(sess)
+
+	result, err := ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("default-for-az"),
+				Values: []*string{aws.String("true")},
+			},
+		},
+	})
+	require.NoError(t, err, "Failed to describe default subnets")
+	require.NotEmpty(t, result.Subnets, "No default subnets found")
+
+	subnet := result.Subnets[0]
+	t.Logf("Using subnet %s in %s", aws.StringValue(subnet.SubnetId), aws.StringValue(subnet.AvailabilityZone))
+
+	return aws.StringValue(subnet.SubnetId)
+}
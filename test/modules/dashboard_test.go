@@ -0,0 +1,98 @@
+package modules_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+)
+
+// TestModuleDashboard deploys the ECS service and the data tier with create_dashboard set and verifies each module's
+// CloudWatch dashboard exists and graphs the resources that were actually deployed
+func TestModuleDashboard(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	t.Run("ECS", func(t *testing.T) {
+		t.Parallel()
+
+		name := fmt.Sprintf("ecs-dash-%s", strings.ToLower(random.UniqueId()))
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":             name,
+				"desired_count":    1,
+				"create_dashboard": true,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying ECS Fargate service with a dashboard...")
+		terraform.InitAndApply(t, terraformOptions)
+
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		dashboardName := terraform.Output(t, terraformOptions, "dashboard_name")
+
+		// CloudWatch identifies a target group by the part of its ARN after the account ID, e.g. targetgroup/name/id
+		tgArn := terraform.Output(t, terraformOptions, "target_group_arn")
+		tgArnSuffix := tgArn[strings.Index(tgArn, "targetgroup/"):]
+
+		body := helpers.AssertDashboardExists(t, sess, dashboardName)
+		helpers.AssertDashboardReferences(t, dashboardName, body,
+			terraform.Output(t, terraformOptions, "ecs_cluster_name"),
+			terraform.Output(t, terraformOptions, "ecs_service_name"),
+			tgArnSuffix,
+		)
+	})
+
+	t.Run("DataTier", func(t *testing.T) {
+		t.Parallel()
+
+		name := fmt.Sprintf("data-dash-%s", strings.ToLower(random.UniqueId()))
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/data-tier",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":              name,
+				"master_username":   "testadmin",
+				"master_password":   fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+				"create_dashboards": true,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying PostgreSQL and Redis with dashboards... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+		t.Run("PostgreSQL", func(t *testing.T) {
+			dashboardName := terraform.Output(t, terraformOptions, "db_dashboard_name")
+			body := helpers.AssertDashboardExists(t, sess, dashboardName)
+			helpers.AssertDashboardReferences(t, dashboardName, body,
+				helpers.DBInstanceIdentifierFromARN(terraform.Output(t, terraformOptions, "db_arn")))
+		})
+
+		t.Run("Redis", func(t *testing.T) {
+			dashboardName := terraform.Output(t, terraformOptions, "redis_dashboard_name")
+			body := helpers.AssertDashboardExists(t, sess, dashboardName)
+			helpers.AssertDashboardReferences(t, dashboardName, body,
+				terraform.OutputList(t, terraformOptions, "redis_member_clusters")...)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package modules_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDatastoreNetworkIsolation deploys PostgreSQL and Redis behind an app security group and audits the data tier's
+// network segmentation as a whole, both from the security group rules and by connecting from inside the VPC
+func TestDatastoreNetworkIsolation(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("data-tier-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/data-tier",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":            name,
+			"master_username": "testadmin",
+			"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying PostgreSQL and Redis behind an app security group... (this may take 10-15 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	appSgID := terraform.Output(t, terraformOptions, "app_security_group_id")
+	rdsSgID := terraform.Output(t, terraformOptions, "db_security_group_id")
+	redisSgID := terraform.Output(t, terraformOptions, "redis_security_group_id")
+
+	rdsPort, err := strconv.ParseInt(terraform.Output(t, terraformOptions, "db_port"), 10, 64)
+	require.NoError(t, err, "db_port should be a number")
+	redisPort, err := strconv.ParseInt(terraform.Output(t, terraformOptions, "redis_port"), 10, 64)
+	require.NoError(t, err, "redis_port should be a number")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("SecurityGroupRules", func(t *testing.T) {
+		helpers.AssertDatastoreSGsIsolated(t, sess, rdsSgID, redisSgID, appSgID, rdsPort, redisPort)
+	})
+
+	// The rules can look right and still not behave as intended, so try real connections from inside the VPC
+	t.Run("Reachability", func(t *testing.T) {
+		subnetIDs := terraform.OutputList(t, terraformOptions, "subnet_ids")
+		dbAddress := terraform.Output(t, terraformOptions, "db_address")
+		redisAddress := terraform.Output(t, terraformOptions, "redis_address")
+
+		appProbe := helpers.DeployConnectivityProbeLambda(t, sess, helpers.ProbeVPCConfig{
+			Name:             fmt.Sprintf("%s-app", name),
+			SubnetIDs:        subnetIDs,
+			SecurityGroupIDs: []string{appSgID},
+		})
+		defer appProbe.Destroy(t)
+
+		outsiderProbe := helpers.DeployConnectivityProbeLambda(t, sess, helpers.ProbeVPCConfig{
+			Name:             fmt.Sprintf("%s-outsider", name),
+			SubnetIDs:        subnetIDs,
+			SecurityGroupIDs: []string{terraform.Output(t, terraformOptions, "outsider_security_group_id")},
+		})
+		defer outsiderProbe.Destroy(t)
+
+		assert.True(t, appProbe.CanConnect(t, dbAddress, int(rdsPort)), "The app should reach PostgreSQL")
+		assert.True(t, appProbe.CanConnect(t, redisAddress, int(redisPort)), "The app should reach Redis")
+		assert.False(t, outsiderProbe.CanConnect(t, dbAddress, int(rdsPort)), "Other security groups should not reach PostgreSQL")
+		assert.False(t, outsiderProbe.CanConnect(t, redisAddress, int(redisPort)), "Other security groups should not reach Redis")
+	})
+}
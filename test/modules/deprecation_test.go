@@ -0,0 +1,103 @@
+package modules_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deprecatedAWSArguments lists arguments and nested blocks the AWS provider (~> 5.0) has deprecated, with their
+// replacement. Most are inline settings that moved to their own resources and are due to be removed.
+var deprecatedAWSArguments = map[string]map[string]string{
+	"aws_db_instance": {
+		"name": "db_name",
+	},
+	"aws_elasticache_replication_group": {
+		"replication_group_description": "description",
+		"number_cache_clusters":         "num_cache_clusters",
+		"cluster_mode":                  "num_node_groups and replicas_per_node_group",
+		"availability_zones":            "preferred_cache_cluster_azs",
+	},
+	"aws_s3_bucket": {
+		"acl":                                  "aws_s3_bucket_acl",
+		"versioning":                           "aws_s3_bucket_versioning",
+		"website":                              "aws_s3_bucket_website_configuration",
+		"cors_rule":                            "aws_s3_bucket_cors_configuration",
+		"lifecycle_rule":                       "aws_s3_bucket_lifecycle_configuration",
+		"logging":                              "aws_s3_bucket_logging",
+		"server_side_encryption_configuration": "aws_s3_bucket_server_side_encryption_configuration",
+		"policy":                               "aws_s3_bucket_policy",
+		"acceleration_status":                  "aws_s3_bucket_accelerate_configuration",
+		"replication_configuration":            "aws_s3_bucket_replication_configuration",
+		"object_lock_configuration":            "aws_s3_bucket_object_lock_configuration",
+		"request_payer":                        "aws_s3_bucket_request_payment_configuration",
+	},
+	"aws_eip": {
+		"vpc": "domain = \"vpc\"",
+	},
+	"aws_iam_role": {
+		"managed_policy_arns": "aws_iam_role_policy_attachment",
+		"inline_policy":       "aws_iam_role_policy",
+	},
+	"aws_ecs_cluster": {
+		"capacity_providers":                 "aws_ecs_cluster_capacity_providers",
+		"default_capacity_provider_strategy": "aws_ecs_cluster_capacity_providers",
+	},
+}
+
+// TestNoDeprecatedResourceAttributes fails if any module sets an argument the AWS provider has deprecated, so the
+// catalog is fixed before the provider removes it rather than when an upgrade breaks every caller. Known deprecations
+// are checked statically; each example is also validated against the provider schemas, which reports every deprecation
+// the provider declares, including ones not in the list.
+func TestNoDeprecatedResourceAttributes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("KnownDeprecatedArguments", func(t *testing.T) {
+		moduleDirs, err := filepath.Glob("../../modules/*")
+		require.NoError(t, err)
+		require.NotEmpty(t, moduleDirs, "No modules found")
+
+		for _, moduleDir := range moduleDirs {
+			if info, err := os.Stat(moduleDir); err != nil || !info.IsDir() {
+				continue
+			}
+
+			found := helpers.FindDeprecatedArguments(t, moduleDir, deprecatedAWSArguments)
+			assert.Empty(t, found, "%s uses deprecated arguments:\n%s", moduleDir, strings.Join(found, "\n"))
+		}
+		t.Log("✅ No module sets a known deprecated argument")
+	})
+
+	t.Run("ProviderDeprecations", func(t *testing.T) {
+		examples, err := filepath.Glob("../../examples/tofu/*/main.tf")
+		require.NoError(t, err)
+		require.NotEmpty(t, examples, "No examples found")
+
+		for _, example := range examples {
+			exampleDir := filepath.Dir(example)
+
+			t.Run(filepath.Base(exampleDir), func(t *testing.T) {
+				t.Parallel()
+
+				opts := &terraform.Options{
+					TerraformDir:    exampleDir,
+					TerraformBinary: "tofu",
+				}
+				terraform.Init(t, opts)
+
+				var deprecations []string
+				for _, diagnostic := range helpers.DeprecationWarnings(helpers.ValidateDiagnostics(t, opts)) {
+					deprecations = append(deprecations, diagnostic.String())
+				}
+				assert.Empty(t, deprecations, "%s relies on deprecated provider features:\n%s", exampleDir, strings.Join(deprecations, "\n"))
+				t.Logf("✅ %s has no deprecation warnings", exampleDir)
+			})
+		}
+	})
+}
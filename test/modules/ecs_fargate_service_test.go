@@ -1,17 +1,28 @@
 package modules_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,6 +50,13 @@ func TestECSFargateServiceModule(t *testing.T) {
 	// Cleanup resources after test
 	defer terraform.Destroy(t, terraformOptions)
 
+	// Delete the service first and wait for its task ENIs, so a teardown race shows up as a clear failure rather than a
+	// DependencyViolation when the full destroy deletes the security group
+	defer destroyECSServiceAndAssertENIsReleased(t, terraformOptions, awsRegion)
+
+	// Keep the deploy output and plan if anything below fails
+	defer helpers.CaptureLogsOnFailure(t, terraformOptions)()
+
 	// Deploy the ECS Fargate service
 	t.Log("Deploying ECS Fargate service...")
 	terraform.InitAndApply(t, terraformOptions)
@@ -93,6 +111,51 @@ func TestECSFargateServiceModuleMinimal(t *testing.T) {
 	// For full Plan testing, use the non-Minimal tests which deploy real infrastructure
 }
 
+// TestECSNoScaleDownDuringDeploy verifies running capacity never dips below the deployment floor during a rollout
+func TestECSNoScaleDownDuringDeploy(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-deploy-test-%s", uniqueID)
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                               name,
+			"desired_count":                      2,
+			"deployment_minimum_healthy_percent": 100,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 5*time.Minute)
+
+	// Roll out a new deployment of the same task definition
+	ecsClient := ecs.New(sess)
+	_, err := ecsClient.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:            aws.String(clusterName),
+		Service:            aws.String(serviceName),
+		ForceNewDeployment: aws.Bool(true),
+	})
+	require.NoError(t, err, "Failed to trigger a new deployment")
+	t.Log("Triggered new deployment, monitoring running count...")
+
+	helpers.AssertECSNoScaleDownDuringDeploy(t, sess, clusterName, serviceName, 10*time.Minute)
+}
+
 // testECSOutputs validates that all expected outputs are present
 func testECSOutputs(t *testing.T, opts *terraform.Options) {
 	// Verify URL output
@@ -119,6 +182,8 @@ func testECSOutputs(t *testing.T, opts *terraform.Options) {
 
 // testECSServiceHealth verifies the ECS service is running and healthy
 func testECSServiceHealth(t *testing.T, opts *terraform.Options, region, serviceName string) {
+	startedAt := time.Now()
+
 	// Create AWS session
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(region),
@@ -168,10 +233,38 @@ func testECSServiceHealth(t *testing.T, opts *terraform.Options, region, service
 		time.Sleep(retryInterval)
 	}
 
+	if !serviceStable {
+		dumpECSTaskLogs(t, sess, opts, serviceName, startedAt.Add(-ecsLogDumpWindow))
+	}
 	require.True(t, serviceStable, "ECS service did not stabilize within timeout")
 	t.Log("✅ ECS service is running and healthy")
 }
 
+const (
+	// ecsLogDumpLines is how many of the app container's most recent log lines are logged when a service won't stabilize
+	ecsLogDumpLines = 50
+
+	// ecsLogDumpWindow is how far before the health check started to look for logs, to cover tasks started by the apply
+	ecsLogDumpWindow = 15 * time.Minute
+)
+
+// dumpECSTaskLogs logs the app container's most recent log lines, so a task that keeps crashing shows why in the test
+// output
+func dumpECSTaskLogs(t *testing.T, sess *session.Session, opts *terraform.Options, containerName string, since time.Time) {
+	logGroup, err := terraform.OutputE(t, opts, "log_group_name")
+	if err != nil || logGroup == "" {
+		t.Logf("No app log group to dump: %v", err)
+		return
+	}
+
+	lines := helpers.GetECSTaskLogs(t, sess, logGroup, fmt.Sprintf("ecs/%s", containerName), since)
+	if len(lines) > ecsLogDumpLines {
+		lines = lines[len(lines)-ecsLogDumpLines:]
+	}
+
+	t.Logf("Last %d log line(s) from %s:\n%s", len(lines), logGroup, strings.Join(lines, "\n"))
+}
+
 // testECSLoadBalancer verifies the ALB is properly configured and healthy
 func testECSLoadBalancer(t *testing.T, opts *terraform.Options, region string) {
 	// Create AWS session
@@ -276,6 +369,28 @@ func testECSSecurityGroups(t *testing.T, opts *terraform.Options) {
 	t.Log("✅ Security group IDs are properly formatted")
 }
 
+// destroyECSServiceAndAssertENIsReleased destroys the ECS service alone, then checks no network interfaces are left in
+// its security group. Failures are reported but don't stop the caller's destroy.
+func destroyECSServiceAndAssertENIsReleased(t *testing.T, opts *terraform.Options, region string) {
+	sgID, err := terraform.OutputE(t, opts, "service_security_group_id")
+	if err != nil || sgID == "" {
+		t.Logf("No service security group in the state, so there are no ENIs to check: %v", err)
+		return
+	}
+
+	// The service is one of two resources depending on enable_autoscaling; targeting the missing one is a no-op
+	opts.Targets = []string{"module.ecs_service.aws_ecs_service.service", "module.ecs_service.aws_ecs_service.autoscaled"}
+	_, err = terraform.DestroyE(t, opts)
+	opts.Targets = nil
+	if err != nil {
+		t.Errorf("Failed to destroy the ECS service ahead of the rest of the stack: %v", err)
+		return
+	}
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: region})
+	helpers.AssertNoOrphanedENIs(t, sess, sgID)
+}
+
 // Helper function to find the cluster containing a service
 func findClusterForService(client *ecs.ECS, serviceName string) (string, error) {
 	// List all clusters
@@ -309,3 +424,1408 @@ func findClusterForService(client *ecs.ECS, serviceName string) (string, error)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[len(s)-len(substr):] == substr || s[:len(substr)] == substr))
 }
+
+// TestECSServiceConnect tests that a service registered with Service Connect is reachable from another service by its
+// DNS alias. The frontend proxies every request to http://backend:5000, so its ALB only returns the backend's response
+// if Service Connect routes the traffic.
+func TestECSServiceConnect(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-sc-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-service-connect",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name": name,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying backend and frontend services with Service Connect...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	backendCluster := terraform.Output(t, terraformOptions, "backend_cluster_name")
+	backendService := terraform.Output(t, terraformOptions, "backend_service_name")
+	frontendCluster := terraform.Output(t, terraformOptions, "frontend_cluster_name")
+	frontendService := terraform.Output(t, terraformOptions, "frontend_service_name")
+	namespaceARN := terraform.Output(t, terraformOptions, "namespace_arn")
+
+	t.Run("ServiceConnectConfiguration", func(t *testing.T) {
+		config := helpers.GetECSServiceConnectConfiguration(t, sess, backendCluster, backendService)
+
+		assert.True(t, aws.BoolValue(config.Enabled), "Service Connect should be enabled on the backend")
+		assert.Equal(t, namespaceARN, aws.StringValue(config.Namespace))
+		require.Len(t, config.Services, 1, "Backend should expose one Service Connect service")
+		require.NotEmpty(t, config.Services[0].ClientAliases, "Backend should have a client alias")
+		assert.Equal(t, "backend", aws.StringValue(config.Services[0].ClientAliases[0].DNSName))
+
+		clientConfig := helpers.GetECSServiceConnectConfiguration(t, sess, frontendCluster, frontendService)
+		assert.True(t, aws.BoolValue(clientConfig.Enabled), "Service Connect should be enabled on the frontend")
+		assert.Empty(t, clientConfig.Services, "Frontend should join the namespace as a client only")
+	})
+
+	t.Run("ServiceToServiceTraffic", func(t *testing.T) {
+		helpers.WaitForECSServiceStable(t, sess, backendCluster, backendService, 10*time.Minute)
+		helpers.WaitForECSServiceStable(t, sess, frontendCluster, frontendService, 10*time.Minute)
+
+		frontendURL := terraform.Output(t, terraformOptions, "frontend_url")
+		http_helper.HttpGetWithRetry(t, frontendURL, nil, 200, "Hello World!", 30, 10*time.Second)
+		t.Log("✅ Frontend reached the backend via its Service Connect alias")
+	})
+}
+
+// TestECSFirelens tests that enabling firelens_configuration adds a FluentBit log router sidecar, switches the app
+// container to the awsfirelens log driver, and that the app's logs reach the configured destination
+func TestECSFirelens(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-firelens-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":            name,
+			"desired_count":   1,
+			"enable_firelens": true,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with a FireLens log router...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	taskDefinitionARN := terraform.Output(t, terraformOptions, "task_definition_arn")
+
+	t.Run("TaskDefinition", func(t *testing.T) {
+		result, err := ecs.New(sess).DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinitionARN),
+		})
+		require.NoError(t, err, "Failed to describe task definition")
+
+		var logRouter, app *ecs.ContainerDefinition
+		for _, container := range result.TaskDefinition.ContainerDefinitions {
+			switch aws.StringValue(container.Name) {
+			case "log_router":
+				logRouter = container
+			case name:
+				app = container
+			}
+		}
+
+		require.NotNil(t, logRouter, "Task definition should contain a log_router container")
+		require.NotNil(t, logRouter.FirelensConfiguration, "log_router should have a firelensConfiguration")
+		assert.Equal(t, ecs.FirelensConfigurationTypeFluentbit, aws.StringValue(logRouter.FirelensConfiguration.Type))
+		assert.True(t, aws.BoolValue(logRouter.Essential), "log_router should be essential")
+		t.Logf("✅ Log router container present with %s firelensConfiguration", aws.StringValue(logRouter.FirelensConfiguration.Type))
+
+		require.NotNil(t, app, "Task definition should contain the app container")
+		require.NotNil(t, app.LogConfiguration, "App container should have a logConfiguration")
+		assert.Equal(t, ecs.LogDriverAwsfirelens, aws.StringValue(app.LogConfiguration.LogDriver))
+		assert.Equal(t, "cloudwatch_logs", aws.StringValue(app.LogConfiguration.Options["Name"]))
+		t.Log("✅ App container logs via the awsfirelens driver")
+	})
+
+	t.Run("LogsReachDestination", func(t *testing.T) {
+		clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+		serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+
+		// Every request is logged by the app, so generate some traffic and look for it at the destination
+		url := terraform.Output(t, terraformOptions, "url")
+		http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+
+		logGroupName := terraform.Output(t, terraformOptions, "firelens_log_group_name")
+		helpers.WaitForLogEvents(t, sess, logGroupName, "GET", 5*time.Minute)
+	})
+}
+
+// TestECSInitContainerOrdering tests that init_container_definition adds a non-essential container the app depends on
+// with condition SUCCESS, and that ECS only starts the app once the init container has exited cleanly
+func TestECSInitContainerOrdering(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-init-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                  name,
+			"desired_count":         1,
+			"enable_init_container": true,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with an init container...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	taskDefinitionARN := terraform.Output(t, terraformOptions, "task_definition_arn")
+
+	t.Run("TaskDefinition", func(t *testing.T) {
+		result, err := ecs.New(sess).DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinitionARN),
+		})
+		require.NoError(t, err, "Failed to describe task definition")
+
+		var initContainer, app *ecs.ContainerDefinition
+		for _, container := range result.TaskDefinition.ContainerDefinitions {
+			switch aws.StringValue(container.Name) {
+			case "init":
+				initContainer = container
+			case name:
+				app = container
+			}
+		}
+
+		require.NotNil(t, initContainer, "Task definition should contain the init container")
+		assert.False(t, aws.BoolValue(initContainer.Essential), "init container should not be essential")
+
+		require.NotNil(t, app, "Task definition should contain the app container")
+		require.Len(t, app.DependsOn, 1, "App container should depend on the init container")
+		assert.Equal(t, "init", aws.StringValue(app.DependsOn[0].ContainerName))
+		assert.Equal(t, ecs.ContainerConditionSuccess, aws.StringValue(app.DependsOn[0].Condition))
+		t.Log("✅ App container depends on the init container with condition SUCCESS")
+	})
+
+	t.Run("AppStartsAfterInit", func(t *testing.T) {
+		clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+		serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+
+		helpers.AssertContainerStartsAfter(t, sess, clusterName, serviceName, "init", name, 10*time.Minute)
+
+		url := terraform.Output(t, terraformOptions, "url")
+		http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+	})
+}
+
+// TestECSScalingCooldowns verifies the autoscaling cooldowns are applied and behave as configured: load drives the
+// service to scale out, and once the load stops, consecutive scale-ins are at least scale_in_cooldown apart, with no
+// scale-out in between. Only scale-ins are checked for spacing because target tracking may add more capacity during a
+// scale-out cooldown if the metric calls for it.
+func TestECSScalingCooldowns(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-scale-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	minCapacity := 1
+	maxCapacity := 3
+	scaleOutCooldown := int64(60)
+	scaleInCooldown := int64(300)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"desired_count":      minCapacity,
+			"enable_autoscaling": true,
+			"min_capacity":       minCapacity,
+			"max_capacity":       maxCapacity,
+			// A low target so a test runner's traffic is enough to scale out
+			"target_cpu_utilization": 20,
+			"scale_out_cooldown":     scaleOutCooldown,
+			"scale_in_cooldown":      scaleInCooldown,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with CPU autoscaling...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+	url := terraform.Output(t, terraformOptions, "url")
+
+	t.Run("PolicyCooldowns", func(t *testing.T) {
+		helpers.AssertScalingPolicyCooldowns(t, sess, clusterName, serviceName, scaleOutCooldown, scaleInCooldown)
+	})
+
+	helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+	http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+
+	t.Run("ScaleOutUnderLoad", func(t *testing.T) {
+		stopLoad := startHTTPLoad(url, 50)
+
+		// Target tracking needs three minutes of high CPU before it scales out
+		helpers.WaitForCondition(t, helpers.RetryConfig{
+			MaxRetries:    60,
+			RetryInterval: 15 * time.Second,
+			Description:   "scale-out",
+		}, func() bool {
+			return helpers.GetECSServiceDesiredCount(t, sess, clusterName, serviceName) >= maxCapacity
+		}, "Service %s did not scale out to %d tasks under load", serviceName, maxCapacity)
+
+		// Applying the module again must keep the count the scaler chose rather than reset it to desired_count
+		terraform.Apply(t, terraformOptions)
+		assert.GreaterOrEqual(t, helpers.GetECSServiceDesiredCount(t, sess, clusterName, serviceName), maxCapacity,
+			"Applying the module again reset the scaled-out service to desired_count = %d", minCapacity)
+		t.Log("✅ Applying the module again kept the scaled-out task count")
+
+		stopLoad()
+		t.Log("Load stopped")
+	})
+
+	t.Run("ScaleInRespectsCooldown", func(t *testing.T) {
+		// Target tracking waits fifteen minutes of low CPU before scaling in, then steps down one cooldown at a time
+		helpers.WaitForCondition(t, helpers.RetryConfig{
+			MaxRetries:    90,
+			RetryInterval: 30 * time.Second,
+			Description:   "scale-in",
+		}, func() bool {
+			return helpers.GetECSServiceDesiredCount(t, sess, clusterName, serviceName) <= minCapacity
+		}, "Service %s did not scale back in to %d task(s) once the load stopped", serviceName, minCapacity)
+
+		events := helpers.GetECSScalingEvents(t, sess, clusterName, serviceName)
+		var scaleIns []helpers.ECSScalingEvent
+		previous := minCapacity
+		for _, event := range events {
+			t.Logf("%s: desired count %d -> %d", event.Start.Format(time.RFC3339), previous, event.DesiredCount)
+			if event.DesiredCount < previous {
+				scaleIns = append(scaleIns, event)
+			} else {
+				require.Empty(t, scaleIns, "Service scaled out again after scaling in with no load, so it is flapping")
+			}
+			previous = event.DesiredCount
+		}
+		require.NotEmpty(t, scaleIns, "No scale-in activity was recorded")
+
+		for i := 1; i < len(scaleIns); i++ {
+			gap := scaleIns[i].Start.Sub(scaleIns[i-1].End)
+			assert.GreaterOrEqual(t, gap, time.Duration(scaleInCooldown)*time.Second,
+				"Scale-in at %s started %s after the previous one, inside the %ds cooldown", scaleIns[i].Start, gap, scaleInCooldown)
+		}
+		if len(scaleIns) == 1 {
+			t.Log("Service scaled in to the minimum in one step, so there was no second scale-in for the cooldown to delay")
+		}
+		t.Logf("✅ %d scale-in(s), each at least %ds apart", len(scaleIns), scaleInCooldown)
+	})
+}
+
+// TestECSFargateAutoscaling verifies enabling autoscaling registers the service with Application Auto Scaling using
+// the configured bounds and CPU target. It doesn't drive a scaling event; TestECSScalingCooldowns does.
+func TestECSFargateAutoscaling(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("ecs-asg-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+
+	minCapacity := 2
+	maxCapacity := 5
+	targetCPU := 60.0
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                   name,
+			"desired_count":          minCapacity,
+			"enable_autoscaling":     true,
+			"min_capacity":           minCapacity,
+			"max_capacity":           maxCapacity,
+			"target_cpu_utilization": targetCPU,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with CPU autoscaling...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	t.Run("ScalableTarget", func(t *testing.T) {
+		target := helpers.GetECSScalableTarget(t, sess, clusterName, serviceName)
+		assert.Equal(t, terraform.Output(t, terraformOptions, "scaling_target_resource_id"), aws.StringValue(target.ResourceId))
+		assert.Equal(t, int64(minCapacity), aws.Int64Value(target.MinCapacity), "min_capacity")
+		assert.Equal(t, int64(maxCapacity), aws.Int64Value(target.MaxCapacity), "max_capacity")
+		t.Logf("✅ %s registered to scale between %d and %d tasks", aws.StringValue(target.ResourceId), minCapacity, maxCapacity)
+	})
+
+	t.Run("TargetTrackingPolicy", func(t *testing.T) {
+		policyName := terraform.Output(t, terraformOptions, "autoscaling_policy_name")
+		result, err := applicationautoscaling.New(sess).DescribeScalingPolicies(&applicationautoscaling.DescribeScalingPoliciesInput{
+			ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+			PolicyNames:      []*string{aws.String(policyName)},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.ScalingPolicies, 1, "Scaling policy %s not found", policyName)
+
+		config := result.ScalingPolicies[0].TargetTrackingScalingPolicyConfiguration
+		require.NotNil(t, config, "%s should be a target tracking policy", policyName)
+		assert.Equal(t, targetCPU, aws.Float64Value(config.TargetValue))
+		assert.Equal(t, applicationautoscaling.MetricTypeEcsserviceAverageCpuutilization,
+			aws.StringValue(config.PredefinedMetricSpecification.PredefinedMetricType))
+	})
+
+	t.Run("DesiredCountWithinBounds", func(t *testing.T) {
+		desired := helpers.GetECSServiceDesiredCount(t, sess, clusterName, serviceName)
+		assert.GreaterOrEqual(t, desired, minCapacity)
+		assert.LessOrEqual(t, desired, maxCapacity)
+		t.Logf("✅ Desired count %d is within [%d, %d]", desired, minCapacity, maxCapacity)
+	})
+}
+
+// ecsScaleOutSLO is how long autoscaling may take from the CPU target first being breached to a new task serving
+// traffic. Target tracking alone waits for three one-minute datapoints above the target, so most of the budget goes to
+// the alarm.
+const ecsScaleOutSLO = 4 * time.Minute
+
+// TestECSScaleOutLatency applies a sudden load spike and measures how long autoscaling takes to put a new task behind
+// the load balancer, broken down into alarm, scaling activity, task start, and health check, so regressions in the
+// threshold or cooldown tuning show up as a slower stage rather than just a slower total
+func TestECSScaleOutLatency(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("ecs-spike-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	targetCPU := 20
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"desired_count":      1,
+			"enable_autoscaling": true,
+			"min_capacity":       1,
+			"max_capacity":       2,
+			// A low target so a test runner's traffic is enough to scale out
+			"target_cpu_utilization": targetCPU,
+			"scale_out_cooldown":     60,
+			"scale_in_cooldown":      300,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with CPU autoscaling...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+	tgArn := terraform.Output(t, terraformOptions, "target_group_arn")
+	url := terraform.Output(t, terraformOptions, "url")
+
+	helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+	http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+
+	spikeStart := time.Now()
+	stopLoad := startHTTPLoad(url, 50)
+	defer stopLoad()
+	t.Log("Load spike started")
+
+	timeline := helpers.MeasureECSScaleOut(t, sess, clusterName, serviceName, tgArn, float64(targetCPU), spikeStart, 15*time.Minute)
+
+	stages := []time.Time{timeline.ThresholdBreached, timeline.AlarmTriggered, timeline.ScalingStarted, timeline.TaskRunning, timeline.TargetHealthy}
+	for i := 1; i < len(stages); i++ {
+		require.False(t, stages[i].Before(stages[i-1]), "Scale-out stages are out of order: %s", timeline)
+	}
+
+	assert.LessOrEqual(t, timeline.Total(), ecsScaleOutSLO, "Scale-out took longer than the %s SLO: %s", ecsScaleOutSLO, timeline)
+	t.Logf("✅ New task was serving traffic %s after the CPU target was breached", timeline.Total())
+}
+
+// startHTTPLoad sends requests to url from the given number of concurrent workers until the returned function is
+// called, which waits for them to finish
+func startHTTPLoad(url string, workers int) func() {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	client := &http.Client{Timeout: 10 * time.Second}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if resp, err := client.Get(url); err == nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+			}
+		}()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			wg.Wait()
+		})
+	}
+}
+
+// TestECSDefaultResponse verifies requests that match none of the routed paths get the configured fixed response from
+// the ALB instead of reaching the service
+func TestECSDefaultResponse(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-default-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	statusCode := 404
+	contentType := "application/json"
+	messageBody := `{"error": "not_found"}`
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                 name,
+			"routed_path_patterns": []string{"/"},
+			"default_fixed_response": map[string]interface{}{
+				"content_type": contentType,
+				"message_body": messageBody,
+				"status_code":  statusCode,
+			},
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with a default fixed response...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	url := terraform.Output(t, terraformOptions, "url")
+
+	t.Run("ListenerRules", func(t *testing.T) {
+		helpers.AssertALBDefaultFixedResponse(t, sess, terraform.Output(t, terraformOptions, "alb_listener_arn"),
+			statusCode, contentType, messageBody)
+	})
+
+	t.Run("RoutedPathReachesService", func(t *testing.T) {
+		http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+	})
+
+	t.Run("UnmatchedPathGetsFixedResponse", func(t *testing.T) {
+		resp, err := http.Get(url + "/no-such-route")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, statusCode, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), contentType)
+		assert.Equal(t, messageBody, string(body))
+		t.Logf("✅ Unmatched path got %d %s", resp.StatusCode, string(body))
+	})
+}
+
+// TestECSTargetGroupProtocol verifies the target group's protocol, protocol version, and health check path, matcher, and
+// threshold match the module inputs, and that with them the service's tasks pass their health checks
+func TestECSTargetGroupProtocol(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-tg-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+	desiredCount := 2
+
+	// The example app serves plain HTTP/1.1, so the target group must too. A range is looser than the module default
+	// of 200 so a redirect from the health check path wouldn't mark the targets unhealthy.
+	healthCheckMatcher := "200-399"
+	healthCheckPath := "/"
+	healthyThreshold := 3
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                           name,
+			"desired_count":                  desiredCount,
+			"health_check_path":              healthCheckPath,
+			"health_check_matcher":           healthCheckMatcher,
+			"health_check_healthy_threshold": healthyThreshold,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	// The Django app answers health checks on /health/live/; checking / instead gets a redirect and the targets flap
+	t.Run("DjangoHealthCheckPlanned", func(t *testing.T) {
+		planOptions := &terraform.Options{
+			TerraformDir:    terraformOptions.TerraformDir,
+			TerraformBinary: terraformOptions.TerraformBinary,
+			Vars: map[string]interface{}{
+				"name":                 name,
+				"health_check_path":    "/health/live/",
+				"health_check_matcher": "200-299",
+			},
+			PlanFilePath: filepath.Join(t.TempDir(), "health-check.tfplan"),
+			EnvVars:      terraformOptions.EnvVars,
+		}
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, planOptions)
+		targetGroup, ok := plan.ResourcePlannedValuesMap["module.ecs_service.aws_lb_target_group.ecs"]
+		require.True(t, ok, "Target group not found in plan")
+
+		healthChecks, ok := targetGroup.AttributeValues["health_check"].([]interface{})
+		require.True(t, ok && len(healthChecks) == 1, "Target group should have one health_check block")
+		healthCheck := healthChecks[0].(map[string]interface{})
+		assert.Equal(t, "/health/live/", healthCheck["path"])
+		assert.Equal(t, "200-299", healthCheck["matcher"])
+	})
+
+	t.Run("InvalidMatcherFailsPlan", func(t *testing.T) {
+		invalidOptions := &terraform.Options{
+			TerraformDir:    terraformOptions.TerraformDir,
+			TerraformBinary: terraformOptions.TerraformBinary,
+			Vars: map[string]interface{}{
+				"name":                 name,
+				"health_check_matcher": "2xx",
+			},
+			EnvVars: terraformOptions.EnvVars,
+		}
+
+		_, err := terraform.InitAndPlanE(t, invalidOptions)
+		require.Error(t, err, "Plan should reject a matcher that isn't codes or ranges")
+		assert.Contains(t, err.Error(), "health_check_matcher must be a code, a range")
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	tgArn := terraform.Output(t, terraformOptions, "target_group_arn")
+
+	t.Run("Config", func(t *testing.T) {
+		config := helpers.GetTargetGroupConfig(t, sess, tgArn)
+
+		assert.Equal(t, "HTTP", config.Protocol, "The ALB should reach the container over HTTP, as the app serves")
+		assert.Equal(t, "HTTP1", config.ProtocolVersion)
+		assert.Equal(t, config.Protocol, config.HealthCheckProtocol, "Health checks should use the same protocol as traffic")
+		assert.Equal(t, healthCheckMatcher, config.Matcher)
+		t.Logf("✅ Target group uses %s/%s with health check matcher %s", config.Protocol, config.ProtocolVersion, config.Matcher)
+	})
+
+	t.Run("HealthCheckConfig", func(t *testing.T) {
+		config := helpers.GetTargetGroupConfig(t, sess, tgArn)
+
+		assert.Equal(t, healthCheckPath, config.HealthCheckPath)
+		assert.Equal(t, healthCheckMatcher, config.Matcher, "The matcher range should be kept as given")
+		assert.EqualValues(t, healthyThreshold, config.HealthyThreshold)
+		t.Logf("✅ Target group checks %s for %s, healthy after %d checks", config.HealthCheckPath, config.Matcher, config.HealthyThreshold)
+	})
+
+	t.Run("TargetsHealthy", func(t *testing.T) {
+		helpers.WaitForHealthyTargets(t, sess, tgArn, desiredCount, 10*time.Minute)
+	})
+}
+
+// TestECSZeroDesiredCount verifies a service deployed paused, with desired_count = 0, exists with no tasks, answers with
+// the paused response rather than the ALB's generic 503, and resumes serving when scaled back up
+func TestECSZeroDesiredCount(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-paused-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+	pausedBody := "503: service paused for maintenance"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":          name,
+			"desired_count": 0,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying paused ECS Fargate service...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+	tgArn := terraform.Output(t, terraformOptions, "target_group_arn")
+	url := terraform.Output(t, terraformOptions, "url")
+
+	t.Run("Paused", func(t *testing.T) {
+		assert.Equal(t, "true", terraform.Output(t, terraformOptions, "paused"))
+
+		service := describeECSService(t, sess, clusterName, serviceName)
+		assert.Equal(t, "ACTIVE", aws.StringValue(service.Status), "A paused service should still exist")
+		assert.Zero(t, aws.Int64Value(service.DesiredCount))
+		assert.Zero(t, aws.Int64Value(service.RunningCount), "A paused service should run no tasks")
+		assert.Zero(t, helpers.CountHealthyTargets(t, sess, tgArn), "A paused service should have no healthy targets")
+
+		http_helper.HttpGetWithRetry(t, url, nil, 503, pausedBody, 30, 10*time.Second)
+		t.Log("✅ Paused service returns the configured 503")
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		terraformOptions.Vars["desired_count"] = 1
+		terraform.Apply(t, terraformOptions)
+
+		assert.Equal(t, "false", terraform.Output(t, terraformOptions, "paused"))
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+		helpers.WaitForHealthyTargets(t, sess, tgArn, 1, 5*time.Minute)
+
+		http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 30, 10*time.Second)
+		t.Log("✅ Service resumed serving after scaling back to 1")
+	})
+}
+
+// describeECSService returns the current state of an ECS service
+func describeECSService(t *testing.T, sess *session.Session, clusterName, serviceName string) *ecs.Service {
+	result, err := ecs.New(sess).DescribeServices(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []*string{aws.String(serviceName)},
+	})
+	require.NoError(t, err, "Failed to describe ECS service")
+	require.Len(t, result.Services, 1, "ECS service %s not found", serviceName)
+
+	return result.Services[0]
+}
+
+// TestECSClusterCapacityProviders verifies the cluster has FARGATE and FARGATE_SPOT associated with the expected default
+// strategy, so a service that mixes the two can place tasks instead of failing with "No Capacity Provider"
+func TestECSClusterCapacityProviders(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-cp-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	serviceStrategy := []map[string]interface{}{
+		{"capacity_provider": "FARGATE", "weight": 1, "base": 1},
+		{"capacity_provider": "FARGATE_SPOT", "weight": 1, "base": 0},
+	}
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                               name,
+			"desired_count":                      2,
+			"service_capacity_provider_strategy": serviceStrategy,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	t.Run("MissingProviderFailsPlan", func(t *testing.T) {
+		missingSpotOptions := &terraform.Options{
+			TerraformDir:    terraformOptions.TerraformDir,
+			TerraformBinary: terraformOptions.TerraformBinary,
+			Vars: map[string]interface{}{
+				"name":                               name,
+				"capacity_providers":                 []string{"FARGATE"},
+				"service_capacity_provider_strategy": serviceStrategy,
+			},
+			EnvVars: terraformOptions.EnvVars,
+		}
+
+		_, err := terraform.InitAndPlanE(t, missingSpotOptions)
+		require.Error(t, err, "Plan should fail when the service uses a provider the cluster doesn't have")
+		assert.Contains(t, err.Error(), "Add the missing provider to capacity_providers")
+		t.Log("✅ Plan rejected a service strategy using an unassociated capacity provider")
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service on FARGATE and FARGATE_SPOT...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	t.Run("ClusterAssociations", func(t *testing.T) {
+		helpers.AssertClusterCapacityProviders(t, sess, clusterName,
+			[]string{"FARGATE", "FARGATE_SPOT"},
+			[]helpers.ECSCapacityProviderStrategyItem{
+				{CapacityProvider: "FARGATE", Weight: 1, Base: 0},
+			})
+	})
+
+	t.Run("ServiceUsesStrategy", func(t *testing.T) {
+		result, err := ecs.New(sess).DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterName),
+			Services: []*string{aws.String(serviceName)},
+		})
+		require.NoError(t, err, "Failed to describe ECS service")
+		require.Len(t, result.Services, 1)
+
+		var providers []string
+		for _, item := range result.Services[0].CapacityProviderStrategy {
+			providers = append(providers, aws.StringValue(item.CapacityProvider))
+		}
+		assert.ElementsMatch(t, []string{"FARGATE", "FARGATE_SPOT"}, providers)
+
+		// Tasks are only placed if every provider in the strategy is associated with the cluster
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+		t.Log("✅ Service placed tasks using its FARGATE/FARGATE_SPOT strategy")
+	})
+}
+
+// TestECSFargateSpot deploys a service that keeps one task on FARGATE and weights the rest 1:3 towards FARGATE_SPOT,
+// and verifies via DescribeServices that ECS applied exactly that strategy. A service can't have both a launch type and
+// a capacity provider strategy, so it also checks the module dropped launch_type.
+func TestECSFargateSpot(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-spot-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	expected := map[string]helpers.ECSCapacityProviderStrategyItem{
+		"FARGATE":      {CapacityProvider: "FARGATE", Weight: 1, Base: 1},
+		"FARGATE_SPOT": {CapacityProvider: "FARGATE_SPOT", Weight: 3, Base: 0},
+	}
+
+	var serviceStrategy []map[string]interface{}
+	for _, item := range expected {
+		serviceStrategy = append(serviceStrategy, map[string]interface{}{
+			"capacity_provider": item.CapacityProvider,
+			"weight":            item.Weight,
+			"base":              item.Base,
+		})
+	}
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                               name,
+			"desired_count":                      4,
+			"service_capacity_provider_strategy": serviceStrategy,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	t.Run("StrategyDropsLaunchType", func(t *testing.T) {
+		planOptions := *terraformOptions
+		planOptions.PlanFilePath = filepath.Join(t.TempDir(), "spot.tfplan")
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, &planOptions)
+		service, ok := plan.ResourcePlannedValuesMap["module.ecs_service.aws_ecs_service.service[0]"]
+		require.True(t, ok, "ECS service not found in plan")
+
+		assert.Empty(t, service.AttributeValues["launch_type"], "launch_type must not be set alongside a capacity provider strategy")
+		assert.Len(t, service.AttributeValues["capacity_provider_strategy"], len(expected))
+		t.Log("✅ Plan sets a capacity provider strategy without a launch type")
+	})
+
+	t.Run("TwoBasesFailPlan", func(t *testing.T) {
+		invalidOptions := &terraform.Options{
+			TerraformDir:    terraformOptions.TerraformDir,
+			TerraformBinary: terraformOptions.TerraformBinary,
+			Vars: map[string]interface{}{
+				"name": name,
+				"service_capacity_provider_strategy": []map[string]interface{}{
+					{"capacity_provider": "FARGATE", "weight": 1, "base": 1},
+					{"capacity_provider": "FARGATE_SPOT", "weight": 3, "base": 1},
+				},
+			},
+			EnvVars: terraformOptions.EnvVars,
+		}
+
+		_, err := terraform.InitAndPlanE(t, invalidOptions)
+		require.Error(t, err, "Plan should fail when more than one provider has a base")
+		assert.Contains(t, err.Error(), "Only one provider in service_capacity_provider_strategy can have a base")
+		t.Log("✅ Plan rejected a strategy with two bases")
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with a FARGATE base and FARGATE_SPOT weighting...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	t.Run("ServiceStrategyMatchesWeights", func(t *testing.T) {
+		service := describeECSService(t, sess, clusterName, serviceName)
+		assert.Empty(t, aws.StringValue(service.LaunchType), "Service should not have a launch type")
+
+		actual := make(map[string]helpers.ECSCapacityProviderStrategyItem)
+		for _, item := range service.CapacityProviderStrategy {
+			provider := aws.StringValue(item.CapacityProvider)
+			actual[provider] = helpers.ECSCapacityProviderStrategyItem{
+				CapacityProvider: provider,
+				Weight:           aws.Int64Value(item.Weight),
+				Base:             aws.Int64Value(item.Base),
+			}
+		}
+		assert.Equal(t, expected, actual, "DescribeServices should report the configured strategy")
+		t.Logf("✅ Service strategy: %v", actual)
+	})
+
+	t.Run("OutputMatchesStrategy", func(t *testing.T) {
+		assert.Empty(t, terraform.Output(t, terraformOptions, "launch_type"))
+
+		strategy := terraform.OutputListOfObjects(t, terraformOptions, "capacity_provider_strategy")
+		require.Len(t, strategy, len(expected))
+		for _, item := range strategy {
+			provider := fmt.Sprint(item["capacity_provider"])
+			want, ok := expected[provider]
+			require.True(t, ok, "Unexpected provider %s in capacity_provider_strategy output", provider)
+			assert.EqualValues(t, want.Weight, item["weight"], "Weight for %s", provider)
+			assert.EqualValues(t, want.Base, item["base"], "Base for %s", provider)
+		}
+		t.Log("✅ capacity_provider_strategy output reports the effective strategy")
+	})
+
+	t.Run("TasksPlaced", func(t *testing.T) {
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+		t.Log("✅ Service placed its tasks across FARGATE and FARGATE_SPOT")
+	})
+}
+
+// TestECSOutOfMemory runs a workload that grows past the container's 512 MB limit and verifies the stopped task
+// reports OutOfMemoryError, so exceeding the module's memory sizing is diagnosable from DescribeTasks, and that ECS
+// replaces the task
+func TestECSOutOfMemory(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-oom-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":          name,
+			"desired_count": 1,
+			// Allocate 10 MB at a time until the kernel's OOM killer stops the container
+			"container_command": []string{"python", "-c", "chunks = []\nwhile True: chunks.append(' ' * 10 ** 7)"},
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with a memory-hungry workload...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	stopped := helpers.WaitForStoppedECSTask(t, sess, clusterName, serviceName, 10*time.Minute)
+
+	t.Run("StoppedReasonIsOOM", func(t *testing.T) {
+		var container *ecs.Container
+		for _, c := range stopped.Containers {
+			if aws.StringValue(c.Name) == name {
+				container = c
+			}
+		}
+		require.NotNil(t, container, "Stopped task has no %s container", name)
+
+		assert.Contains(t, aws.StringValue(container.Reason), "OutOfMemoryError",
+			"Container should report it was killed for exceeding its memory limit")
+		assert.Equal(t, int64(137), aws.Int64Value(container.ExitCode), "An OOM-killed container exits with SIGKILL")
+		assert.Equal(t, ecs.TaskStopCodeEssentialContainerExited, aws.StringValue(stopped.StopCode))
+		t.Logf("✅ Task stopped with %q; container reason %q", aws.StringValue(stopped.StoppedReason), aws.StringValue(container.Reason))
+	})
+
+	t.Run("TaskReplaced", func(t *testing.T) {
+		replacement := helpers.WaitForECSTaskReplaced(t, sess, clusterName, serviceName, stopped, 10*time.Minute)
+		t.Logf("✅ ECS replaced task %s with %s", aws.StringValue(stopped.TaskArn), replacement)
+	})
+}
+
+// TestECSHTTPS verifies that with a certificate the ALB serves the app over HTTPS and permanently redirects plain HTTP
+// to it, and that enable_https without a certificate falls back to HTTP-only rather than planning a broken listener
+func TestECSHTTPS(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-https-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	t.Run("EmptyCertificateFallsBackToHTTP", func(t *testing.T) {
+		planOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":            fmt.Sprintf("%s-plan", name),
+				"enable_https":    true,
+				"certificate_arn": "",
+			},
+			PlanFilePath: filepath.Join(t.TempDir(), "https.tfplan"),
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, planOptions)
+		assert.NotContains(t, plan.ResourcePlannedValuesMap, "module.ecs_service.aws_lb_listener.https[0]",
+			"No HTTPS listener should be planned without a certificate")
+
+		httpListener, ok := plan.ResourcePlannedValuesMap["module.ecs_service.aws_lb_listener.http"]
+		require.True(t, ok, "The HTTP listener should always be planned")
+		actions, _ := httpListener.AttributeValues["default_action"].([]interface{})
+		require.Len(t, actions, 1)
+		assert.Equal(t, "fixed-response", actions[0].(map[string]interface{})["type"],
+			"The HTTP listener should serve the app rather than redirect to an HTTPS listener that doesn't exist")
+		t.Log("✅ enable_https without a certificate planned an HTTP-only ALB")
+	})
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	// Registered before the destroy so it runs after the listener has released the certificate
+	certificateARN, certificatePEM := helpers.ImportSelfSignedCertificate(t, sess, fmt.Sprintf("*.%s.elb.amazonaws.com", awsRegion))
+	defer helpers.DeleteACMCertificate(t, sess, certificateARN)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":            name,
+			"enable_https":    true,
+			"certificate_arn": certificateARN,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service behind an HTTPS listener...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	httpURL := terraform.Output(t, terraformOptions, "url")
+	httpsURL := terraform.Output(t, terraformOptions, "https_url")
+	require.True(t, strings.HasPrefix(httpsURL, "https://"), "https_url should be set when HTTPS is enabled, got %q", httpsURL)
+
+	t.Run("HTTPRedirectsToHTTPS", func(t *testing.T) {
+		client := &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Get(httpURL + "/some/path")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(location, httpsURL), "Redirect should go to %s, got %q", httpsURL, location)
+		assert.True(t, strings.HasSuffix(location, "/some/path"), "Redirect should keep the path, got %q", location)
+		t.Logf("✅ HTTP redirected with %d to %s", resp.StatusCode, location)
+	})
+
+	t.Run("HTTPSServesApp", func(t *testing.T) {
+		roots := x509.NewCertPool()
+		require.True(t, roots.AppendCertsFromPEM(certificatePEM), "Failed to trust the test certificate")
+
+		http_helper.HttpGetWithRetry(t, httpsURL, &tls.Config{RootCAs: roots}, 200, "Hello World!", 30, 10*time.Second)
+		t.Logf("✅ %s served the app over verified TLS", httpsURL)
+	})
+}
+
+// TestECSSecrets deploys the service with a whole secret and one key of a JSON secret injected as environment
+// variables, and verifies the task definition maps both, the execution role can read exactly that secret, and the task
+// starts, which it can only do once ECS has resolved the secrets
+func TestECSSecrets(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-secrets-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	secretARN := helpers.CreateTestSecret(t, sess, name, `{"username": "app", "password": "not-a-real-password"}`)
+	defer helpers.DeleteTestSecret(t, sess, secretARN)
+
+	secrets := map[string]string{
+		"APP_CREDENTIALS": secretARN,
+		"DB_PASSWORD":     secretARN + ":password::",
+	}
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":    name,
+			"secrets": secrets,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with injected secrets...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	taskDefinition, err := ecs.New(sess).DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(terraform.Output(t, terraformOptions, "task_definition_arn")),
+	})
+	require.NoError(t, err)
+
+	t.Run("TaskDefinitionSecrets", func(t *testing.T) {
+		var container *ecs.ContainerDefinition
+		for _, c := range taskDefinition.TaskDefinition.ContainerDefinitions {
+			if aws.StringValue(c.Name) == name {
+				container = c
+			}
+		}
+		require.NotNil(t, container, "Task definition has no %s container", name)
+
+		mapped := map[string]string{}
+		for _, secret := range container.Secrets {
+			mapped[aws.StringValue(secret.Name)] = aws.StringValue(secret.ValueFrom)
+		}
+		assert.Equal(t, secrets, mapped, "The container should get exactly the secrets passed in, JSON key selector included")
+
+		for _, variable := range container.Environment {
+			assert.NotContains(t, aws.StringValue(variable.Value), "not-a-real-password", "A secret value leaked into a plain environment variable")
+		}
+		t.Logf("✅ Container %s maps %d secrets", name, len(mapped))
+	})
+
+	t.Run("ExecutionRoleCanReadSecret", func(t *testing.T) {
+		executionRoleARN := aws.StringValue(taskDefinition.TaskDefinition.ExecutionRoleArn)
+		resources := helpers.GetRoleInlinePolicyResources(t, sess, executionRoleARN, "secretsmanager:GetSecretValue")
+		assert.Equal(t, []string{secretARN}, resources, "The execution role should be granted the secret's ARN, without the JSON key")
+		t.Logf("✅ Execution role %s can read %s", executionRoleARN, secretARN)
+	})
+
+	t.Run("TaskStarts", func(t *testing.T) {
+		clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+		serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+		helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+		http_helper.HttpGetWithRetry(t, terraform.Output(t, terraformOptions, "url"), nil, 200, "Hello World!", 30, 10*time.Second)
+	})
+}
+
+// TestECSContainerHealthCheck verifies the container health check variables end up in the task definition ECS
+// registers, and that the default empty command leaves the healthCheck block out instead of emitting an invalid one
+func TestECSContainerHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-hc-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	command := []string{"CMD-SHELL", "curl -f http://localhost:5000/ || exit 1"}
+	interval, timeout, retries, startPeriod := 15, 4, 2, 45
+
+	t.Run("EmptyCommandOmitsHealthCheck", func(t *testing.T) {
+		planOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":                 fmt.Sprintf("%s-plan", name),
+				"health_check_command": []string{},
+			},
+			PlanFilePath: filepath.Join(t.TempDir(), "health-check.tfplan"),
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, planOptions)
+		taskDefinition, ok := plan.ResourcePlannedValuesMap["module.ecs_service.aws_ecs_task_definition.service"]
+		require.True(t, ok, "The task definition should be planned")
+
+		var containers []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(taskDefinition.AttributeValues["container_definitions"].(string)), &containers))
+		for _, container := range containers {
+			assert.NotContains(t, container, "healthCheck", "Container %v should have no health check without a command", container["name"])
+		}
+		t.Log("✅ No healthCheck block planned for an empty health_check_command")
+	})
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                      name,
+			"health_check_command":      command,
+			"health_check_interval":     interval,
+			"health_check_timeout":      timeout,
+			"health_check_retries":      retries,
+			"health_check_start_period": startPeriod,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with a container health check...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	t.Run("TaskDefinitionHealthCheck", func(t *testing.T) {
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		result, err := ecs.New(sess).DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(terraform.Output(t, terraformOptions, "task_definition_arn")),
+		})
+		require.NoError(t, err)
+
+		var healthCheck *ecs.HealthCheck
+		for _, container := range result.TaskDefinition.ContainerDefinitions {
+			if aws.StringValue(container.Name) == name {
+				healthCheck = container.HealthCheck
+			}
+		}
+		require.NotNil(t, healthCheck, "Container %s has no health check", name)
+
+		assert.Equal(t, command, aws.StringValueSlice(healthCheck.Command))
+		assert.Equal(t, int64(interval), aws.Int64Value(healthCheck.Interval))
+		assert.Equal(t, int64(timeout), aws.Int64Value(healthCheck.Timeout))
+		assert.Equal(t, int64(retries), aws.Int64Value(healthCheck.Retries))
+		assert.Equal(t, int64(startPeriod), aws.Int64Value(healthCheck.StartPeriod))
+		t.Logf("✅ Container %s checks health with %v every %ds (timeout %ds, %d retries, %ds start period)",
+			name, command, interval, timeout, retries, startPeriod)
+	})
+}
+
+// TestECSContainerInsights deploys a service without Container Insights, turns it on, and verifies the cluster setting
+// is enabled and that the toggle updated the cluster in place rather than recreating the cluster or the service
+func TestECSContainerInsights(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-ci-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                      name,
+			"enable_container_insights": false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service without Container Insights...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	require.Equal(t, "disabled", helpers.GetECSClusterSetting(t, sess, clusterName, "containerInsights"))
+	before := describeECSService(t, sess, clusterName, serviceName)
+
+	terraformOptions.Vars["enable_container_insights"] = true
+
+	t.Run("ToggleUpdatesInPlace", func(t *testing.T) {
+		planOptions := *terraformOptions
+		planOptions.PlanFilePath = filepath.Join(t.TempDir(), "insights.tfplan")
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, &planOptions)
+		helpers.AssertNoResourcesReplaced(t, plan, "module.ecs_service.aws_ecs_")
+
+		change, ok := plan.ResourceChangesMap["module.ecs_service.aws_ecs_cluster.fargate"]
+		require.True(t, ok, "Plan has no changes for the cluster")
+		assert.True(t, change.Change.Actions.Update(), "Cluster should be updated in place (actions: %v)", change.Change.Actions)
+	})
+
+	t.Log("Enabling Container Insights...")
+	terraform.Apply(t, terraformOptions)
+
+	t.Run("ContainerInsightsEnabled", func(t *testing.T) {
+		assert.Equal(t, "enabled", helpers.GetECSClusterSetting(t, sess, clusterName, "containerInsights"))
+		t.Logf("✅ Container Insights enabled on %s", clusterName)
+	})
+
+	t.Run("ServiceNotRecreated", func(t *testing.T) {
+		after := describeECSService(t, sess, clusterName, serviceName)
+		assert.Equal(t, aws.StringValue(before.ServiceArn), aws.StringValue(after.ServiceArn))
+		assert.Equal(t, aws.TimeValue(before.CreatedAt), aws.TimeValue(after.CreatedAt), "Service should not have been recreated")
+		t.Log("✅ Service kept running through the toggle")
+	})
+}
+
+// ecsCircuitBreakerEnvVar must be set to run TestECSDeploymentCircuitBreaker, which deliberately fails a deployment
+const ecsCircuitBreakerEnvVar = "RUN_ECS_CIRCUIT_BREAKER_TEST"
+
+// TestECSDeploymentCircuitBreaker deploys a working service, then applies an image tag that doesn't exist, and verifies
+// the circuit breaker stops the failed deployment and rolls back so the running tasks are on the previous revision,
+// not the broken one. It intentionally fails a deployment and takes up to half an hour, so it only runs when
+// RUN_ECS_CIRCUIT_BREAKER_TEST is set.
+func TestECSDeploymentCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(ecsCircuitBreakerEnvVar) == "" {
+		t.Skipf("Set %s to run a deliberately failing deployment", ecsCircuitBreakerEnvVar)
+	}
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-cb-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                              name,
+			"desired_count":                     2,
+			"enable_deployment_circuit_breaker": true,
+			"enable_rollback":                   true,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with the deployment circuit breaker...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	clusterName := terraform.Output(t, terraformOptions, "ecs_cluster_name")
+	serviceName := terraform.Output(t, terraformOptions, "ecs_service_name")
+
+	helpers.WaitForECSServiceStable(t, sess, clusterName, serviceName, 10*time.Minute)
+	priorARN := helpers.GetECSRunningTaskDefinition(t, sess, clusterName, serviceName)
+	t.Logf("Service %s runs %s before the broken deploy", serviceName, priorARN)
+
+	// Every task of the new revision fails to pull its image, which trips the circuit breaker
+	terraformOptions.Vars["container_image"] = fmt.Sprintf("training/webapp:does-not-exist-%s", strings.ToLower(uniqueID))
+	terraform.Apply(t, terraformOptions)
+
+	brokenARN := terraform.Output(t, terraformOptions, "task_definition_arn")
+	require.NotEqual(t, priorARN, brokenARN, "The broken deploy should have registered a new task definition revision")
+
+	helpers.WaitForECSRollback(t, sess, clusterName, serviceName, brokenARN, 30*time.Minute)
+
+	t.Run("RolledBackToPriorRevision", func(t *testing.T) {
+		runningARN := helpers.GetECSRunningTaskDefinition(t, sess, clusterName, serviceName)
+		assert.NotEqual(t, brokenARN, runningARN, "Tasks should not run the broken revision")
+		assert.Equal(t, priorARN, runningARN, "Tasks should run the revision from before the broken deploy")
+		t.Logf("✅ Rolled back from %s to %s", brokenARN, runningARN)
+	})
+
+	t.Run("ServiceStillServes", func(t *testing.T) {
+		testECSHTTPEndpoint(t, terraformOptions)
+	})
+}
+
+// TestECSAccessLogs deploys the service with ALB access logs going to a new bucket, sends it some requests, and waits
+// for an access log file to be delivered. ELB writes logs every 5 minutes, so the wait allows up to 10.
+func TestECSAccessLogs(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("ecs-logs-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+	prefix := "alb"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"enable_access_logs": true,
+			"access_logs_prefix": prefix,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying ECS Fargate service with ALB access logs...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	bucket := terraform.Output(t, terraformOptions, "access_logs_bucket")
+
+	t.Run("Enabled", func(t *testing.T) {
+		assert.Equal(t, "true", terraform.Output(t, terraformOptions, "access_logs_enabled"))
+	})
+
+	t.Run("LogsDelivered", func(t *testing.T) {
+		testECSHTTPEndpoint(t, terraformOptions)
+
+		url := terraform.Output(t, terraformOptions, "url")
+		for i := 0; i < 20; i++ {
+			http_helper.HttpGetWithRetry(t, url, nil, 200, "Hello World!", 5, 2*time.Second)
+		}
+
+		// ELB also writes an ELBAccessLogTestFile when logging is enabled, so only count files under elasticloadbalancing/
+		logPrefix := fmt.Sprintf("%s/AWSLogs/%s/elasticloadbalancing/", prefix, helpers.GetAccountID(t, sess))
+		key := helpers.WaitForS3ObjectWithPrefix(t, sess, bucket, logPrefix, helpers.RetryConfig{
+			MaxRetries:    40,
+			RetryInterval: 15 * time.Second,
+			Description:   "ALB access log delivery",
+		})
+		assert.True(t, strings.HasSuffix(key, ".log.gz"), "Unexpected access log object %s", key)
+		t.Logf("✅ Access log delivered to s3://%s/%s", bucket, key)
+	})
+}
@@ -0,0 +1,72 @@
+package modules_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExamplesCoverModuleVariables parses every example and the modules it calls, and checks each call sets every
+// required variable of the module and nothing the module doesn't declare. A module refactor that renames or adds a
+// required variable otherwise only shows up when the example's tests fail to plan.
+func TestExamplesCoverModuleVariables(t *testing.T) {
+	t.Parallel()
+
+	examples, err := filepath.Glob("../../examples/tofu/*/main.tf")
+	require.NoError(t, err)
+	require.NotEmpty(t, examples, "No examples found")
+
+	for _, example := range examples {
+		exampleDir := filepath.Dir(example)
+
+		t.Run(filepath.Base(exampleDir), func(t *testing.T) {
+			t.Parallel()
+
+			calls := helpers.ParseLocalModuleCalls(t, exampleDir)
+			require.NotEmpty(t, calls, "Example %s calls no module in this repo", exampleDir)
+
+			for _, call := range calls {
+				if _, err := os.Stat(call.Source); err != nil {
+					assert.Fail(t, fmt.Sprintf("module.%s in %s calls %s, which doesn't exist", call.Name, exampleDir, call.Source))
+					continue
+				}
+				variables := helpers.ParseModuleVariables(t, call.Source)
+
+				supplied := map[string]bool{}
+				for _, argument := range call.Arguments {
+					supplied[argument] = true
+					assert.Contains(t, variables, argument,
+						"module.%s in %s sets %s, which %s doesn't declare", call.Name, exampleDir, argument, call.Source)
+				}
+
+				var missing []string
+				for name, variable := range variables {
+					if variable.Required && !supplied[name] {
+						missing = append(missing, name)
+					}
+				}
+				assert.Empty(t, missing, "module.%s in %s doesn't set required variable(s) %s of %s",
+					call.Name, exampleDir, strings.Join(missing, ", "), call.Source)
+
+				t.Logf("✅ module.%s sets all %d required variable(s) of %s", call.Name, countRequired(variables), call.Source)
+			}
+		})
+	}
+}
+
+// countRequired returns how many of the variables have no default
+func countRequired(variables map[string]helpers.ModuleVariable) int {
+	required := 0
+	for _, variable := range variables {
+		if variable.Required {
+			required++
+		}
+	}
+	return required
+}
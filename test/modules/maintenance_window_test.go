@@ -0,0 +1,55 @@
+package modules_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+)
+
+// TestMaintenanceWindowCompliance deploys PostgreSQL and Redis with the modules' default maintenance windows and
+// verifies the windows AWS applied fall within the change-freeze policy's approved off-hours window (override it with
+// APPROVED_MAINTENANCE_WINDOW) and never overlap business hours
+func TestMaintenanceWindowCompliance(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("maint-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	approved := helpers.ApprovedMaintenanceWindow(t)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/data-tier",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":            name,
+			"master_username": "testadmin",
+			"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying PostgreSQL and Redis with default maintenance windows... (this may take 10-15 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		dbIdentifier := helpers.DBInstanceIdentifierFromARN(terraform.Output(t, terraformOptions, "db_arn"))
+		window := helpers.GetRDSMaintenanceWindow(t, sess, dbIdentifier)
+		helpers.AssertMaintenanceWindowCompliant(t, fmt.Sprintf("RDS instance %s", dbIdentifier), window, approved)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		replicationGroupID := terraform.Output(t, terraformOptions, "redis_replication_group_id")
+		for clusterID, window := range helpers.GetElastiCacheMaintenanceWindows(t, sess, replicationGroupID) {
+			helpers.AssertMaintenanceWindowCompliant(t, fmt.Sprintf("cache cluster %s", clusterID), window, approved)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package modules_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNamingUniqueness verifies names generated concurrently never collide and always satisfy the service's naming
+// rules, and that two deployments sharing a logical name don't clash on account- or globally-unique resources
+func TestNamingUniqueness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ConcurrentGeneration", func(t *testing.T) {
+		workers := 50
+		namesPerWorker := 200
+
+		constraints := []helpers.NamingConstraints{
+			helpers.S3BucketNaming,
+			helpers.RDSIdentifierNaming,
+			helpers.ElastiCacheNaming,
+			helpers.ALBNaming,
+		}
+
+		// Awkward bases exercise sanitizing and truncation
+		bases := []string{
+			"pg-test",
+			"Cache_Test.Upper",
+			"9starts-with-digit",
+			"--hyphens--everywhere--",
+			strings.Repeat("very-long-base-name-", 5),
+		}
+
+		for _, constraint := range constraints {
+			constraint := constraint
+			t.Run(strings.ReplaceAll(constraint.Description, " ", "_"), func(t *testing.T) {
+				var mu sync.Mutex
+				seen := make(map[string]bool, workers*namesPerWorker)
+				var wg sync.WaitGroup
+
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func(w int) {
+						defer wg.Done()
+						for i := 0; i < namesPerWorker; i++ {
+							name := helpers.UniqueResourceName(bases[(w+i)%len(bases)], constraint)
+
+							// t.Error is safe to call from other goroutines, unlike require's t.FailNow
+							if err := helpers.ValidateResourceName(name, constraint); err != nil {
+								t.Error(err)
+							}
+
+							mu.Lock()
+							if seen[name] {
+								t.Errorf("Collision: %q generated more than once", name)
+							}
+							seen[name] = true
+							mu.Unlock()
+						}
+					}(w)
+				}
+
+				wg.Wait()
+				assert.Len(t, seen, workers*namesPerWorker, "Every generated name should be unique")
+				t.Logf("✅ Generated %d unique, valid %s names concurrently", len(seen), constraint.Description)
+			})
+		}
+	})
+
+	t.Run("ParallelDeployments", func(t *testing.T) {
+		// Both deployments share the same logical base; only the unique suffix differs
+		base := "naming-test"
+
+		t.Run("S3Buckets", func(t *testing.T) {
+			deployInParallel(t, "examples/tofu/s3-cdn-bucket", base, helpers.S3BucketNaming, nil,
+				"bucket_name", func(output string) string { return output })
+		})
+
+		t.Run("RDSIdentifiers", func(t *testing.T) {
+			t.Log("Deploying two PostgreSQL instances in parallel... (this may take 10-15 minutes)")
+			deployInParallel(t, "examples/tofu/postgresql", base, helpers.RDSIdentifierNaming, map[string]interface{}{
+				"master_username": "testadmin",
+				"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+				"multi_az":        false,
+			}, "arn", helpers.DBInstanceIdentifierFromARN)
+		})
+	})
+}
+
+// deployInParallel applies two copies of an example concurrently, each named with UniqueResourceName from the same
+// base, and asserts both succeed and that nameFromOutput recovers each deployment's own name from the given output
+func deployInParallel(t *testing.T, exampleDir, base string, constraints helpers.NamingConstraints, extraVars map[string]interface{}, output string, nameFromOutput func(string) string) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	names := make([]string, 2)
+	results := make([]string, 2)
+	errs := make([]error, 2)
+
+	for i := range names {
+		names[i] = helpers.UniqueResourceName(base, constraints)
+		vars := map[string]interface{}{"name": names[i]}
+		for key, value := range extraVars {
+			vars[key] = value
+		}
+
+		// Each copy gets its own directory so the deployments don't share a state file. The whole repo is copied so the
+		// example's relative module sources still resolve.
+		repoCopy, err := files.CopyTerraformFolderToTemp("../..", "naming-test")
+		require.NoError(t, err, "Failed to copy the repo to a temp folder")
+
+		opts := &terraform.Options{
+			TerraformDir:    filepath.Join(repoCopy, exampleDir),
+			TerraformBinary: "tofu",
+			Vars:            vars,
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": "us-east-1",
+			},
+		}
+		defer terraform.Destroy(t, opts)
+
+		wg.Add(1)
+		go func(i int, opts *terraform.Options) {
+			defer wg.Done()
+			if _, err := terraform.InitAndApplyE(t, opts); err != nil {
+				errs[i] = err
+				return
+			}
+			results[i], errs[i] = terraform.OutputE(t, opts, output)
+		}(i, opts)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		require.NoError(t, err, "Deployment %d (%s) failed; names may have clashed", i+1, names[i])
+		assert.Equal(t, names[i], nameFromOutput(results[i]), "Deployment %d should have created its resource under its own name", i+1)
+	}
+	t.Logf("✅ Parallel deployments created %s %s and %s", constraints.Description, names[0], names[1])
+}
@@ -0,0 +1,125 @@
+package modules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+)
+
+// Output stability contracts of the stateful modules' examples. Terragrunt units read these outputs through dependency
+// blocks, so a Stable output that changes on a tag change would ripple into every unit that consumes it.
+var (
+	postgreSQLOutputContract = helpers.OutputContract{
+		Stable: []string{
+			"endpoint",
+			"address",
+			"port",
+			"allocated_storage",
+			"max_allocated_storage",
+			"db_name",
+			"arn",
+			"replica_endpoints",
+			"kms_key_id",
+			"db_security_group_id",
+			"connection_string",
+			"additional_databases",
+			"iam_auth_enabled",
+			"cloudwatch_log_group_names",
+			"performance_insights_enabled",
+			"master_user_secret_arn",
+		},
+	}
+
+	redisOutputContract = helpers.OutputContract{
+		Stable: []string{
+			"primary_endpoint_address",
+			"reader_endpoint_address",
+			"configuration_endpoint_address",
+			"engine",
+			"persistence_mode",
+			"port",
+			"arn",
+			"tls_enabled",
+			"auth_token_secret_arn",
+			"redis_security_group_id",
+			"parameter_group_name",
+			"redis_url",
+			"celery_broker_url",
+			"cpu_alarm_arn",
+			"memory_alarm_arn",
+			"eviction_alarm_arn",
+		},
+	}
+)
+
+// TestOutputStabilityContract applies each stateful module, changes only its tags, re-applies, and verifies the
+// outputs keep to the module's stability contract
+func TestOutputStabilityContract(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/postgresql",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":            helpers.UniqueResourceName("pg-contract", helpers.RDSIdentifierNaming),
+				"master_username": "testadmin",
+				"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying PostgreSQL... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+
+		assertOutputStability(t, terraformOptions, postgreSQLOutputContract)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name": helpers.UniqueResourceName("redis-contract", helpers.ElastiCacheNaming),
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying Redis... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+
+		assertOutputStability(t, terraformOptions, redisOutputContract)
+	})
+}
+
+// assertOutputStability records the outputs, re-applies with a changed tag, and checks the outputs against contract
+func assertOutputStability(t *testing.T, deployed *terraform.Options, contract helpers.OutputContract) {
+	before := helpers.CaptureOutputs(t, deployed)
+
+	retagged := withVars(deployed, map[string]interface{}{
+		"tags": map[string]string{"CostCenter": "output-contract"},
+	})
+	t.Log("Re-applying with a changed tag...")
+	terraform.Apply(t, retagged)
+
+	after := helpers.CaptureOutputs(t, retagged)
+
+	helpers.AssertOutputContract(t, before, after, contract)
+}
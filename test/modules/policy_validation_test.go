@@ -0,0 +1,95 @@
+package modules_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModulePoliciesValid deploys each example that generates IAM or resource policies and runs every policy document in
+// its state through IAM Access Analyzer policy validation, failing on ERROR and SECURITY_WARNING findings. Policies are
+// read from state rather than the plan because most of them embed ARNs that aren't known until apply.
+func TestModulePoliciesValid(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	examples := []struct {
+		name string
+		dir  string
+		vars map[string]interface{}
+	}{
+		{
+			name: "ECSFargateService",
+			dir:  "ecs-fargate-service",
+			vars: map[string]interface{}{
+				"name":            fmt.Sprintf("ecs-policy-%s", strings.ToLower(random.UniqueId())),
+				"desired_count":   1,
+				"enable_firelens": true,
+			},
+		},
+		{
+			name: "S3Bucket",
+			dir:  "s3-bucket",
+			vars: map[string]interface{}{
+				"name": fmt.Sprintf("s3-policy-%s", strings.ToLower(random.UniqueId())),
+			},
+		},
+		{
+			name: "S3CdnBucket",
+			dir:  "s3-cdn-bucket",
+			vars: map[string]interface{}{
+				"name":                      fmt.Sprintf("cdn-policy-%s", strings.ToLower(random.UniqueId())),
+				"enable_event_notification": true,
+			},
+		},
+	}
+
+	for _, example := range examples {
+		example := example
+
+		t.Run(example.name, func(t *testing.T) {
+			t.Parallel()
+
+			terraformOptions := &terraform.Options{
+				TerraformDir:    fmt.Sprintf("../../examples/tofu/%s", example.dir),
+				TerraformBinary: "tofu",
+				Vars:            example.vars,
+				EnvVars: map[string]string{
+					"AWS_DEFAULT_REGION": awsRegion,
+				},
+			}
+
+			defer terraform.Destroy(t, terraformOptions)
+
+			terraform.InitAndApply(t, terraformOptions)
+
+			policies := helpers.ExtractStatePolicies(t, helpers.PullState(t, terraformOptions))
+			require.NotEmpty(t, policies, "%s should generate at least one policy", example.dir)
+
+			sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+			for _, policy := range policies {
+				for _, finding := range helpers.AnalyzePolicy(t, sess, policy.Document) {
+					findingType := aws.StringValue(finding.FindingType)
+					message := fmt.Sprintf("%s: %s %s: %s (%s)", policy.Address, findingType, aws.StringValue(finding.IssueCode),
+						aws.StringValue(finding.FindingDetails), aws.StringValue(finding.LearnMoreLink))
+
+					if findingType == accessanalyzer.ValidatePolicyFindingTypeError || findingType == accessanalyzer.ValidatePolicyFindingTypeSecurityWarning {
+						assert.Fail(t, message)
+					} else {
+						t.Log(message)
+					}
+				}
+				t.Logf("✅ Validated %s", policy.Address)
+			}
+		})
+	}
+}
@@ -1,17 +1,29 @@
 package modules_test
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/go-redis/redis/v8"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,16 +37,30 @@ func TestRedisModule(t *testing.T) {
 	name := fmt.Sprintf("redis-test-%s", uniqueID)
 	awsRegion := "us-east-1"
 
+	// The module reads the AUTH token from Secrets Manager; store it as JSON, the way a rotation Lambda would
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	authToken := fmt.Sprintf("Token%s%s%s", random.UniqueId(), random.UniqueId(), random.UniqueId())
+	secretARN := helpers.CreateSecret(t, sess, fmt.Sprintf("%s-auth-token", name), fmt.Sprintf(`{"auth_token": %q}`, authToken))
+	defer helpers.DeleteSecret(t, sess, secretARN)
+
 	terraformOptions := &terraform.Options{
 		TerraformDir:    "../../examples/tofu/redis",
 		TerraformBinary: "tofu",
 		Vars: map[string]interface{}{
-			"name":               name,
-			"node_type":          "cache.t3.micro", // Use small instance for testing
-			"num_cache_nodes":    1,                // Single node for testing
-			"automatic_failover": false,            // Disable for single node
-			"multi_az":           false,            // Single AZ for cost savings
-			"auth_token_enabled": false,            // Simplify connectivity testing
+			"name":                       name,
+			"node_type":                  "cache.t3.micro", // Use small instance for testing
+			"num_cache_nodes":            1,                // Single node for testing
+			"automatic_failover":         false,            // Disable for single node
+			"multi_az":                   false,            // Single AZ for cost savings
+			"auth_token_enabled":         true,
+			"auth_token_secret_arn":      secretARN,
+			"transit_encryption_enabled": true, // ElastiCache only allows AUTH over TLS
+			"enable_alarms":              true,
+			"snapshot_retention_limit":   1,
+			"snapshot_window":            "04:00-05:00",
+			"parameter_group_overrides": map[string]string{
+				"maxmemory-policy": "allkeys-lru",
+			},
 		},
 		EnvVars: map[string]string{
 			"AWS_DEFAULT_REGION": awsRegion,
@@ -70,7 +96,20 @@ func TestRedisModule(t *testing.T) {
 	})
 
 	t.Run("SecurityGroup", func(t *testing.T) {
-		testRedisSecurityGroup(t, terraformOptions)
+		testRedisSecurityGroup(t, terraformOptions, awsRegion)
+	})
+
+	t.Run("AuthConnectivity", func(t *testing.T) {
+		testRedisAuthConnectivity(t, terraformOptions, sess, secretARN)
+	})
+
+	t.Run("RedisBackupConfiguration", func(t *testing.T) {
+		testRedisBackupConfiguration(t, sess, name, 1, "04:00-05:00")
+	})
+
+	// Runs last, since it re-applies with the alarms disabled
+	t.Run("RedisAlarms", func(t *testing.T) {
+		testRedisAlarms(t, terraformOptions, sess)
 	})
 }
 
@@ -102,6 +141,1227 @@ func TestRedisModuleMinimal(t *testing.T) {
 	// For full Plan testing, use the non-Minimal tests which deploy real infrastructure
 }
 
+// TestElastiCacheValkey tests that the module deploys the Valkey engine and that the go-redis client works against it unchanged
+func TestElastiCacheValkey(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("valkey-test-%s", uniqueID)
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"engine":             "valkey",
+			"node_type":          "cache.t3.micro",
+			"num_cache_nodes":    1,
+			"automatic_failover": false,
+			"multi_az":           false,
+			"auth_token_enabled": false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Valkey ElastiCache cluster... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	t.Run("EngineOutput", func(t *testing.T) {
+		assert.Equal(t, "valkey", terraform.Output(t, terraformOptions, "engine"))
+	})
+
+	t.Run("ClusterStatus", func(t *testing.T) {
+		testRedisClusterStatus(t, terraformOptions, awsRegion, name)
+	})
+
+	t.Run("Engine", func(t *testing.T) {
+		testElastiCacheEngine(t, awsRegion, name, "valkey")
+	})
+
+	t.Run("RedisConnectivity", func(t *testing.T) {
+		testRedisConnectivity(t, terraformOptions)
+	})
+
+	t.Run("RedisOperations", func(t *testing.T) {
+		testRedisOperations(t, terraformOptions)
+	})
+}
+
+// TestRedisVersionUpgrade tests that upgrading the engine version updates the replication group in place
+func TestRedisVersionUpgrade(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("redis-upgrade-%s", uniqueID)
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"engine_version":     "7.0",
+			"node_type":          "cache.t3.micro",
+			"num_cache_nodes":    1,
+			"automatic_failover": false,
+			"multi_az":           false,
+			"auth_token_enabled": false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Redis 7.0 ElastiCache cluster... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+	before := helpers.CaptureOutputs(t, terraformOptions)
+
+	terraformOptions.Vars["engine_version"] = "7.1"
+
+	// The ARN and endpoint are derived from the replication group ID, so they would survive a replacement too; only
+	// the plan shows whether the group is destroyed
+	terraformOptions.PlanFilePath = filepath.Join(t.TempDir(), "upgrade.tfplan")
+	plan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+	helpers.AssertNoResourcesReplaced(t, plan, "module.redis.aws_elasticache_replication_group.")
+
+	// Apply the plan that was checked rather than planning again
+	t.Log("Upgrading to Redis 7.1... (this may take 10-20 minutes)")
+	terraform.Apply(t, terraformOptions)
+	terraformOptions.PlanFilePath = ""
+	after := helpers.CaptureOutputs(t, terraformOptions)
+
+	diffs := helpers.DiffOutputs(before, after)
+	for _, diff := range diffs {
+		t.Logf("Output '%s' changed: %q -> %q", diff.Name, diff.Before, diff.After)
+	}
+
+	// Clients keep connecting to the same endpoint and security group
+	helpers.AssertOutputsUnchanged(t, diffs, "redis_security_group_id", "primary_endpoint_address")
+
+	t.Run("RedisOperations", func(t *testing.T) {
+		testRedisOperations(t, terraformOptions)
+	})
+}
+
+// TestRedisApplyImmediately verifies the deferred-versus-immediate semantics of apply_immediately using a node type
+// change, which restarts nodes. With apply_immediately = false the apply returns without touching the nodes and the
+// change waits in PendingModifiedValues for the maintenance window; with true the group starts modifying straight away.
+func TestRedisApplyImmediately(t *testing.T) {
+	t.Parallel()
+
+	name := helpers.UniqueResourceName("redis-apply", helpers.ElastiCacheNaming)
+	awsRegion := "us-east-1"
+	originalNodeType := "cache.t3.micro"
+	newNodeType := "cache.t3.small"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"node_type":          originalNodeType,
+			"num_cache_nodes":    1,
+			"automatic_failover": false,
+			"multi_az":           false,
+			"apply_immediately":  false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying single-node Redis ElastiCache cluster... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	ecClient := elasticache.New(sess)
+	memberID := getPrimaryCacheClusterID(t, ecClient, name)
+
+	t.Run("DeferredToMaintenanceWindow", func(t *testing.T) {
+		terraformOptions.Vars["node_type"] = newNodeType
+		terraform.Apply(t, terraformOptions)
+
+		group := describeReplicationGroup(t, ecClient, name)
+		assert.Equal(t, "available", aws.StringValue(group.Status), "A deferred change should not start modifying the group")
+		assert.Equal(t, originalNodeType, aws.StringValue(group.CacheNodeType), "The node type should not change before the maintenance window")
+
+		member := describeCacheCluster(t, ecClient, memberID)
+		require.NotNil(t, member.PendingModifiedValues, "The change should be pending")
+		assert.Equal(t, newNodeType, aws.StringValue(member.PendingModifiedValues.CacheNodeType),
+			"The node type change should wait in PendingModifiedValues")
+		t.Logf("✅ Node type change to %s pending until the maintenance window", newNodeType)
+	})
+
+	t.Run("AppliedImmediately", func(t *testing.T) {
+		terraformOptions.Vars["apply_immediately"] = true
+
+		// The apply waits for the modification to finish, so watch the group's status while it runs
+		applyErr := make(chan error, 1)
+		go func() {
+			_, err := terraform.ApplyE(t, terraformOptions)
+			applyErr <- err
+		}()
+
+		sawModifying := false
+		deadline := time.Now().Add(5 * time.Minute)
+		for !sawModifying && time.Now().Before(deadline) {
+			select {
+			case err := <-applyErr:
+				require.NoError(t, err, "Apply failed")
+				require.Fail(t, "The apply finished without the group ever being seen modifying")
+			case <-time.After(5 * time.Second):
+			}
+			result, err := ecClient.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+				ReplicationGroupId: aws.String(name),
+			})
+			sawModifying = err == nil && len(result.ReplicationGroups) == 1 &&
+				aws.StringValue(result.ReplicationGroups[0].Status) == "modifying"
+		}
+
+		require.NoError(t, <-applyErr, "Apply failed")
+		require.True(t, sawModifying, "The group should start modifying as soon as the change is applied")
+
+		group := describeReplicationGroup(t, ecClient, name)
+		assert.Equal(t, newNodeType, aws.StringValue(group.CacheNodeType), "The node type should have changed")
+		if pending := describeCacheCluster(t, ecClient, memberID).PendingModifiedValues; pending != nil {
+			assert.Nil(t, pending.CacheNodeType, "No node type change should be left pending")
+		}
+		t.Logf("✅ Node type changed to %s without waiting for the maintenance window", newNodeType)
+	})
+}
+
+// describeReplicationGroup returns the replication group
+func describeReplicationGroup(t *testing.T, ecClient *elasticache.ElastiCache, replicationGroupID string) *elasticache.ReplicationGroup {
+	result, err := ecClient.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroupID),
+	})
+	require.NoError(t, err, "Failed to describe replication group %s", replicationGroupID)
+	require.Len(t, result.ReplicationGroups, 1)
+
+	return result.ReplicationGroups[0]
+}
+
+// describeCacheCluster returns a member cache cluster. Node-level changes such as the node type are pending here rather
+// than on the replication group.
+func describeCacheCluster(t *testing.T, ecClient *elasticache.ElastiCache, clusterID string) *elasticache.CacheCluster {
+	result, err := ecClient.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+		CacheClusterId: aws.String(clusterID),
+	})
+	require.NoError(t, err, "Failed to describe cache cluster %s", clusterID)
+	require.Len(t, result.CacheClusters, 1)
+
+	return result.CacheClusters[0]
+}
+
+// TestRedisPersistenceMode tests the durability of each persistence mode when the primary node goes away. In
+// replicated (AOF-equivalent) mode a write survives a forced failover; in snapshot-only mode a node reboot loses it.
+func TestRedisPersistenceMode(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	t.Run("Replicated", func(t *testing.T) {
+		t.Parallel()
+
+		name := fmt.Sprintf("redis-aof-%s", random.UniqueId())
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":               name,
+				"node_type":          "cache.t3.micro",
+				"num_cache_nodes":    2,
+				"automatic_failover": true,
+				"multi_az":           true,
+				"appendonly":         true,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying replicated Redis cluster... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+		assert.Equal(t, "replicated", terraform.Output(t, terraformOptions, "persistence_mode"))
+
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		ecClient := elasticache.New(sess)
+
+		rdb := newRedisClientFromOutputs(t, terraformOptions)
+		defer rdb.Close()
+
+		ctx := context.Background()
+
+		t.Run("RedisReaderEndpoint", func(t *testing.T) {
+			reader := terraform.Output(t, terraformOptions, "reader_endpoint_address")
+			require.NotEmpty(t, reader, "A cluster with a replica should have a reader endpoint")
+			require.NotEqual(t, terraform.Output(t, terraformOptions, "primary_endpoint_address"), reader)
+
+			key, value := "test:reader", random.UniqueId()
+			require.NoError(t, rdb.Set(ctx, key, value, 0).Err(), "Failed to SET on the primary")
+
+			readerAddr := fmt.Sprintf("%s:%s", reader, terraform.Output(t, terraformOptions, "port"))
+			helpers.WaitForRedisReplicaValue(t, readerAddr, key, value, helpers.FastRetryConfig("read from reader endpoint"))
+			t.Logf("✅ Write to the primary was readable through reader endpoint %s", reader)
+		})
+
+		key, value := "test:durability", "survives-failover"
+		require.NoError(t, rdb.Set(ctx, key, value, 0).Err(), "Failed to SET durability key")
+
+		// Block until the replica acknowledges the write, so the failover can't race replication
+		acked, err := rdb.Do(ctx, "WAIT", 1, 5000).Int()
+		require.NoError(t, err, "WAIT failed")
+		require.Equal(t, 1, acked, "Replica should acknowledge the write")
+
+		oldPrimary := getPrimaryCacheClusterID(t, ecClient, name)
+		_, err = ecClient.TestFailover(&elasticache.TestFailoverInput{
+			ReplicationGroupId: aws.String(name),
+			NodeGroupId:        aws.String("0001"),
+		})
+		require.NoError(t, err, "Failed to trigger failover")
+		t.Logf("Triggered failover away from %s", oldPrimary)
+
+		helpers.WaitForCondition(t, helpers.SlowRetryConfig("primary promotion"), func() bool {
+			return getPrimaryCacheClusterID(t, ecClient, name) != oldPrimary
+		}, "Replica was not promoted after failover")
+		helpers.WaitForElastiCacheAvailable(t, sess, name, 15*time.Minute)
+
+		var got string
+		helpers.RetryUntilNoError(t, helpers.MediumRetryConfig("read after failover"), func() error {
+			got, err = rdb.Get(ctx, key).Result()
+			return err
+		})
+		assert.Equal(t, value, got, "Write acknowledged by the replica should survive failover")
+		t.Log("✅ Write survived primary failover in replicated mode")
+	})
+
+	t.Run("SnapshotOnly", func(t *testing.T) {
+		t.Parallel()
+
+		name := fmt.Sprintf("redis-snap-%s", random.UniqueId())
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":               name,
+				"node_type":          "cache.t3.micro",
+				"num_cache_nodes":    1,
+				"automatic_failover": false,
+				"multi_az":           false,
+				"appendonly":         false,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying snapshot-only Redis cluster... (this may take 5-10 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+		assert.Equal(t, "snapshot", terraform.Output(t, terraformOptions, "persistence_mode"))
+		assert.Empty(t, terraform.Output(t, terraformOptions, "reader_endpoint_address"), "A single node has no replicas to read from")
+
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		ecClient := elasticache.New(sess)
+
+		rdb := newRedisClientFromOutputs(t, terraformOptions)
+		defer rdb.Close()
+
+		ctx := context.Background()
+		key := "test:durability"
+		require.NoError(t, rdb.Set(ctx, key, "lost-on-reboot", 0).Err(), "Failed to SET durability key")
+
+		primary := getPrimaryCacheClusterID(t, ecClient, name)
+		_, err := ecClient.RebootCacheCluster(&elasticache.RebootCacheClusterInput{
+			CacheClusterId:       aws.String(primary),
+			CacheNodeIdsToReboot: []*string{aws.String("0001")},
+		})
+		require.NoError(t, err, "Failed to reboot node")
+		t.Logf("Rebooting %s", primary)
+
+		waitForCacheClusterStatus(t, ecClient, primary, "rebooting cache cluster nodes")
+		waitForCacheClusterStatus(t, ecClient, primary, "available")
+
+		var getErr error
+		helpers.RetryUntilSuccess(t, helpers.MediumRetryConfig("read after reboot"), func() (bool, error) {
+			_, getErr = rdb.Get(ctx, key).Result()
+			return getErr == nil || getErr == redis.Nil, nil
+		})
+
+		// Without AOF, a rebooted node comes back empty; only the last snapshot could restore data
+		assert.Equal(t, redis.Nil, getErr, "Snapshot-only mode is expected to lose writes on node reboot")
+		t.Log("✅ Write was lost on reboot in snapshot-only mode, as documented")
+	})
+}
+
+// TestRedisNodeCountSemantics pins down what num_cache_nodes means: the total number of nodes in the replication group,
+// counting the primary. It also checks that automatic failover with a single node is rejected at plan time rather than
+// failing partway through the deploy.
+func TestRedisNodeCountSemantics(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	t.Run("FailoverNeedsReplica", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":               helpers.UniqueResourceName("redis-failover", helpers.ElastiCacheNaming),
+				"num_cache_nodes":    1,
+				"automatic_failover": true,
+				"multi_az":           false,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		_, err := terraform.InitAndPlanE(t, terraformOptions)
+		require.Error(t, err, "Plan should fail when automatic failover has no replica to fail over to")
+		assert.Contains(t, err.Error(), "automatic_failover_enabled = true requires num_cache_clusters >= 2")
+		t.Log("✅ Automatic failover with a single node rejected at plan time")
+	})
+
+	t.Run("PrimaryPlusReplicas", func(t *testing.T) {
+		t.Parallel()
+
+		replicas := 2
+		name := helpers.UniqueResourceName("redis-nodes", helpers.ElastiCacheNaming)
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":               name,
+				"node_type":          "cache.t3.micro",
+				"num_cache_nodes":    1 + replicas,
+				"automatic_failover": true,
+				"multi_az":           false,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Logf("Deploying Redis with 1 primary and %d replicas... (this may take 10-15 minutes)", replicas)
+		terraform.InitAndApply(t, terraformOptions)
+
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		result, err := elasticache.New(sess).DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(name),
+		})
+		require.NoError(t, err)
+		require.Len(t, result.ReplicationGroups, 1)
+		group := result.ReplicationGroups[0]
+
+		assert.Len(t, group.MemberClusters, 1+replicas, "num_cache_nodes should be the total node count, primary included")
+
+		roles := map[string]int{}
+		for _, nodeGroup := range group.NodeGroups {
+			for _, member := range nodeGroup.NodeGroupMembers {
+				roles[aws.StringValue(member.CurrentRole)]++
+			}
+		}
+		assert.Equal(t, 1, roles["primary"], "There should be exactly one primary")
+		assert.Equal(t, replicas, roles["replica"], "The remaining nodes should be replicas")
+
+		t.Logf("✅ num_cache_nodes = %d created %d member clusters: %v", 1+replicas, len(group.MemberClusters), roles)
+	})
+}
+
+// TestRedisScalingConnectionStability adds a replica to a running replication group while a client keeps reading and
+// writing through the primary endpoint, and checks the client sees no errors and the new replica serves reads once the
+// group is available again. Adding replicas is the scaling operation we expect to run online, so it must not disrupt
+// live traffic.
+func TestRedisScalingConnectionStability(t *testing.T) {
+	t.Parallel()
+
+	name := helpers.UniqueResourceName("redis-scale", helpers.ElastiCacheNaming)
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":               name,
+			"node_type":          "cache.t3.micro",
+			"num_cache_nodes":    1,
+			"automatic_failover": false,
+			"multi_az":           false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying single-node Redis ElastiCache cluster... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	rdb := newRedisClientFromOutputs(t, terraformOptions)
+	defer rdb.Close()
+
+	ctx := context.Background()
+	require.NoError(t, rdb.Ping(ctx).Err(), "Failed to connect to Redis before scaling")
+
+	load := startRedisLoadLoop(rdb, "scaling:counter", 100*time.Millisecond)
+
+	// Scale outside of tofu so the client is running the whole time the group is modifying; a tofu apply would make
+	// the same IncreaseReplicaCount call
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	_, err := elasticache.New(sess).IncreaseReplicaCount(&elasticache.IncreaseReplicaCountInput{
+		ReplicationGroupId: aws.String(name),
+		NewReplicaCount:    aws.Int64(1),
+		ApplyImmediately:   aws.Bool(true),
+	})
+	if err != nil {
+		load.stop()
+		require.NoError(t, err, "Failed to add a replica to %s", name)
+	}
+
+	t.Log("Adding a replica under load... (this may take 10-15 minutes)")
+	group := helpers.WaitForReplicationGroupModification(t, sess, name, helpers.RetryConfig{
+		MaxRetries:    120,
+		RetryInterval: 10 * time.Second,
+		Description:   "replica added",
+	})
+	result := load.stop()
+
+	// Keep the configuration in line with the group for the destroy
+	terraformOptions.Vars["num_cache_nodes"] = 2
+
+	t.Run("NoErrorsOnPrimary", func(t *testing.T) {
+		require.Positive(t, result.succeeded, "No operations succeeded while scaling")
+		assert.Zero(t, result.failed, "Operations on the primary failed while scaling, first error: %v", result.firstErr)
+		t.Logf("✅ %d operations on the primary succeeded while adding a replica, none failed", result.succeeded)
+	})
+
+	t.Run("NewReplicaServesReads", func(t *testing.T) {
+		require.Len(t, group.NodeGroups, 1, "Replication group should have a single node group")
+
+		var replicaAddrs []string
+		for _, member := range group.NodeGroups[0].NodeGroupMembers {
+			if aws.StringValue(member.CurrentRole) == "replica" && member.ReadEndpoint != nil {
+				replicaAddrs = append(replicaAddrs, fmt.Sprintf("%s:%d",
+					aws.StringValue(member.ReadEndpoint.Address), aws.Int64Value(member.ReadEndpoint.Port)))
+			}
+		}
+		require.Len(t, replicaAddrs, 1, "The group should have gained exactly one replica")
+
+		// The replica is synced from the primary when it joins, so it should have the last value the load wrote
+		want := rdb.Get(ctx, "scaling:counter").Val()
+		helpers.WaitForRedisReplicaValue(t, replicaAddrs[0], "scaling:counter", want, helpers.FastRetryConfig("replica read"))
+		t.Logf("✅ New replica %s serves reads", replicaAddrs[0])
+	})
+}
+
+// redisLoadLoopResult summarizes the operations made by a redisLoadLoop
+type redisLoadLoopResult struct {
+	succeeded int
+	failed    int
+	firstErr  error
+}
+
+// redisLoadLoop increments and reads back a counter at a fixed interval in the background
+type redisLoadLoop struct {
+	done   chan struct{}
+	result chan redisLoadLoopResult
+}
+
+// startRedisLoadLoop starts the load in the background. It reuses the client's pooled connections, as an application
+// would, so a node change that drops existing connections shows up as failures.
+func startRedisLoadLoop(rdb *redis.Client, key string, interval time.Duration) *redisLoadLoop {
+	loop := &redisLoadLoop{
+		done:   make(chan struct{}),
+		result: make(chan redisLoadLoopResult, 1),
+	}
+
+	go func() {
+		var result redisLoadLoopResult
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-loop.done:
+				loop.result <- result
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := rdb.Incr(ctx, key).Err()
+			if err == nil {
+				err = rdb.Get(ctx, key).Err()
+			}
+			cancel()
+
+			if err != nil {
+				if result.firstErr == nil {
+					result.firstErr = err
+				}
+				result.failed++
+				continue
+			}
+			result.succeeded++
+		}
+	}()
+
+	return loop
+}
+
+// stop ends the load loop and returns its results
+func (l *redisLoadLoop) stop() redisLoadLoopResult {
+	close(l.done)
+	return <-l.result
+}
+
+// TestRedisAutomaticBackups checks that snapshot_retention_limit turns backups on, not just that it is configured. The
+// daily snapshot window is too far away to wait for, so the test checks the retention and snapshotting node ElastiCache
+// will back up from, then takes a snapshot of that node to prove the snapshot pipeline works end to end.
+func TestRedisAutomaticBackups(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	retentionDays := 3
+	name := helpers.UniqueResourceName("redis-backup", helpers.ElastiCacheNaming)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                     name,
+			"snapshot_retention_limit": retentionDays,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Redis with automatic backups enabled... (this may take 10-15 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("RedisBackupConfiguration", func(t *testing.T) {
+		testRedisBackupConfiguration(t, sess, name, retentionDays, "03:00-04:00")
+	})
+}
+
+// redisNodeTypesWithoutSnapshots are node types ElastiCache can't snapshot. The ElastiCache backup constraints list
+// cache.t1.micro as the only Redis/Valkey node type without backup and restore, so the cache.t3.micro nodes the tests
+// use take snapshots like any other.
+var redisNodeTypesWithoutSnapshots = map[string]bool{
+	"cache.t1.micro": true,
+}
+
+// testRedisBackupConfiguration checks the replication group keeps automatic snapshots for retentionDays and takes them
+// during window, then, on node types that support it, snapshots the node automatic backups use to prove the snapshot
+// pipeline works end to end
+func testRedisBackupConfiguration(t *testing.T, sess *session.Session, replicationGroupID string, retentionDays int, window string) {
+	ecClient := elasticache.New(sess)
+
+	result, err := ecClient.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroupID),
+	})
+	require.NoError(t, err, "Failed to describe replication group")
+	require.Len(t, result.ReplicationGroups, 1)
+	group := result.ReplicationGroups[0]
+
+	assert.Equal(t, int64(retentionDays), aws.Int64Value(group.SnapshotRetentionLimit))
+	assert.Equal(t, window, aws.StringValue(group.SnapshotWindow))
+	t.Logf("✅ Automatic snapshots kept for %d days, taken during %s UTC",
+		aws.Int64Value(group.SnapshotRetentionLimit), aws.StringValue(group.SnapshotWindow))
+
+	t.Run("SnapshotCompletes", func(t *testing.T) {
+		nodeType := aws.StringValue(group.CacheNodeType)
+		if redisNodeTypesWithoutSnapshots[nodeType] {
+			t.Skipf("ElastiCache can't snapshot %s nodes", nodeType)
+		}
+
+		// ElastiCache only sets a snapshotting cluster while backups are enabled; it's the node automatic backups use
+		snapshottingClusterID := aws.StringValue(group.SnapshottingClusterId)
+		require.NotEmpty(t, snapshottingClusterID, "Replication group has no snapshotting cluster, so automatic backups won't run")
+
+		snapshotName := fmt.Sprintf("%s-test", replicationGroupID)
+		_, err := ecClient.CreateSnapshot(&elasticache.CreateSnapshotInput{
+			CacheClusterId: aws.String(snapshottingClusterID),
+			SnapshotName:   aws.String(snapshotName),
+		})
+		require.NoError(t, err, "Failed to create snapshot of %s", snapshottingClusterID)
+
+		// Manual snapshots outlive the replication group, so remove it explicitly
+		defer func() {
+			_, err := ecClient.DeleteSnapshot(&elasticache.DeleteSnapshotInput{SnapshotName: aws.String(snapshotName)})
+			assert.NoError(t, err, "Failed to delete snapshot %s", snapshotName)
+		}()
+
+		snapshot := helpers.WaitForRedisSnapshot(t, sess, replicationGroupID, helpers.SlowRetryConfig("snapshot available"))
+		assert.Equal(t, snapshotName, aws.StringValue(snapshot.SnapshotName))
+		assert.Equal(t, replicationGroupID, aws.StringValue(snapshot.ReplicationGroupId), "Snapshot should belong to the replication group")
+		t.Logf("✅ Snapshot %s of %s completed", snapshotName, snapshottingClusterID)
+
+		// The cluster should be usable again once the snapshot is done
+		helpers.WaitForElastiCacheAvailable(t, sess, replicationGroupID, 10*time.Minute)
+	})
+}
+
+const (
+	// redisMaxClientsOverflowEnvVar overrides how many connections TestRedisMaxClients attempts past maxclients
+	redisMaxClientsOverflowEnvVar = "REDIS_MAXCLIENTS_OVERFLOW"
+
+	defaultRedisMaxClientsOverflow = 50
+
+	// redisDialConcurrency bounds how many connections TestRedisMaxClients opens at once while filling the cluster
+	redisDialConcurrency = 64
+
+	// redisMaxClientsError is the reply Redis sends a client it accepts past maxclients, just before closing it
+	redisMaxClientsError = "-ERR max number of clients reached"
+)
+
+// TestRedisMaxClients fills the cluster up to its maxclients limit, checks connections past the limit are rejected
+// with the expected error while connected clients keep working, then checks new clients are accepted again once the
+// others disconnect. ElastiCache doesn't allow maxclients to be modified, so the test reads it from the parameter group;
+// the runner needs enough file descriptors and ephemeral ports to reach it. Set REDIS_MAXCLIENTS_OVERFLOW to change
+// how many connections are attempted past the limit.
+func TestRedisMaxClients(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	overflow := defaultRedisMaxClientsOverflow
+	if value := os.Getenv(redisMaxClientsOverflowEnvVar); value != "" {
+		var err error
+		overflow, err = strconv.Atoi(value)
+		require.NoError(t, err, "Invalid %s", redisMaxClientsOverflowEnvVar)
+		require.Positive(t, overflow, "%s must be positive", redisMaxClientsOverflowEnvVar)
+	}
+
+	// The module's parameter group can't override maxclients, so the family default tells us up front whether this
+	// runner can reach the limit, before paying for a deploy
+	defaultMaxClients, err := strconv.Atoi(helpers.GetElastiCacheParameter(t, sess, "default.redis7", "maxclients"))
+	require.NoError(t, err, "maxclients in default.redis7 is not a number")
+	requireConnectionCapacity(t, defaultMaxClients+overflow)
+
+	name := helpers.UniqueResourceName("redis-maxcli", helpers.ElastiCacheNaming)
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name": name,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Redis cluster... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	parameterGroup := terraform.Output(t, terraformOptions, "parameter_group_name")
+	maxClients, err := strconv.Atoi(helpers.GetElastiCacheParameter(t, sess, parameterGroup, "maxclients"))
+	require.NoError(t, err, "maxclients in %s is not a number", parameterGroup)
+	require.Equal(t, defaultMaxClients, maxClients, "Parameter group %s should inherit maxclients from default.redis7", parameterGroup)
+	t.Logf("Parameter group %s sets maxclients to %d", parameterGroup, maxClients)
+
+	addr := fmt.Sprintf("%s:%s",
+		terraform.Output(t, terraformOptions, "primary_endpoint_address"),
+		terraform.Output(t, terraformOptions, "port"))
+
+	// The control client connects first, so it holds one of the slots the rest of the test fills
+	rdb := newRedisClientFromOutputs(t, terraformOptions)
+	defer rdb.Close()
+
+	ctx := context.Background()
+	connected := redisConnectedClients(t, ctx, rdb)
+
+	conns := fillRedisConnections(t, addr, maxClients-connected)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	t.Run("RejectsPastLimit", func(t *testing.T) {
+		for i := 0; i < overflow; i++ {
+			reply, err := readRedisGreeting(addr)
+			require.NoError(t, err, "Connection %d past maxclients failed before the server replied", i+1)
+			require.Equal(t, redisMaxClientsError, reply, "Connection %d past maxclients was not rejected", i+1)
+		}
+		t.Logf("✅ %d connections past maxclients were rejected with %q", overflow, redisMaxClientsError)
+	})
+
+	t.Run("StaysHealthy", func(t *testing.T) {
+		require.NoError(t, rdb.Ping(ctx).Err(), "Connected client should keep working at maxclients")
+		assert.LessOrEqual(t, redisConnectedClients(t, ctx, rdb), maxClients, "Redis accepted more clients than maxclients")
+		helpers.WaitForElastiCacheAvailable(t, sess, name, time.Minute)
+		t.Log("✅ Cluster stayed available with every client slot in use")
+	})
+
+	t.Run("RecoversAfterDisconnect", func(t *testing.T) {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		conns = nil
+
+		helpers.RetryUntilNoError(t, helpers.FastRetryConfig("new client after disconnect"), func() error {
+			conn, reply, err := dialRedisAndPing(addr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if reply != "+PONG" {
+				return fmt.Errorf("PING returned %q", reply)
+			}
+			return nil
+		})
+		t.Log("✅ New clients were accepted again once the others disconnected")
+	})
+}
+
+// requireConnectionCapacity skips the test unless the runner can hold count open connections to a single endpoint. It
+// raises the soft open file limit as far as the hard limit allows.
+func requireConnectionCapacity(t *testing.T, count int) {
+	t.Helper()
+
+	var limit syscall.Rlimit
+	require.NoError(t, syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit), "Failed to read the open file limit")
+	if limit.Cur < limit.Max {
+		limit.Cur = limit.Max
+		require.NoError(t, syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit), "Failed to raise the open file limit")
+	}
+	// Leave room for the files the test binary and its AWS and tofu calls already hold
+	if limit.Cur < uint64(count)+1024 {
+		t.Skipf("Open file limit %d is too low to hold %d connections; raise it with ulimit -n", limit.Cur, count)
+	}
+
+	// Every connection to the same endpoint needs its own local port
+	if portRange, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range"); err == nil {
+		var low, high int
+		if _, err := fmt.Sscan(string(portRange), &low, &high); err == nil && high-low+1 < count {
+			t.Skipf("Ephemeral port range %d-%d is too small to hold %d connections; widen net.ipv4.ip_local_port_range", low, high, count)
+		}
+	}
+}
+
+// redisConnectedClients returns connected_clients from INFO clients
+func redisConnectedClients(t *testing.T, ctx context.Context, rdb *redis.Client) int {
+	t.Helper()
+
+	return int(redisInfoInt(t, ctx, rdb, "clients", "connected_clients"))
+}
+
+// redisInfoInt returns a numeric field from a section of INFO, e.g. evicted_keys from stats
+func redisInfoInt(t *testing.T, ctx context.Context, rdb *redis.Client, section, field string) int64 {
+	t.Helper()
+
+	info, err := rdb.Info(ctx, section).Result()
+	require.NoError(t, err, "INFO %s failed", section)
+
+	for _, line := range strings.Split(info, "\r\n") {
+		if value, found := strings.CutPrefix(line, field+":"); found {
+			number, err := strconv.ParseInt(value, 10, 64)
+			require.NoError(t, err, "%s is not a number: %q", field, value)
+			return number
+		}
+	}
+
+	require.Fail(t, fmt.Sprintf("INFO %s has no %s", section, field))
+	return 0
+}
+
+// fillRedisConnections opens up to count connections and returns the ones Redis accepted. ElastiCache's own monitoring
+// clients come and go, so a few rejections at the very end are expected; any other failure fails the test.
+func fillRedisConnections(t *testing.T, addr string, count int) []net.Conn {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		conns    []net.Conn
+		rejected int
+		failures []error
+	)
+
+	sem := make(chan struct{}, redisDialConcurrency)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			conn, reply, err := dialRedisAndPing(addr)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				failures = append(failures, err)
+			case reply == redisMaxClientsError:
+				conn.Close()
+				rejected++
+			case reply != "+PONG":
+				conn.Close()
+				failures = append(failures, fmt.Errorf("PING returned %q", reply))
+			default:
+				conns = append(conns, conn)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A client rejected past the limit may see its PING reset instead of the error, so allow as many of those as there
+	// were clean rejections
+	require.LessOrEqual(t, len(failures), rejected+1, "Failed to open connections below maxclients: %v", failures)
+	t.Logf("Opened %d connections (%d rejected at the limit)", len(conns), rejected+len(failures))
+
+	return conns
+}
+
+// dialRedisAndPing opens a raw connection and sends PING, returning the connection and the first line of the reply
+func dialRedisAndPing(addr string) (net.Conn, string, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		conn.Close()
+		return nil, "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, "", err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return conn, strings.TrimSpace(reply), nil
+}
+
+// readRedisGreeting connects without sending a command and returns what the server says first. An accepted client
+// gets nothing, so this times out; a client past maxclients is sent the error as soon as it connects. Not writing
+// first means the server closing the connection can't reset it before the error is read.
+func readRedisGreeting(addr string) (string, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(reply), nil
+}
+
+// TestRedisTransitEncryption deploys a cluster that requires TLS but has no AUTH token, which ElastiCache allows, and
+// verifies a client that enables TLS from the tls_enabled output connects without a password while one that leaves TLS
+// off, as clients often do when there's no password to set, can't connect
+func TestRedisTransitEncryption(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	name := helpers.UniqueResourceName("redis-tls", helpers.ElastiCacheNaming)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                       name,
+			"transit_encryption_enabled": true,
+			"auth_token_enabled":         false,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Redis cluster with TLS and no AUTH token... (this may take 5-10 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	require.Equal(t, "true", terraform.Output(t, terraformOptions, "tls_enabled"), "tls_enabled should reflect transit_encryption_enabled")
+
+	t.Run("ClusterConfig", func(t *testing.T) {
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		result, err := elasticache.New(sess).DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(name),
+		})
+		require.NoError(t, err, "Failed to describe replication group")
+		require.Len(t, result.ReplicationGroups, 1)
+
+		group := result.ReplicationGroups[0]
+		assert.True(t, aws.BoolValue(group.TransitEncryptionEnabled), "Transit encryption should be enabled")
+		assert.False(t, aws.BoolValue(group.AuthTokenEnabled), "AUTH token should be disabled")
+		t.Log("✅ Cluster requires TLS and has no AUTH token")
+	})
+
+	t.Run("PingOverTLS", func(t *testing.T) {
+		options := redisOptionsFromOutputs(t, terraformOptions)
+		require.NotNil(t, options.TLSConfig, "Client options should enable TLS when tls_enabled is true")
+		require.Empty(t, options.Password, "No password should be needed without an AUTH token")
+
+		rdb := redis.NewClient(options)
+		defer rdb.Close()
+
+		ctx := context.Background()
+		var pong string
+		helpers.RetryUntilNoError(t, helpers.MediumRetryConfig("PING over TLS"), func() error {
+			var err error
+			pong, err = rdb.Ping(ctx).Result()
+			return err
+		})
+		assert.Equal(t, "PONG", pong)
+		t.Log("✅ PING succeeded over TLS without a password")
+	})
+
+	t.Run("PlaintextRejected", func(t *testing.T) {
+		options := redisOptionsFromOutputs(t, terraformOptions)
+		options.TLSConfig = nil
+		options.MaxRetries = -1
+
+		rdb := redis.NewClient(options)
+		defer rdb.Close()
+
+		err := rdb.Ping(context.Background()).Err()
+		require.Error(t, err, "A client without TLS should not be able to talk to a cluster that requires it")
+		t.Logf("✅ PING without TLS failed as expected: %v", err)
+	})
+}
+
+// TestRedisClusterMode deploys a sharded cluster with two node groups and verifies the outputs switch to the
+// configuration endpoint, and that a cluster-aware client spreads keys across both shards and reads them all back
+func TestRedisClusterMode(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	name := helpers.UniqueResourceName("redis-shard", helpers.ElastiCacheNaming)
+	numShards := 2
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                    name,
+			"cluster_mode_enabled":    true,
+			"num_node_groups":         numShards,
+			"replicas_per_node_group": 1,
+			"automatic_failover":      true,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying sharded Redis cluster... (this may take 15-20 minutes)")
+	terraform.InitAndApply(t, terraformOptions)
+
+	var primaryEndpoint *string
+	terraform.OutputStruct(t, terraformOptions, "primary_endpoint_address", &primaryEndpoint)
+	configurationEndpoint := terraform.Output(t, terraformOptions, "configuration_endpoint_address")
+	port := terraform.Output(t, terraformOptions, "port")
+
+	t.Run("Outputs", func(t *testing.T) {
+		assert.Nil(t, primaryEndpoint, "primary_endpoint_address should be null in cluster mode")
+		require.Contains(t, configurationEndpoint, ".cache.amazonaws.com", "configuration_endpoint_address should be set in cluster mode")
+		assert.Contains(t, terraform.Output(t, terraformOptions, "redis_url"), configurationEndpoint,
+			"redis_url should point at the configuration endpoint")
+		t.Logf("✅ Outputs switched to the configuration endpoint %s", configurationEndpoint)
+	})
+
+	t.Run("NodeGroups", func(t *testing.T) {
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+		result, err := elasticache.New(sess).DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(name),
+		})
+		require.NoError(t, err, "Failed to describe replication group")
+		require.Len(t, result.ReplicationGroups, 1)
+
+		group := result.ReplicationGroups[0]
+		assert.True(t, aws.BoolValue(group.ClusterEnabled), "Cluster mode should be enabled")
+		assert.Len(t, group.NodeGroups, numShards, "Replication group should have %d shards", numShards)
+		t.Logf("✅ Replication group has %d shards", len(group.NodeGroups))
+	})
+
+	t.Run("KeyspaceDistribution", func(t *testing.T) {
+		rdb := redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        []string{fmt.Sprintf("%s:%s", configurationEndpoint, port)},
+			DialTimeout:  10 * time.Second,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		})
+		defer rdb.Close()
+
+		ctx := context.Background()
+		helpers.RetryUntilNoError(t, helpers.MediumRetryConfig("cluster PING"), func() error {
+			return rdb.Ping(ctx).Err()
+		})
+
+		// Keys without hash tags hash to slots all over the keyspace, so enough of them land on every shard
+		keys := 200
+		shardKeys := map[string]int{}
+		for i := 0; i < keys; i++ {
+			key := fmt.Sprintf("test:shard:%d", i)
+			require.NoError(t, rdb.Set(ctx, key, i, 0).Err(), "Failed to SET %s", key)
+
+			master, err := rdb.MasterForKey(ctx, key)
+			require.NoError(t, err, "Failed to find the shard owning %s", key)
+			shardKeys[master.Options().Addr]++
+		}
+
+		for i := 0; i < keys; i++ {
+			key := fmt.Sprintf("test:shard:%d", i)
+			value, err := rdb.Get(ctx, key).Int()
+			require.NoError(t, err, "Failed to GET %s", key)
+			assert.Equal(t, i, value, "Value of %s", key)
+		}
+
+		assert.Len(t, shardKeys, numShards, "Keys should be spread across every shard, got %v", shardKeys)
+		t.Logf("✅ %d keys written and read back across %d shards: %v", keys, len(shardKeys), shardKeys)
+	})
+}
+
+// newRedisClientFromOutputs creates a client for the cluster's primary endpoint
+func newRedisClientFromOutputs(t *testing.T, opts *terraform.Options) *redis.Client {
+	return redis.NewClient(redisOptionsFromOutputs(t, opts))
+}
+
+// redisOptionsFromOutputs returns client options for the cluster's primary endpoint, with TLS when the cluster requires
+// it and the AUTH token from Secrets Manager when it has one. TLS depends only on tls_enabled: a cluster can require
+// TLS without an AUTH token, and a client that leaves TLS off because there's no password can't connect at all.
+func redisOptionsFromOutputs(t *testing.T, opts *terraform.Options) *redis.Options {
+	endpoint := terraform.Output(t, opts, "primary_endpoint_address")
+	port := terraform.Output(t, opts, "port")
+
+	options := &redis.Options{
+		Addr:         fmt.Sprintf("%s:%s", endpoint, port),
+		DialTimeout:  10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	if terraform.Output(t, opts, "tls_enabled") == "true" {
+		options.TLSConfig = &tls.Config{
+			ServerName: endpoint,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	var secretARN *string
+	terraform.OutputStruct(t, opts, "auth_token_secret_arn", &secretARN)
+	if secretARN != nil {
+		sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: opts.EnvVars["AWS_DEFAULT_REGION"]})
+		options.Password = redisAuthTokenFromSecret(helpers.GetSecretValue(t, sess, *secretARN))
+	}
+
+	return options
+}
+
+// redisAuthTokenFromSecret extracts the AUTH token from a secret value the way the module does: the auth_token key of
+// a JSON object, or else the whole value as the raw token
+func redisAuthTokenFromSecret(value string) string {
+	var secret struct {
+		AuthToken *string `json:"auth_token"`
+	}
+	if json.Unmarshal([]byte(value), &secret) == nil && secret.AuthToken != nil {
+		return *secret.AuthToken
+	}
+	return value
+}
+
+// getPrimaryCacheClusterID returns the ID of the member cluster currently acting as primary
+func getPrimaryCacheClusterID(t *testing.T, ecClient *elasticache.ElastiCache, replicationGroupID string) string {
+	result, err := ecClient.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroupID),
+	})
+	require.NoError(t, err, "Failed to describe replication group")
+	require.NotEmpty(t, result.ReplicationGroups, "No replication groups returned")
+	require.NotEmpty(t, result.ReplicationGroups[0].NodeGroups, "Replication group has no node groups")
+
+	for _, member := range result.ReplicationGroups[0].NodeGroups[0].NodeGroupMembers {
+		if aws.StringValue(member.CurrentRole) == "primary" {
+			return aws.StringValue(member.CacheClusterId)
+		}
+	}
+
+	require.Fail(t, "Replication group has no primary member")
+	return ""
+}
+
+// waitForCacheClusterStatus waits until a cache cluster reports the given status
+func waitForCacheClusterStatus(t *testing.T, ecClient *elasticache.ElastiCache, clusterID, status string) {
+	helpers.WaitForCondition(t, helpers.SlowRetryConfig(fmt.Sprintf("cache cluster %s", status)), func() bool {
+		result, err := ecClient.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+			CacheClusterId: aws.String(clusterID),
+		})
+		return err == nil && len(result.CacheClusters) > 0 && aws.StringValue(result.CacheClusters[0].CacheClusterStatus) == status
+	}, "Cache cluster %s did not reach status %s", clusterID, status)
+}
+
+// testElastiCacheEngine verifies the engine running on every member of the replication group. The engine is read from
+// the member cache clusters because ReplicationGroup does not expose it in this SDK version.
+func testElastiCacheEngine(t *testing.T, region, replicationGroupID, expectedEngine string) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	require.NoError(t, err, "Failed to create AWS session")
+
+	ecClient := elasticache.New(sess)
+
+	result, err := ecClient.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroupID),
+	})
+	require.NoError(t, err, "Failed to describe replication group")
+	require.NotEmpty(t, result.ReplicationGroups, "No replication groups returned")
+
+	members := result.ReplicationGroups[0].MemberClusters
+	require.NotEmpty(t, members, "Replication group should have member clusters")
+
+	for _, member := range members {
+		clusters, err := ecClient.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+			CacheClusterId: member,
+		})
+		require.NoError(t, err, "Failed to describe cache cluster %s", *member)
+		require.NotEmpty(t, clusters.CacheClusters, "No cache cluster returned for %s", *member)
+
+		cluster := clusters.CacheClusters[0]
+		assert.Equal(t, expectedEngine, aws.StringValue(cluster.Engine), "Unexpected engine on %s", *member)
+		t.Logf("✅ %s: Engine=%s, Version=%s", *member, aws.StringValue(cluster.Engine), aws.StringValue(cluster.EngineVersion))
+	}
+}
+
 // testRedisOutputs validates that all expected outputs are present
 func testRedisOutputs(t *testing.T, opts *terraform.Options) {
 	// Verify primary endpoint output
@@ -201,18 +1461,8 @@ func testRedisClusterStatus(t *testing.T, opts *terraform.Options, region, repli
 
 // testRedisConnectivity verifies we can connect to Redis
 func testRedisConnectivity(t *testing.T, opts *terraform.Options) {
-	endpoint := terraform.Output(t, opts, "primary_endpoint_address")
-	port := terraform.Output(t, opts, "port")
-
-	// Create Redis client
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%s", endpoint, port),
-		Password:     "", // No password for testing
-		DB:           0,  // Use default DB
-		DialTimeout:  10 * time.Second,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	})
+	// No password for testing; TLS is used if the cluster requires it
+	rdb := newRedisClientFromOutputs(t, opts)
 	defer rdb.Close()
 
 	ctx := context.Background()
@@ -245,15 +1495,7 @@ func testRedisConnectivity(t *testing.T, opts *terraform.Options) {
 
 // testRedisOperations performs basic Redis operations
 func testRedisOperations(t *testing.T, opts *terraform.Options) {
-	endpoint := terraform.Output(t, opts, "primary_endpoint_address")
-	port := terraform.Output(t, opts, "port")
-
-	// Create Redis client
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", endpoint, port),
-		Password: "",
-		DB:       0,
-	})
+	rdb := newRedisClientFromOutputs(t, opts)
 	defer rdb.Close()
 
 	ctx := context.Background()
@@ -318,19 +1560,54 @@ func testRedisOperations(t *testing.T, opts *terraform.Options) {
 
 	// Cleanup test keys
 	rdb.Del(ctx, hashKey, listKey, setKey)
+
+	// Test 8: Eviction under the allkeys-lru policy
+	testRedisEviction(t, ctx, rdb)
+}
+
+// testRedisEviction writes past maxmemory and checks the eviction policy makes room by evicting keys, rather than
+// rejecting writes with OOM as noeviction would. A key read before every batch is the most recently used, so LRU
+// eviction should keep it. Everything written is flushed afterwards.
+func testRedisEviction(t *testing.T, ctx context.Context, rdb *redis.Client) {
+	maxmemory := redisInfoInt(t, ctx, rdb, "memory", "maxmemory")
+	require.Positive(t, maxmemory, "ElastiCache nodes should have maxmemory set")
+	evictedBefore := redisInfoInt(t, ctx, rdb, "stats", "evicted_keys")
+	defer rdb.FlushDB(ctx)
+
+	hotKey := "test:evict:hot"
+	require.NoError(t, rdb.Set(ctx, hotKey, "recently-used", 0).Err(), "Failed to SET hot key")
+
+	value := strings.Repeat("x", 64*1024)
+	batch := 100
+	written := int64(0)
+	evicted := int64(0)
+	for i := 0; evicted == 0; i++ {
+		// Writing twice maxmemory without an eviction means the policy isn't evicting
+		require.Less(t, written, 2*maxmemory, "Wrote %d bytes against maxmemory %d without any key being evicted", written, maxmemory)
+
+		require.NoError(t, rdb.Get(ctx, hotKey).Err(), "Hot key was evicted")
+
+		pipe := rdb.Pipeline()
+		for j := 0; j < batch; j++ {
+			pipe.Set(ctx, fmt.Sprintf("test:evict:%d", i*batch+j), value, 0)
+		}
+		_, err := pipe.Exec(ctx)
+		require.NoError(t, err, "Writes past maxmemory should evict keys, not fail")
+		written += int64(batch * len(value))
+
+		evicted = redisInfoInt(t, ctx, rdb, "stats", "evicted_keys") - evictedBefore
+	}
+
+	exists, err := rdb.Exists(ctx, hotKey).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists, "The most recently used key should survive LRU eviction")
+	t.Logf("✅ Writing %d MB against maxmemory %d MB evicted %d keys", written>>20, maxmemory>>20, evicted)
 }
 
 // testRedisPersistence verifies data persistence across connections
 func testRedisPersistence(t *testing.T, opts *terraform.Options) {
-	endpoint := terraform.Output(t, opts, "primary_endpoint_address")
-	port := terraform.Output(t, opts, "port")
-
 	// Create first Redis client
-	rdb1 := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", endpoint, port),
-		Password: "",
-		DB:       0,
-	})
+	rdb1 := newRedisClientFromOutputs(t, opts)
 
 	ctx := context.Background()
 
@@ -345,11 +1622,7 @@ func testRedisPersistence(t *testing.T, opts *terraform.Options) {
 	rdb1.Close()
 
 	// Create second Redis client (new connection)
-	rdb2 := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", endpoint, port),
-		Password: "",
-		DB:       0,
-	})
+	rdb2 := newRedisClientFromOutputs(t, opts)
 	defer rdb2.Close()
 
 	// Verify key exists with second client
@@ -362,13 +1635,85 @@ func testRedisPersistence(t *testing.T, opts *terraform.Options) {
 	rdb2.Del(ctx, persistKey)
 }
 
+// testRedisAuthConnectivity verifies the cluster requires the AUTH token stored in the secret: a client authenticating
+// with the token fetched from Secrets Manager can run commands, and one without it is refused
+func testRedisAuthConnectivity(t *testing.T, opts *terraform.Options, sess *session.Session, secretARN string) {
+	assert.Equal(t, secretARN, terraform.Output(t, opts, "auth_token_secret_arn"), "The module should output the secret's ARN")
+
+	token := redisAuthTokenFromSecret(helpers.GetSecretValue(t, sess, secretARN))
+	require.NotEmpty(t, token, "Secret %s holds no AUTH token", secretARN)
+
+	ctx := context.Background()
+
+	t.Run("WithToken", func(t *testing.T) {
+		options := redisOptionsFromOutputs(t, opts)
+		options.Password = token
+
+		rdb := redis.NewClient(options)
+		defer rdb.Close()
+
+		require.NoError(t, rdb.Ping(ctx).Err(), "PING with the AUTH token from the secret failed")
+		t.Log("✅ Authenticated with the AUTH token from Secrets Manager")
+	})
+
+	t.Run("WithoutToken", func(t *testing.T) {
+		options := redisOptionsFromOutputs(t, opts)
+		options.Password = ""
+
+		rdb := redis.NewClient(options)
+		defer rdb.Close()
+
+		err := rdb.Ping(ctx).Err()
+		require.Error(t, err, "PING without the AUTH token should be refused")
+		assert.Contains(t, err.Error(), "NOAUTH")
+		t.Logf("✅ Client without the AUTH token was refused: %v", err)
+	})
+}
+
+// testRedisAlarms verifies each alarm exists and isn't firing on an idle cluster, then that setting enable_alarms to
+// false removes all of them
+func testRedisAlarms(t *testing.T, opts *terraform.Options, sess *session.Session) {
+	var alarmNames []string
+	for _, output := range []string{"cpu_alarm_arn", "memory_alarm_arn", "eviction_alarm_arn"} {
+		alarmARN := terraform.Output(t, opts, output)
+		require.Contains(t, alarmARN, ":alarm:", "%s should be an alarm ARN", output)
+		alarmName := alarmARN[strings.Index(alarmARN, ":alarm:")+len(":alarm:"):]
+		alarmNames = append(alarmNames, alarmName)
+
+		// A new alarm is INSUFFICIENT_DATA until the first datapoints arrive
+		state := helpers.GetCloudWatchAlarmState(t, sess, alarmName)
+		assert.Contains(t, []string{cloudwatch.StateValueOk, cloudwatch.StateValueInsufficientData}, state,
+			"Alarm %s should not be firing on an idle cluster", alarmName)
+		t.Logf("✅ Alarm %s is %s", alarmName, state)
+	}
+
+	t.Log("Re-applying with enable_alarms = false...")
+	opts.Vars["enable_alarms"] = false
+	terraform.Apply(t, opts)
+
+	for _, output := range []string{"cpu_alarm_arn", "memory_alarm_arn", "eviction_alarm_arn"} {
+		var alarmARN *string
+		terraform.OutputStruct(t, opts, output, &alarmARN)
+		assert.Nil(t, alarmARN, "%s should be null with alarms disabled", output)
+	}
+
+	result, err := cloudwatch.New(sess).DescribeAlarms(&cloudwatch.DescribeAlarmsInput{AlarmNames: aws.StringSlice(alarmNames)})
+	require.NoError(t, err, "Failed to describe alarms")
+	assert.Empty(t, result.MetricAlarms, "enable_alarms = false should delete every alarm")
+	t.Log("✅ enable_alarms = false removed all alarms")
+}
+
 // testRedisSecurityGroup verifies security group configuration
-func testRedisSecurityGroup(t *testing.T, opts *terraform.Options) {
+func testRedisSecurityGroup(t *testing.T, opts *terraform.Options, region string) {
 	sgID := terraform.Output(t, opts, "redis_security_group_id")
 
 	// Verify security group ID format
 	assert.Regexp(t, "^sg-[a-f0-9]+$", sgID, "Security group ID should be valid")
 	t.Logf("✅ Security group ID is properly formatted: %s", sgID)
+
+	// egress_cidr_blocks isn't set, so there should be no egress
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: region})
+	helpers.AssertEgressRestricted(t, sess, sgID, nil)
 }
 
 // TestRedisCeleryIntegration tests Redis configuration for Celery
@@ -0,0 +1,138 @@
+package modules_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regionAllowlistMessage is the start of the postcondition error every AWS module raises outside allowed_regions
+const regionAllowlistMessage = "is not an approved region for this module"
+
+// regionlessModules don't use the AWS provider, so they have no region to restrict
+var regionlessModules = map[string]bool{
+	"cloudflare-dns": true,
+}
+
+// TestRegionAllowlist checks every AWS module declares allowed_regions, then plans the examples in a region outside the
+// default allowlist and checks each plan fails on the region before anything is created. Adding the region to
+// allowed_regions must let the plan through, including the security group modules nested inside the service.
+func TestRegionAllowlist(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EveryModuleDeclaresAllowlist", func(t *testing.T) {
+		t.Parallel()
+
+		modules, err := filepath.Glob("../../modules/*")
+		require.NoError(t, err)
+		require.NotEmpty(t, modules, "No modules found")
+
+		for _, module := range modules {
+			if regionlessModules[filepath.Base(module)] {
+				continue
+			}
+			variables := helpers.ParseModuleVariables(t, module)
+			if assert.Contains(t, variables, "allowed_regions", "%s doesn't declare allowed_regions", module) {
+				t.Logf("✅ %s declares allowed_regions", module)
+			}
+		}
+	})
+
+	disallowedRegion := "ap-south-1"
+	masterPassword := fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId())
+
+	examples := []struct {
+		name string
+		dir  string
+		vars map[string]interface{}
+	}{
+		{
+			name: "PostgreSQL",
+			dir:  "postgresql",
+			vars: map[string]interface{}{
+				"name":            "test-pg-region",
+				"master_username": "testadmin",
+				"master_password": masterPassword,
+			},
+		},
+		{
+			name: "Redis",
+			dir:  "redis",
+			vars: map[string]interface{}{"name": "test-redis-region"},
+		},
+		{
+			name: "S3Bucket",
+			dir:  "s3-bucket",
+			vars: map[string]interface{}{"name": "test-s3-region"},
+		},
+		{
+			name: "ECRRepository",
+			dir:  "ecr-repository",
+			vars: map[string]interface{}{},
+		},
+		{
+			name: "ECSFargateService",
+			dir:  "ecs-fargate-service",
+			vars: map[string]interface{}{},
+		},
+		{
+			name: "VPCEndpoints",
+			dir:  "vpc-endpoints",
+			vars: map[string]interface{}{"name": "test-vpce-region"},
+		},
+	}
+
+	for _, example := range examples {
+		example := example
+
+		t.Run(example.name, func(t *testing.T) {
+			t.Parallel()
+
+			vars := map[string]interface{}{"aws_region": disallowedRegion}
+			for key, value := range example.vars {
+				vars[key] = value
+			}
+
+			terraformOptions := &terraform.Options{
+				TerraformDir:    fmt.Sprintf("../../examples/tofu/%s", example.dir),
+				TerraformBinary: "tofu",
+				Vars:            vars,
+				EnvVars: map[string]string{
+					"AWS_DEFAULT_REGION": disallowedRegion,
+				},
+			}
+
+			_, err := terraform.InitAndPlanE(t, terraformOptions)
+			require.Error(t, err, "Plan should fail in %s, which isn't in allowed_regions", disallowedRegion)
+			assert.Contains(t, err.Error(), fmt.Sprintf("Region %s %s", disallowedRegion, regionAllowlistMessage))
+			t.Logf("✅ %s rejected in %s at plan time", example.dir, disallowedRegion)
+		})
+	}
+
+	t.Run("OverrideAllowsRegion", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/ecs-fargate-service",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"aws_region":      disallowedRegion,
+				"allowed_regions": []string{disallowedRegion},
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": disallowedRegion,
+			},
+		}
+
+		output, err := terraform.InitAndPlanE(t, terraformOptions)
+		require.NoError(t, err, "Plan should pass in %s once it is in allowed_regions", disallowedRegion)
+		assert.NotContains(t, output, regionAllowlistMessage)
+		t.Logf("✅ ecs-fargate-service and its nested security group modules planned in %s", disallowedRegion)
+	})
+}
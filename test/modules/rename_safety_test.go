@@ -0,0 +1,112 @@
+package modules_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+)
+
+// TestRenameSafety documents which inputs of the stateful modules can change without losing data. A tag change must be
+// applied in place, while a name change is expected to replace the data store; if the second assertion ever starts
+// failing, the module has become rename-safe and the README should say so.
+func TestRenameSafety(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		t.Parallel()
+
+		name := helpers.UniqueResourceName("pg-rename", helpers.RDSIdentifierNaming)
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/postgresql",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":            name,
+				"master_username": "testadmin",
+				"master_password": fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId()),
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying PostgreSQL... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+
+		assertRenameSafety(t, terraformOptions, "module.postgresql.aws_db_instance.postgresql",
+			helpers.UniqueResourceName("pg-renamed", helpers.RDSIdentifierNaming))
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		t.Parallel()
+
+		name := helpers.UniqueResourceName("redis-rename", helpers.ElastiCacheNaming)
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/redis",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name": name,
+			},
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		defer terraform.Destroy(t, terraformOptions)
+
+		t.Log("Deploying Redis... (this may take 10-15 minutes)")
+		terraform.InitAndApply(t, terraformOptions)
+
+		assertRenameSafety(t, terraformOptions, "module.redis.aws_elasticache_replication_group.redis",
+			helpers.UniqueResourceName("redis-renamed", helpers.ElastiCacheNaming))
+	})
+}
+
+// assertRenameSafety plans a tag change, which must not destroy anything, and a rename, which must replace the data
+// store at address. Both are only planned, so the deployed resources are left as they were.
+func assertRenameSafety(t *testing.T, deployed *terraform.Options, address, newName string) {
+	t.Run("TagChangeInPlace", func(t *testing.T) {
+		opts := withVars(deployed, map[string]interface{}{
+			"tags": map[string]string{"CostCenter": "rename-safety"},
+		})
+		opts.PlanFilePath = filepath.Join(t.TempDir(), "tags.tfplan")
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, opts)
+		helpers.AssertNoDestructiveChanges(t, plan)
+	})
+
+	t.Run("RenameReplaces", func(t *testing.T) {
+		opts := withVars(deployed, map[string]interface{}{
+			"name": newName,
+		})
+		opts.PlanFilePath = filepath.Join(t.TempDir(), "rename.tfplan")
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, opts)
+		helpers.AssertResourceReplaced(t, plan, address)
+	})
+}
+
+// withVars returns a copy of opts with overrides merged over its vars, leaving opts untouched for the deferred destroy
+func withVars(opts *terraform.Options, overrides map[string]interface{}) *terraform.Options {
+	vars := make(map[string]interface{}, len(opts.Vars)+len(overrides))
+	for key, value := range opts.Vars {
+		vars[key] = value
+	}
+	for key, value := range overrides {
+		vars[key] = value
+	}
+
+	return &terraform.Options{
+		TerraformDir:    opts.TerraformDir,
+		TerraformBinary: opts.TerraformBinary,
+		Vars:            vars,
+		EnvVars:         opts.EnvVars,
+	}
+}
@@ -0,0 +1,62 @@
+package modules_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// sharedVPCSubnetIDsEnvVar lists, comma-separated, the shared subnets a workload account deploys the modules into
+	sharedVPCSubnetIDsEnvVar = "SHARED_VPC_SUBNET_IDS"
+	// sharedVPCOwnerProfileEnvVar is the AWS profile of the central networking account that owns and shares them
+	sharedVPCOwnerProfileEnvVar = "SHARED_VPC_OWNER_PROFILE"
+)
+
+// TestSharedVPCSubnets verifies, in a multi-account landing zone, that the subnets the workload account passes to the
+// modules' subnet_ids are owned by the networking account and shared to the workload account through RAM. It runs
+// with the workload account's credentials and is skipped unless SHARED_VPC_SUBNET_IDS and SHARED_VPC_OWNER_PROFILE
+// are set, since single-account deployments have no shares to check.
+func TestSharedVPCSubnets(t *testing.T) {
+	t.Parallel()
+
+	subnetIDs := os.Getenv(sharedVPCSubnetIDsEnvVar)
+	ownerProfile := os.Getenv(sharedVPCOwnerProfileEnvVar)
+	if subnetIDs == "" || ownerProfile == "" {
+		t.Skipf("Set %s and %s to check shared-VPC subnets", sharedVPCSubnetIDsEnvVar, sharedVPCOwnerProfileEnvVar)
+	}
+
+	awsRegion := "us-east-1"
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	ownerSess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion, Profile: ownerProfile})
+
+	workloadAccountID := helpers.GetAccountID(t, sess)
+	ownerAccountID := helpers.GetAccountID(t, ownerSess)
+	require.NotEqual(t, ownerAccountID, workloadAccountID, "%s should be a profile for the networking account", sharedVPCOwnerProfileEnvVar)
+
+	// Shared subnets are visible to the workload account, so describe them as the modules would see them
+	result, err := ec2.New(sess).DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(strings.Split(subnetIDs, ",")),
+	})
+	require.NoError(t, err, "Workload account %s can't see subnets %s", workloadAccountID, subnetIDs)
+
+	availabilityZones := map[string]bool{}
+	for _, subnet := range result.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		availabilityZones[aws.StringValue(subnet.AvailabilityZone)] = true
+
+		t.Run(subnetID, func(t *testing.T) {
+			assert.Equal(t, ownerAccountID, aws.StringValue(subnet.OwnerId), "Subnet should be owned by the networking account")
+			helpers.AssertResourceShared(t, ownerSess, aws.StringValue(subnet.SubnetArn), workloadAccountID)
+		})
+	}
+
+	// postgresql and redis need subnets in at least two AZs for Multi-AZ
+	assert.GreaterOrEqual(t, len(availabilityZones), 2, "Shared subnets should span at least 2 availability zones")
+}
@@ -0,0 +1,119 @@
+package modules_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// catalogSizingPolicy is the largest configuration the examples may plan. Anything bigger needs a deliberate decision,
+// not a copied variable.
+var catalogSizingPolicy = helpers.SizingPolicy{
+	MaxRDSInstanceClass:     "db.r6g.2xlarge",
+	MaxRDSStorageGB:         1000,
+	MaxElastiCacheNodeType:  "cache.r6g.xlarge",
+	RequireS3LifecycleRules: true,
+}
+
+// TestSizingGuardrails plans every example that creates a database, cache, or bucket and checks the plan against
+// catalogSizingPolicy, then checks the policy catches an oversized database. Nothing is applied.
+func TestSizingGuardrails(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	masterPassword := fmt.Sprintf("Test%s!%s", random.UniqueId(), random.UniqueId())
+
+	examples := []struct {
+		name string
+		dir  string
+		vars map[string]interface{}
+	}{
+		{
+			name: "PostgreSQL",
+			dir:  "postgresql",
+			vars: map[string]interface{}{
+				"name":            "test-pg-sizing",
+				"master_username": "testadmin",
+				"master_password": masterPassword,
+			},
+		},
+		{
+			name: "Redis",
+			dir:  "redis",
+			vars: map[string]interface{}{"name": "test-redis-sizing"},
+		},
+		{
+			name: "DataTier",
+			dir:  "data-tier",
+			vars: map[string]interface{}{
+				"name":            "test-data-sizing",
+				"master_username": "testadmin",
+				"master_password": masterPassword,
+			},
+		},
+		{
+			name: "S3Bucket",
+			dir:  "s3-bucket",
+			vars: map[string]interface{}{"name": "test-s3-sizing"},
+		},
+		{
+			name: "S3CdnBucket",
+			dir:  "s3-cdn-bucket",
+			vars: map[string]interface{}{"name": "test-cdn-sizing"},
+		},
+	}
+
+	for _, example := range examples {
+		example := example
+
+		t.Run(example.name, func(t *testing.T) {
+			t.Parallel()
+
+			terraformOptions := &terraform.Options{
+				TerraformDir:    fmt.Sprintf("../../examples/tofu/%s", example.dir),
+				TerraformBinary: "tofu",
+				Vars:            example.vars,
+				PlanFilePath:    filepath.Join(t.TempDir(), "sizing.tfplan"),
+				EnvVars: map[string]string{
+					"AWS_DEFAULT_REGION": awsRegion,
+				},
+			}
+
+			plan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+			helpers.AssertResourceSizingWithinPolicy(t, plan, catalogSizingPolicy)
+		})
+	}
+
+	// Guard against a policy that passes everything
+	t.Run("OversizedPostgreSQLRejected", func(t *testing.T) {
+		t.Parallel()
+
+		terraformOptions := &terraform.Options{
+			TerraformDir:    "../../examples/tofu/postgresql",
+			TerraformBinary: "tofu",
+			Vars: map[string]interface{}{
+				"name":                  "test-pg-oversized",
+				"master_username":       "testadmin",
+				"master_password":       masterPassword,
+				"instance_class":        "db.r6g.4xlarge",
+				"max_allocated_storage": 2000,
+			},
+			PlanFilePath: filepath.Join(t.TempDir(), "oversized.tfplan"),
+			EnvVars: map[string]string{
+				"AWS_DEFAULT_REGION": awsRegion,
+			},
+		}
+
+		plan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+		violations := helpers.FindSizingViolations(plan, catalogSizingPolicy)
+		assert.Len(t, violations, 2, "Expected the instance class and the storage limit to be flagged, got %v", violations)
+		for _, violation := range violations {
+			t.Logf("✅ Flagged: %s", violation)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package modules_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagMergeResourceTypes are ARN fragments of the resource types the Redis example creates, so the merge is checked
+// across services rather than on one resource
+var tagMergeResourceTypes = []string{
+	":replicationgroup:",
+	":parametergroup:",
+	":subnetgroup:",
+	":security-group/",
+	":log-group:",
+	":alarm:",
+}
+
+// TestTagMergeBehavior deploys the Redis example with provider default tags and module tags that share a key, and
+// checks through the tagging API that every resource carries the union of both, with the module's value winning the
+// conflict. Resources are found by a default tag, so one the module forgot to tag shows up missing the module tags.
+func TestTagMergeBehavior(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	name := fmt.Sprintf("redis-tags-%s", uniqueID)
+	awsRegion := "us-east-1"
+
+	defaultTags := map[string]string{
+		"TagMergeRun": uniqueID,
+		"Team":        "platform",
+		"CostCenter":  "org-default",
+	}
+	moduleTags := map[string]string{
+		"CostCenter": "cache",
+		"Service":    "redis",
+	}
+
+	expected := map[string]string{}
+	for key, value := range defaultTags {
+		expected[key] = value
+	}
+	for key, value := range moduleTags {
+		expected[key] = value
+	}
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/redis",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":          name,
+			"enable_alarms": true,
+			"default_tags":  defaultTags,
+			"tags":          moduleTags,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying Redis with provider default tags and module tags...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	var resources map[string]map[string]string
+	helpers.WaitForCondition(t, helpers.RetryConfig{
+		MaxRetries:    20,
+		RetryInterval: 15 * time.Second,
+		Description:   "tagged resources listed",
+	}, func() bool {
+		resources = helpers.GetTaggedResources(t, sess, "TagMergeRun", uniqueID)
+		for _, resourceType := range tagMergeResourceTypes {
+			if !hasResourceOfType(resources, resourceType) {
+				return false
+			}
+		}
+		return true
+	}, "The tagging API didn't list every expected resource type (%v)", tagMergeResourceTypes)
+
+	for resourceARN, tags := range resources {
+		for key, value := range expected {
+			assert.Equal(t, value, tags[key], "%s has the wrong %s tag", resourceARN, key)
+		}
+	}
+	t.Logf("✅ %d resources carry the merged tags, with the module's CostCenter overriding the default", len(resources))
+}
+
+// hasResourceOfType reports whether any of the ARNs contains the resource type fragment
+func hasResourceOfType(resources map[string]map[string]string, resourceType string) bool {
+	for resourceARN := range resources {
+		if strings.Contains(resourceARN, resourceType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package modules_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrivateFargateEndpoints deploys the vpc-endpoints module into a VPC with no NAT or internet gateway and checks
+// every endpoint a private Fargate task needs is there: S3 as a gateway for ECR image layers, and ECR API/DKR, Secrets
+// Manager, and CloudWatch Logs as private-DNS interface endpoints in the private subnets. Without any one of them tasks
+// fail with opaque timeouts such as "ResourceInitializationError: unable to pull secrets or registry auth".
+func TestPrivateFargateEndpoints(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := random.UniqueId()
+	name := fmt.Sprintf("vpce-%s", strings.ToLower(uniqueID))
+	awsRegion := "us-east-1"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/vpc-endpoints",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":       name,
+			"aws_region": awsRegion,
+		},
+		EnvVars: map[string]string{
+			"AWS_DEFAULT_REGION": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	t.Log("Deploying VPC endpoints into a private VPC...")
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	vpcID := terraform.Output(t, terraformOptions, "vpc_id")
+	subnetIDs := terraform.OutputList(t, terraformOptions, "private_subnet_ids")
+	endpointSgID := terraform.Output(t, terraformOptions, "vpc_endpoints_security_group_id")
+
+	t.Run("S3Gateway", func(t *testing.T) {
+		endpoint := helpers.AssertVPCEndpointExists(t, sess, vpcID, fmt.Sprintf("com.amazonaws.%s.s3", awsRegion))
+		assert.Equal(t, ec2.VpcEndpointTypeGateway, aws.StringValue(endpoint.VpcEndpointType))
+		assert.NotEmpty(t, endpoint.RouteTableIds, "S3 gateway endpoint should be attached to the private route table")
+	})
+
+	for _, service := range []string{"ecr.api", "ecr.dkr", "secretsmanager", "logs"} {
+		service := service
+		t.Run(service, func(t *testing.T) {
+			endpoint := helpers.AssertVPCEndpointExists(t, sess, vpcID, fmt.Sprintf("com.amazonaws.%s.%s", awsRegion, service))
+			assert.Equal(t, ec2.VpcEndpointTypeInterface, aws.StringValue(endpoint.VpcEndpointType))
+			assert.True(t, aws.BoolValue(endpoint.PrivateDnsEnabled), "Private DNS must be enabled so SDKs resolve the endpoint without configuration")
+			assert.ElementsMatch(t, subnetIDs, aws.StringValueSlice(endpoint.SubnetIds), "Interface endpoint should have an ENI in every private subnet")
+
+			require.Len(t, endpoint.Groups, 1, "Interface endpoint should have one security group")
+			assert.Equal(t, endpointSgID, aws.StringValue(endpoint.Groups[0].GroupId))
+		})
+	}
+}
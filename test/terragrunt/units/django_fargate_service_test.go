@@ -8,8 +8,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
 	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -166,3 +170,75 @@ func TestDjangoModuleMinimal(t *testing.T) {
 		t.Logf("✅ Terraform plan successful")
 	})
 }
+
+// TestDjangoDestroyIsolation verifies destroying the Django unit leaves the PostgreSQL and Redis units it depends on
+// untouched, so tearing down compute can never take the data tier with it
+func TestDjangoDestroyIsolation(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+
+	postgresOptions := &terraform.Options{
+		TerraformDir:    "../../../units/postgresql",
+		TerraformBinary: "terragrunt",
+		Vars: map[string]interface{}{
+			// Let the deferred destroy remove the instance once the test is done
+			"deletion_protection": false,
+			"skip_final_snapshot": true,
+			"multi_az":            false,
+		},
+	}
+	redisOptions := &terraform.Options{
+		TerraformDir:    "../../../units/redis",
+		TerraformBinary: "terragrunt",
+	}
+	djangoOptions := &terraform.Options{
+		TerraformDir:    "../../../units/django-fargate-stateful-service",
+		TerraformBinary: "terragrunt",
+	}
+
+	// The data stores are destroyed last, after the isolation checks
+	defer terraform.RunTerraformCommand(t, postgresOptions, "destroy", "-auto-approve")
+	defer terraform.RunTerraformCommand(t, redisOptions, "destroy", "-auto-approve")
+
+	t.Log("Deploying PostgreSQL and Redis units... (this may take 10-15 minutes)")
+	terraform.RunTerraformCommand(t, postgresOptions, "apply", "-auto-approve")
+	terraform.RunTerraformCommand(t, redisOptions, "apply", "-auto-approve")
+
+	dbARN, err := terraform.RunTerraformCommandAndGetStdoutE(t, postgresOptions, "output", "-raw", "arn")
+	require.NoError(t, err)
+	dbIdentifier := helpers.DBInstanceIdentifierFromARN(dbARN)
+
+	replicationGroupID, err := terraform.RunTerraformCommandAndGetStdoutE(t, redisOptions, "output", "-raw", "id")
+	require.NoError(t, err)
+
+	t.Log("Deploying the Django unit on top of the data stores...")
+	terraform.RunTerraformCommand(t, djangoOptions, "apply", "-auto-approve")
+
+	t.Log("Destroying only the Django unit...")
+	terraform.RunTerraformCommand(t, djangoOptions, "destroy", "-auto-approve")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("DatabaseSurvives", func(t *testing.T) {
+		result, err := rds.New(sess).DescribeDBInstances(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(dbIdentifier),
+		})
+		require.NoError(t, err, "Database %s should still exist after destroying the Django unit", dbIdentifier)
+		require.Len(t, result.DBInstances, 1)
+		assert.Equal(t, "available", aws.StringValue(result.DBInstances[0].DBInstanceStatus),
+			"Database %s should still be available", dbIdentifier)
+		t.Logf("✅ Database %s is still available", dbIdentifier)
+	})
+
+	t.Run("CacheSurvives", func(t *testing.T) {
+		result, err := elasticache.New(sess).DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(replicationGroupID),
+		})
+		require.NoError(t, err, "Replication group %s should still exist after destroying the Django unit", replicationGroupID)
+		require.Len(t, result.ReplicationGroups, 1)
+		assert.Equal(t, "available", aws.StringValue(result.ReplicationGroups[0].Status),
+			"Replication group %s should still be available", replicationGroupID)
+		t.Logf("✅ Replication group %s is still available", replicationGroupID)
+	})
+}
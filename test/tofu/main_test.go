@@ -0,0 +1,13 @@
+package tofu_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+)
+
+// TestMain tags everything the tests create with a shared run ID and sweeps any leftovers once they finish
+func TestMain(m *testing.M) {
+	os.Exit(helpers.RunWithSweep(m, "us-east-1"))
+}
@@ -2,12 +2,18 @@ package tofu_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/gruntwork-io/terratest/modules/files"
 	"github.com/gruntwork-io/terratest/modules/random"
-
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModuleS3Bucket(t *testing.T) {
@@ -25,3 +31,78 @@ func TestModuleS3Bucket(t *testing.T) {
 
 	terraform.InitAndApply(t, terraformOptions)
 }
+
+// TestWorkspaceIsolation applies the same configuration, with the same variables, in two workspaces at once, the way
+// CI deploys dev and staging side by side. Each workspace must get its own state and its own, differently named bucket.
+func TestWorkspaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	// Work on a copy so the workspaces' state doesn't end up in the repo. The whole repo is copied so the example's
+	// relative module source still resolves.
+	repoCopy, err := files.CopyTerraformFolderToTemp("../..", "workspace-test")
+	require.NoError(t, err, "Failed to copy the repo to a temp folder")
+	exampleDir := filepath.Join(repoCopy, "examples/tofu/s3-bucket")
+
+	name := helpers.UniqueResourceName("workspace-test", helpers.S3BucketNaming)
+	baseOptions := &terraform.Options{
+		TerraformDir:    exampleDir,
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name": name,
+		},
+	}
+
+	// Init and create the workspaces one at a time, since both write to the shared .terraform directory. The applies
+	// below pick their workspace with TF_WORKSPACE instead of selecting it, so they don't race over the selection.
+	workspaces := []string{"dev", "staging"}
+	terraform.Init(t, baseOptions)
+	for _, workspace := range workspaces {
+		terraform.WorkspaceSelectOrNew(t, baseOptions, workspace)
+	}
+
+	options := make([]*terraform.Options, len(workspaces))
+	for i, workspace := range workspaces {
+		options[i] = &terraform.Options{
+			TerraformDir:    exampleDir,
+			TerraformBinary: "tofu",
+			Vars:            baseOptions.Vars,
+			EnvVars: map[string]string{
+				"TF_WORKSPACE": workspace,
+			},
+		}
+		defer terraform.Destroy(t, options[i])
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(workspaces))
+	for i := range workspaces {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = terraform.ApplyE(t, options[i])
+		}(i)
+	}
+	wg.Wait()
+
+	bucketNames := map[string]bool{}
+	for i, workspace := range workspaces {
+		require.NoError(t, errs[i], "Apply in workspace %s failed; the workspaces may have contended for state or names", workspace)
+
+		bucketName := terraform.Output(t, options[i], "name")
+		assert.Equal(t, fmt.Sprintf("%s-%s", name, workspace), bucketName, "Workspace %s should name its bucket after itself", workspace)
+		bucketNames[bucketName] = true
+
+		statePath := filepath.Join(exampleDir, "terraform.tfstate.d", workspace, "terraform.tfstate")
+		_, err := os.Stat(statePath)
+		assert.NoError(t, err, "Workspace %s should keep its own state at %s", workspace, statePath)
+
+		resources := terraform.RunTerraformCommand(t, options[i], "state", "list")
+		assert.Contains(t, resources, "module.s3_bucket.aws_s3_bucket.bucket", "Workspace %s should track its own bucket", workspace)
+	}
+	assert.Len(t, bucketNames, len(workspaces), "Each workspace should create a distinct bucket")
+
+	_, err = os.Stat(filepath.Join(exampleDir, "terraform.tfstate"))
+	assert.True(t, os.IsNotExist(err), "Nothing should have been applied in the default workspace")
+
+	t.Logf("✅ Workspaces %s deployed side by side with separate state", strings.Join(workspaces, " and "))
+}
@@ -2,12 +2,21 @@ package tofu_test
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/lightwave-media/lightwave-infrastructure-catalog/test/helpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestModuleS3CdnBucket tests the S3 bucket module configured for CDN use
@@ -50,3 +59,488 @@ func TestModuleS3CdnBucket(t *testing.T) {
 	assert.NotEmpty(t, regionalDomain, "bucket_regional_domain_name should be set")
 	assert.Contains(t, regionalDomain, "s3", "regional domain should be an S3 domain")
 }
+
+// TestS3EventNotification tests that uploading an object to the bucket publishes an event to the notification queue
+func TestS3EventNotification(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-events-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	prefix := "uploads/"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                       bucketName,
+			"aws_region":                 awsRegion,
+			"enable_event_notification":  true,
+			"notification_filter_prefix": prefix,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	queueURL := terraform.Output(t, terraformOptions, "notification_queue_url")
+	queueARN := terraform.Output(t, terraformOptions, "notification_queue_arn")
+	require.NotEmpty(t, queueURL, "notification_queue_url should be set when notifications are enabled")
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	s3Client := s3.New(sess)
+
+	// Verify the bucket notification configuration points at the queue
+	config, err := s3Client.GetBucketNotificationConfiguration(&s3.GetBucketNotificationConfigurationRequest{
+		Bucket: aws.String(bucketName),
+	})
+	require.NoError(t, err, "Failed to get bucket notification configuration")
+	require.Len(t, config.QueueConfigurations, 1, "Bucket should have exactly one queue notification")
+
+	queueConfig := config.QueueConfigurations[0]
+	assert.Equal(t, queueARN, aws.StringValue(queueConfig.QueueArn))
+	assert.Contains(t, aws.StringValueSlice(queueConfig.Events), "s3:ObjectCreated:*")
+
+	// Upload an object and wait for the notification to arrive
+	objectKey := fmt.Sprintf("%sasset-%s.txt", prefix, strings.ToLower(random.UniqueId()))
+	_, err = s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader("event notification test"),
+	})
+	require.NoError(t, err, "Failed to upload test object")
+
+	event := helpers.WaitForS3EventNotification(t, sess, queueURL, objectKey, 2*time.Minute)
+	require.NotEmpty(t, event.Records)
+	assert.Equal(t, bucketName, event.Records[0].S3.Bucket.Name)
+	assert.True(t, strings.HasPrefix(event.Records[0].EventName, "ObjectCreated:"),
+		"Expected an ObjectCreated event, got %s", event.Records[0].EventName)
+}
+
+// TestS3LargeObjectUpload tests that large CDN assets can be uploaded with multipart upload through the transfer
+// acceleration endpoint and retrieved intact
+func TestS3LargeObjectUpload(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-large-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	objectKey := "videos/large-asset.bin"
+	objectSize := int64(100 * 1024 * 1024)
+	partSize := int64(10 * 1024 * 1024)
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":                         bucketName,
+			"aws_region":                   awsRegion,
+			"enable_transfer_acceleration": true,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	s3Client := s3.New(sess)
+
+	t.Run("AccelerationEnabled", func(t *testing.T) {
+		config, err := s3Client.GetBucketAccelerateConfiguration(&s3.GetBucketAccelerateConfigurationInput{
+			Bucket: aws.String(bucketName),
+		})
+		require.NoError(t, err, "Failed to get bucket accelerate configuration")
+		assert.Equal(t, s3.BucketAccelerateStatusEnabled, aws.StringValue(config.Status))
+
+		endpoint := terraform.Output(t, terraformOptions, "transfer_acceleration_endpoint")
+		assert.Equal(t, fmt.Sprintf("%s.s3-accelerate.amazonaws.com", bucketName), endpoint)
+	})
+
+	uploadedChecksum := helpers.UploadLargeObject(t, sess, bucketName, objectKey, objectSize, partSize, true)
+
+	t.Run("MultipartUploadCompleted", func(t *testing.T) {
+		head, err := s3Client.HeadObject(&s3.HeadObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+		})
+		require.NoError(t, err, "Uploaded object should exist")
+		assert.Equal(t, objectSize, aws.Int64Value(head.ContentLength))
+
+		// Multipart ETags end in -<number of parts>
+		expectedParts := objectSize / partSize
+		assert.True(t, strings.HasSuffix(strings.Trim(aws.StringValue(head.ETag), `"`), fmt.Sprintf("-%d", expectedParts)),
+			"ETag %s should show a %d-part upload", aws.StringValue(head.ETag), expectedParts)
+	})
+
+	t.Run("Retrievable", func(t *testing.T) {
+		downloadedChecksum := helpers.DownloadObjectSHA256(t, sess, bucketName, objectKey)
+		assert.Equal(t, uploadedChecksum, downloadedChecksum, "Downloaded object should match what was uploaded")
+		t.Logf("✅ %d MB object round-tripped intact (sha256 %s)", objectSize/(1024*1024), downloadedChecksum)
+	})
+}
+
+// TestS3HTTPSOnly tests that the bucket policy denies requests made without TLS: the same public object is served
+// over HTTPS but refused over plain HTTP
+func TestS3HTTPSOnly(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-https-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	objectKey := "assets/https-only.txt"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":       bucketName,
+			"aws_region": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+
+	t.Run("PolicyDeniesInsecureTransport", func(t *testing.T) {
+		var deny *helpers.BucketPolicyStatement
+		for _, statement := range helpers.GetBucketPolicyStatements(t, sess, bucketName) {
+			if statement.Effect == "Deny" && statement.Condition["Bool"]["aws:SecureTransport"] != nil {
+				statement := statement
+				deny = &statement
+			}
+		}
+		require.NotNil(t, deny, "Bucket policy should deny requests where aws:SecureTransport is false")
+		assert.Equal(t, "false", deny.Condition["Bool"]["aws:SecureTransport"])
+		assert.Equal(t, "*", deny.Principal)
+		assert.Equal(t, "s3:*", deny.Action)
+	})
+
+	_, err := s3.New(sess).PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader("https only"),
+	})
+	require.NoError(t, err, "Failed to upload test object")
+
+	objectPath := fmt.Sprintf("%s/%s", terraform.Output(t, terraformOptions, "bucket_regional_domain_name"), objectKey)
+
+	t.Run("HTTPSAllowed", func(t *testing.T) {
+		// Bucket policies take a few seconds to apply everywhere
+		http_helper.HttpGetWithRetry(t, "https://"+objectPath, nil, http.StatusOK, "https only", 12, 5*time.Second)
+	})
+
+	t.Run("HTTPDenied", func(t *testing.T) {
+		status, body := http_helper.HttpGet(t, "http://"+objectPath, nil)
+		assert.Equal(t, http.StatusForbidden, status, "Plain HTTP request should be denied")
+		assert.Contains(t, body, "AccessDenied")
+		assert.NotContains(t, body, "https only", "Object contents should not be served over plain HTTP")
+	})
+}
+
+// TestS3OwnershipControls tests that the bucket enforces bucket-owner object ownership, which disables ACLs, so public
+// access can only come from the bucket policy and an ACL grant on an object is rejected
+func TestS3OwnershipControls(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-owner-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	objectKey := "assets/owned.txt"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":       bucketName,
+			"aws_region": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	s3Client := s3.New(helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion}))
+
+	t.Run("ACLsDisabled", func(t *testing.T) {
+		controls, err := s3Client.GetBucketOwnershipControls(&s3.GetBucketOwnershipControlsInput{
+			Bucket: aws.String(bucketName),
+		})
+		require.NoError(t, err, "Failed to get ownership controls of bucket %s", bucketName)
+		require.Len(t, controls.OwnershipControls.Rules, 1)
+		assert.Equal(t, s3.ObjectOwnershipBucketOwnerEnforced, aws.StringValue(controls.OwnershipControls.Rules[0].ObjectOwnership))
+	})
+
+	t.Run("ACLGrantRejected", func(t *testing.T) {
+		_, err := s3Client.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+			Body:   strings.NewReader("bucket owned"),
+		})
+		require.NoError(t, err, "Failed to upload test object")
+
+		_, err = s3Client.PutObjectAcl(&s3.PutObjectAclInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+			ACL:    aws.String(s3.ObjectCannedACLPublicRead),
+		})
+		require.Error(t, err, "Setting an ACL should be rejected when ACLs are disabled")
+
+		var awsErr awserr.Error
+		require.ErrorAs(t, err, &awsErr)
+		assert.Equal(t, "AccessControlListNotSupported", awsErr.Code())
+	})
+}
+
+// TestS3CloudFront tests that with enable_cloudfront the bucket is served through a CloudFront distribution that reads
+// it with Origin Access Control, while the bucket itself keeps public access blocked
+func TestS3CloudFront(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-cf-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	objectKey := "assets/cached.txt"
+	objectBody := "served by cloudfront"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":              bucketName,
+			"aws_region":        awsRegion,
+			"enable_cloudfront": true,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	sess := helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion})
+	s3Client := s3.New(sess)
+
+	t.Run("PublicAccessBlocked", func(t *testing.T) {
+		result, err := s3Client.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{Bucket: aws.String(bucketName)})
+		require.NoError(t, err, "Failed to get public access block of bucket %s", bucketName)
+
+		config := result.PublicAccessBlockConfiguration
+		assert.True(t, aws.BoolValue(config.BlockPublicAcls))
+		assert.True(t, aws.BoolValue(config.BlockPublicPolicy))
+		assert.True(t, aws.BoolValue(config.IgnorePublicAcls))
+		assert.True(t, aws.BoolValue(config.RestrictPublicBuckets))
+	})
+
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader(objectBody),
+	})
+	require.NoError(t, err, "Failed to upload test object")
+
+	t.Run("BucketNotPublic", func(t *testing.T) {
+		objectURL := fmt.Sprintf("https://%s/%s", terraform.Output(t, terraformOptions, "bucket_regional_domain_name"), objectKey)
+		status, body := http_helper.HttpGet(t, objectURL, nil)
+		assert.Equal(t, http.StatusForbidden, status, "The bucket should only be readable through CloudFront")
+		assert.NotContains(t, body, objectBody)
+	})
+
+	distributionID := terraform.Output(t, terraformOptions, "cloudfront_distribution_id")
+	helpers.WaitForCloudFrontDeployed(t, sess, distributionID, 30*time.Minute)
+
+	t.Run("ServedFromCache", func(t *testing.T) {
+		objectURL := fmt.Sprintf("https://%s/%s", terraform.Output(t, terraformOptions, "cloudfront_domain_name"), objectKey)
+
+		// The first request fills the edge cache, so a repeat of it should be a hit
+		http_helper.HttpGetWithRetry(t, objectURL, nil, http.StatusOK, objectBody, 12, 5*time.Second)
+
+		helpers.WaitForCondition(t, helpers.FastRetryConfig("CloudFront cache hit"), func() bool {
+			resp, err := http.Get(objectURL)
+			if err != nil {
+				t.Logf("Request to %s failed: %v", objectURL, err)
+				return false
+			}
+			defer resp.Body.Close()
+
+			cache := resp.Header.Get("X-Cache")
+			t.Logf("GET %s: %d (X-Cache: %s)", objectURL, resp.StatusCode, cache)
+			return resp.StatusCode == http.StatusOK && strings.HasPrefix(cache, "Hit from cloudfront")
+		}, "%s to be served from the CloudFront cache", objectKey)
+	})
+}
+
+// TestS3LifecycleRules tests that a lifecycle rule added to a bucket that already holds objects is applied alongside
+// the module's cleanup rule and scoped to its prefix. It checks the configuration only; S3 expires objects about once a
+// day, which is too slow to wait for.
+func TestS3LifecycleRules(t *testing.T) {
+	t.Parallel()
+
+	bucketName := fmt.Sprintf("cdn-lifecycle-%s", strings.ToLower(random.UniqueId()))
+	awsRegion := "us-east-1"
+	prefix := "tmp/"
+	objectKey := prefix + "upload.txt"
+
+	terraformOptions := &terraform.Options{
+		TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+		TerraformBinary: "tofu",
+		Vars: map[string]interface{}{
+			"name":       bucketName,
+			"aws_region": awsRegion,
+		},
+	}
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	s3Client := s3.New(helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion}))
+
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader("expires tomorrow"),
+	})
+	require.NoError(t, err, "Failed to upload test object")
+
+	terraformOptions.Vars["lifecycle_rules"] = []map[string]interface{}{
+		{"id": "expire-tmp", "prefix": prefix, "expiration_days": 1},
+	}
+	terraform.Apply(t, terraformOptions)
+
+	t.Run("RuleCount", func(t *testing.T) {
+		assert.Equal(t, "2", terraform.Output(t, terraformOptions, "lifecycle_rule_count"), "Expected the cleanup rule plus expire-tmp")
+	})
+
+	t.Run("RuleScopedByPrefix", func(t *testing.T) {
+		result, err := s3Client.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucketName),
+		})
+		require.NoError(t, err, "Failed to get lifecycle configuration of bucket %s", bucketName)
+
+		var rule *s3.LifecycleRule
+		for _, candidate := range result.Rules {
+			if aws.StringValue(candidate.ID) == "expire-tmp" {
+				rule = candidate
+			}
+		}
+		require.NotNil(t, rule, "Lifecycle rule expire-tmp not found in %v", result.Rules)
+
+		assert.Equal(t, s3.ExpirationStatusEnabled, aws.StringValue(rule.Status))
+		require.NotNil(t, rule.Filter, "Rule should have a filter")
+		assert.Equal(t, prefix, aws.StringValue(rule.Filter.Prefix), "Rule should only apply to %s", prefix)
+		require.NotNil(t, rule.Expiration, "Rule should expire objects")
+		assert.EqualValues(t, 1, aws.Int64Value(rule.Expiration.Days))
+		t.Logf("✅ Rule expire-tmp expires objects under %s after %d day", prefix, aws.Int64Value(rule.Expiration.Days))
+	})
+
+	t.Run("ExistingObjectKept", func(t *testing.T) {
+		_, err := s3Client.HeadObject(&s3.HeadObjectInput{Bucket: aws.String(bucketName), Key: aws.String(objectKey)})
+		assert.NoError(t, err, "Adding the rule should not remove objects before they expire")
+	})
+}
+
+// TestS3Versioning tests that with versioning enabled, overwriting a key keeps both versions and each can be read back
+// by its VersionId, and that with versioning suspended the overwrite replaces the object under the null version ID
+func TestS3Versioning(t *testing.T) {
+	t.Parallel()
+
+	awsRegion := "us-east-1"
+	objectKey := "assets/versioned.txt"
+	contents := []string{"first version", "second version"}
+
+	testCases := []struct {
+		name             string
+		enableVersioning bool
+		expectedStatus   string
+	}{
+		{name: "Enabled", enableVersioning: true, expectedStatus: s3.BucketVersioningStatusEnabled},
+		{name: "Suspended", enableVersioning: false, expectedStatus: s3.BucketVersioningStatusSuspended},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			bucketName := fmt.Sprintf("cdn-versions-%s", strings.ToLower(random.UniqueId()))
+
+			terraformOptions := &terraform.Options{
+				TerraformDir:    "../../examples/tofu/s3-cdn-bucket",
+				TerraformBinary: "tofu",
+				Vars: map[string]interface{}{
+					"name":              bucketName,
+					"aws_region":        awsRegion,
+					"enable_versioning": testCase.enableVersioning,
+				},
+			}
+
+			defer terraform.Destroy(t, terraformOptions)
+
+			terraform.InitAndApply(t, terraformOptions)
+
+			assert.Equal(t, testCase.expectedStatus, terraform.Output(t, terraformOptions, "versioning_status"))
+
+			s3Client := s3.New(helpers.GetAWSSession(t, helpers.AWSSessionConfig{Region: awsRegion}))
+
+			var putVersionIDs []string
+			for _, content := range contents {
+				result, err := s3Client.PutObject(&s3.PutObjectInput{
+					Bucket: aws.String(bucketName),
+					Key:    aws.String(objectKey),
+					Body:   strings.NewReader(content),
+				})
+				require.NoError(t, err, "Failed to upload %q", content)
+				putVersionIDs = append(putVersionIDs, aws.StringValue(result.VersionId))
+			}
+
+			listed, err := s3Client.ListObjectVersions(&s3.ListObjectVersionsInput{
+				Bucket: aws.String(bucketName),
+				Prefix: aws.String(objectKey),
+			})
+			require.NoError(t, err, "Failed to list versions of %s", objectKey)
+
+			var versionIDs []string
+			for _, version := range listed.Versions {
+				if aws.StringValue(version.Key) == objectKey {
+					versionIDs = append(versionIDs, aws.StringValue(version.VersionId))
+				}
+			}
+
+			if testCase.enableVersioning {
+				require.Len(t, versionIDs, 2, "Both puts should be kept as versions")
+				assert.NotEqual(t, versionIDs[0], versionIDs[1], "Each version should have its own VersionId")
+				assert.ElementsMatch(t, putVersionIDs, versionIDs)
+
+				// Each version still has the content it was uploaded with, not the latest
+				for i, content := range contents {
+					assert.Equal(t, content, getObjectVersion(t, s3Client, bucketName, objectKey, putVersionIDs[i]))
+				}
+				t.Logf("✅ Kept versions %v of %s", versionIDs, objectKey)
+			} else {
+				// With versioning suspended, every put writes the null version, so the second replaces the first
+				require.Len(t, versionIDs, 1, "The second put should overwrite the first")
+				assert.Equal(t, "null", versionIDs[0])
+				assert.Equal(t, contents[1], getObjectVersion(t, s3Client, bucketName, objectKey, "null"))
+				t.Logf("✅ Second put overwrote %s under the null version", objectKey)
+			}
+		})
+	}
+}
+
+// getObjectVersion returns the content of a specific version of an object
+func getObjectVersion(t *testing.T, s3Client *s3.S3, bucket, key, versionID string) string {
+	t.Helper()
+
+	result, err := s3Client.GetObject(&s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	require.NoError(t, err, "Failed to get version %s of s3://%s/%s", versionID, bucket, key)
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -0,0 +1,47 @@
+"""
+Prometheus metrics in the text exposition format.
+
+Counts are kept per Gunicorn worker process and labelled with its pid, since workers share no memory and a scrape is
+answered by whichever worker accepts it.
+"""
+import os
+import threading
+from collections import Counter
+
+from django.db import connections
+
+CONTENT_TYPE = 'text/plain; version=0.0.4; charset=utf-8'
+
+_lock = threading.Lock()
+_requests = Counter()
+
+
+def record_request(method, status_code):
+    """Count a response to a request with the given method."""
+    with _lock:
+        _requests[(method, status_code)] += 1
+
+
+def render():
+    """Render every metric in the Prometheus text exposition format."""
+    pid = os.getpid()
+    with _lock:
+        requests = sorted(_requests.items())
+
+    lines = [
+        '# HELP django_http_requests_total Total HTTP requests handled by this worker.',
+        '# TYPE django_http_requests_total counter',
+    ]
+    for (method, status_code), count in requests:
+        lines.append(f'django_http_requests_total{{method="{method}",status="{status_code}",pid="{pid}"}} {count}')
+
+    # Django keeps a connection per thread, so this reports the connections open in the thread handling the scrape
+    lines += [
+        '# HELP django_db_connections_open Open database connections held by this worker.',
+        '# TYPE django_db_connections_open gauge',
+    ]
+    for alias in connections:
+        is_open = int(connections[alias].connection is not None)
+        lines.append(f'django_db_connections_open{{alias="{alias}",pid="{pid}"}} {is_open}')
+
+    return '\n'.join(lines) + '\n'
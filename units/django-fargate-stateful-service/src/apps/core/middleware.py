@@ -0,0 +1,114 @@
+"""Core middleware"""
+import logging
+import threading
+
+from django.conf import settings
+from django.core.exceptions import RequestDataTooBig
+from django.db import OperationalError
+from django.http import JsonResponse
+
+from . import metrics
+
+logger = logging.getLogger(__name__)
+
+
+class DatabaseBackpressureMiddleware:
+    """
+    Limit how many requests per worker process may hold a database connection at once, and turn connection
+    exhaustion into a 503 with Retry-After instead of a 500.
+
+    Requests wait up to DB_POOL_TIMEOUT seconds for one of DB_POOL_SIZE slots; a DB_POOL_SIZE of 0 disables the limit.
+    Health checks are never turned away, so a busy task isn't mistaken for a dead one.
+    """
+
+    exempt_path_prefixes = ('/health/',)
+
+    def __init__(self, get_response):
+        self.get_response = get_response
+        self.timeout = settings.DB_POOL_TIMEOUT
+        self.slots = threading.BoundedSemaphore(settings.DB_POOL_SIZE) if settings.DB_POOL_SIZE > 0 else None
+
+    def __call__(self, request):
+        if self.slots is None or request.path.startswith(self.exempt_path_prefixes):
+            return self.get_response(request)
+
+        if not self.slots.acquire(timeout=self.timeout):
+            logger.warning('No database connection slot free after %ss, returning 503 for %s', self.timeout, request.path)
+            return self._unavailable('Database connection pool exhausted')
+
+        try:
+            return self.get_response(request)
+        finally:
+            self.slots.release()
+
+    def process_exception(self, request, exception):
+        # The database refusing new connections (e.g. "too many connections") is a capacity problem, not a bug
+        if isinstance(exception, OperationalError):
+            logger.warning('Database unavailable, returning 503 for %s: %s', request.path, exception)
+            return self._unavailable('Database unavailable')
+        return None
+
+    def _unavailable(self, message):
+        response = JsonResponse({'error': message}, status=503)
+        response['Retry-After'] = str(max(1, int(self.timeout)))
+        return response
+
+
+class RequestBodyLimitMiddleware:
+    """
+    Turn a request body larger than DATA_UPLOAD_MAX_MEMORY_SIZE into a 413 instead of Django's generic 400, so clients
+    can tell an oversized upload apart from a malformed one.
+    """
+
+    def __init__(self, get_response):
+        self.get_response = get_response
+        self.limit = settings.DATA_UPLOAD_MAX_MEMORY_SIZE
+
+    def __call__(self, request):
+        return self.get_response(request)
+
+    def process_exception(self, request, exception):
+        if isinstance(exception, RequestDataTooBig):
+            logger.warning('Request body over %s bytes, returning 413 for %s', self.limit, request.path)
+            return JsonResponse({'error': f'Request body exceeds {self.limit} bytes'}, status=413)
+        return None
+
+
+class CacheControlMiddleware:
+    """
+    Set Cache-Control on dynamic responses from CACHE_CONTROL_RULES, a list of {path_prefix, cache_control} pairs where
+    the first matching prefix wins. The rule replaces any header the view set, so an API response can't be cached by a
+    CDN or browser and served to another user because a view forgot to opt out.
+
+    Static files never reach this middleware: WhiteNoise, which sits above it, serves them with a long max-age since
+    their names contain a content hash.
+    """
+
+    def __init__(self, get_response):
+        self.get_response = get_response
+        self.rules = [(rule['path_prefix'], rule['cache_control']) for rule in settings.CACHE_CONTROL_RULES]
+
+    def __call__(self, request):
+        response = self.get_response(request)
+        for path_prefix, cache_control in self.rules:
+            if request.path.startswith(path_prefix):
+                response['Cache-Control'] = cache_control
+                break
+        return response
+
+
+class MetricsMiddleware:
+    """
+    Count every response by method and status code for the Prometheus metrics endpoint. It sits near the top of the
+    stack so responses produced by other middleware, such as backpressure 503s, are counted too.
+    """
+
+    def __init__(self, get_response):
+        self.get_response = get_response
+        self.enabled = settings.METRICS_ENABLED
+
+    def __call__(self, request):
+        response = self.get_response(request)
+        if self.enabled:
+            metrics.record_request(request.method, response.status_code)
+        return response
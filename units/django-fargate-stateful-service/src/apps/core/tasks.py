@@ -0,0 +1,18 @@
+"""Core Celery tasks"""
+import time
+
+from celery import shared_task
+from django.conf import settings
+
+
+@shared_task(name='debug.tasks.sleep')
+def sleep_test(seconds):
+    """
+    Sleep for the given number of seconds (at most 300), to exercise worker draining on shutdown.
+    Only runs when the sleep_test_task feature flag is enabled.
+    """
+    if not settings.FEATURE_FLAGS.get('sleep_test_task', False):
+        raise RuntimeError('The sleep_test_task feature flag is disabled')
+    seconds = min(int(seconds), 300)
+    time.sleep(seconds)
+    return seconds
@@ -2,10 +2,29 @@
 from django.urls import path
 from rest_framework_simplejwt.views import TokenObtainPairView, TokenRefreshView
 
+from . import views
+
 app_name = 'core'
 
 urlpatterns = [
     # JWT authentication endpoints
     path('token/', TokenObtainPairView.as_view(), name='token_obtain_pair'),
     path('token/refresh/', TokenRefreshView.as_view(), name='token_refresh'),
+
+    # Runtime configuration
+    path('flags/', views.feature_flags, name='feature_flags'),
+    path('status/', views.server_status, name='server_status'),
+
+    # Cache degradation tests (falls through to the database when Redis is unreachable)
+    path('cached/', views.cached_query, name='cached_query'),
+
+    # Alerting tests (disabled unless the error_test_endpoint feature flag is set)
+    path('debug/error/', views.error_test, name='error_test'),
+
+    # Request timeout tests (disabled unless the slow_test_endpoint feature flag is set)
+    path('debug/slow/', views.slow_test, name='slow_test'),
+    path('debug/slow-query/', views.slow_query, name='slow_query'),
+
+    # Request body size tests (disabled unless the echo_test_endpoint feature flag is set)
+    path('debug/echo/', views.echo_test, name='echo_test'),
 ]
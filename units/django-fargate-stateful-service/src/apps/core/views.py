@@ -0,0 +1,140 @@
+"""Core API views"""
+import os
+import time
+
+from django.conf import settings
+from django.core.cache import cache
+from django.db import connection
+from django.http import Http404, HttpResponse, JsonResponse
+from django.views.decorators.csrf import csrf_exempt
+from django.views.decorators.http import require_GET, require_POST
+
+from . import metrics as prometheus_metrics
+
+
+@require_GET
+def feature_flags(request):
+    """
+    Report the runtime feature flags the container was started with.
+    Flags are configured via the module's feature_flags variable.
+    """
+    return JsonResponse({'flags': settings.FEATURE_FLAGS}, status=200)
+
+
+def _count_worker_processes():
+    """
+    Count the processes sharing this worker's parent (the Gunicorn master).
+    Reads /proc directly so the result reflects what is actually running, not just the configured value.
+    """
+    master_pid = os.getppid()
+    count = 0
+    for entry in os.listdir('/proc'):
+        if not entry.isdigit():
+            continue
+        try:
+            with open(f'/proc/{entry}/stat') as stat:
+                # The process name may contain spaces, so parse the fields after its closing parenthesis
+                fields = stat.read().rsplit(')', 1)[1].split()
+        except (OSError, IndexError):
+            continue
+        if int(fields[1]) == master_pid:
+            count += 1
+    return count
+
+
+@require_GET
+def server_status(request):
+    """
+    Report the running and configured Gunicorn worker counts for this task.
+    Used to verify concurrency scales with the task CPU allocation.
+    """
+    return JsonResponse({
+        'workers': _count_worker_processes(),
+        'configured_workers': int(os.getenv('GUNICORN_WORKERS', '0')),
+        'cpu_count': os.cpu_count(),
+    }, status=200)
+
+
+@require_GET
+def cached_query(request):
+    """
+    Return a database value through the cache, reporting whether it came from the cache or the database.
+    Used to verify a cache outage degrades to database reads rather than failing requests.
+    """
+    value = cache.get('cached_query')
+    if value is not None:
+        return JsonResponse({'source': 'cache', 'value': value}, status=200)
+
+    with connection.cursor() as cursor:
+        cursor.execute('SELECT now()::text')
+        value = cursor.fetchone()[0]
+    cache.set('cached_query', value, 60)
+    return JsonResponse({'source': 'database', 'value': value}, status=200)
+
+
+@require_GET
+def error_test(request):
+    """
+    Raise an unhandled exception to exercise error logging and alerting.
+    Only reachable when the error_test_endpoint feature flag is enabled.
+    """
+    if not settings.FEATURE_FLAGS.get('error_test_endpoint', False):
+        raise Http404()
+    raise RuntimeError('Deliberate error for alerting tests')
+
+
+@require_GET
+def slow_test(request):
+    """
+    Sleep for ?seconds=N (default 60) before responding, to exercise the request timeout.
+    Only reachable when the slow_test_endpoint feature flag is enabled.
+    """
+    if not settings.FEATURE_FLAGS.get('slow_test_endpoint', False):
+        raise Http404()
+    try:
+        seconds = min(int(request.GET.get('seconds', '60')), 300)
+    except ValueError:
+        return JsonResponse({'error': 'seconds must be an integer'}, status=400)
+    time.sleep(seconds)
+    return JsonResponse({'slept': seconds}, status=200)
+
+
+@require_GET
+def slow_query(request):
+    """
+    Hold a database connection for ?seconds=N (default 10) with pg_sleep, to exercise connection pool backpressure.
+    Only reachable when the slow_test_endpoint feature flag is enabled.
+    """
+    if not settings.FEATURE_FLAGS.get('slow_test_endpoint', False):
+        raise Http404()
+    try:
+        seconds = min(int(request.GET.get('seconds', '10')), 300)
+    except ValueError:
+        return JsonResponse({'error': 'seconds must be an integer'}, status=400)
+    with connection.cursor() as cursor:
+        cursor.execute('SELECT pg_sleep(%s)', [seconds])
+    return JsonResponse({'slept': seconds}, status=200)
+
+
+@csrf_exempt
+@require_POST
+def echo_test(request):
+    """
+    Read the whole request body and report its size, to exercise the DATA_UPLOAD_MAX_MEMORY_SIZE limit.
+    Only reachable when the echo_test_endpoint feature flag is enabled.
+    """
+    if not settings.FEATURE_FLAGS.get('echo_test_endpoint', False):
+        raise Http404()
+    return JsonResponse({'received': len(request.body)}, status=200)
+
+
+@require_GET
+def metrics(request):
+    """
+    Serve Prometheus metrics to scrapers inside the VPC.
+    Requests relayed by the ALB carry X-Forwarded-For and are refused, so the endpoint stays private even if the
+    ALB's rule blocking metrics_path is removed.
+    """
+    if not settings.METRICS_ENABLED or 'HTTP_X_FORWARDED_FOR' in request.META:
+        raise Http404()
+    return HttpResponse(prometheus_metrics.render(), content_type=prometheus_metrics.CONTENT_TYPE)
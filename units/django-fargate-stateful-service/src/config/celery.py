@@ -0,0 +1,14 @@
+"""Celery app for Django API project"""
+import os
+
+from celery import Celery
+
+os.environ.setdefault('DJANGO_SETTINGS_MODULE', 'config.settings.prod')
+
+app = Celery('config')
+
+# Read the CELERY_* settings from Django settings
+app.config_from_object('django.conf:settings', namespace='CELERY')
+
+# Load tasks.py from each installed app
+app.autodiscover_tasks()
@@ -2,6 +2,7 @@
 Django settings for Django API project.
 Base settings shared across all environments.
 """
+import json
 import os
 from pathlib import Path
 
@@ -47,7 +48,9 @@ INSTALLED_APPS = [
 
 MIDDLEWARE = [
     'django.middleware.security.SecurityMiddleware',
+    'apps.core.middleware.MetricsMiddleware',
     'whitenoise.middleware.WhiteNoiseMiddleware',  # Static files
+    'apps.core.middleware.CacheControlMiddleware',
     'corsheaders.middleware.CorsMiddleware',
     'django.contrib.sessions.middleware.SessionMiddleware',
     'django.middleware.common.CommonMiddleware',
@@ -55,6 +58,8 @@ MIDDLEWARE = [
     'django.contrib.auth.middleware.AuthenticationMiddleware',
     'django.contrib.messages.middleware.MessageMiddleware',
     'django.middleware.clickjacking.XFrameOptionsMiddleware',
+    'apps.core.middleware.DatabaseBackpressureMiddleware',
+    'apps.core.middleware.RequestBodyLimitMiddleware',
 ]
 
 ROOT_URLCONF = 'config.urls'
@@ -83,11 +88,20 @@ ASGI_APPLICATION = 'config.asgi.application'
 DATABASES = {
     'default': dj_database_url.config(
         default=env('DATABASE_URL', default='sqlite:///:memory:'),
-        conn_max_age=600,
+        conn_max_age=env.int('DB_CONN_MAX_AGE', default=600),
         conn_health_checks=True,
     )
 }
 
+# Database backpressure (see apps.core.middleware.DatabaseBackpressureMiddleware). Each Gunicorn thread holds its own
+# connection, so DB_POOL_SIZE caps connections per worker process; 0 means unlimited.
+DB_POOL_SIZE = env.int('DB_POOL_SIZE', default=0)
+DB_POOL_TIMEOUT = env.float('DB_POOL_TIMEOUT', default=5)
+
+# Largest request body Django will read into memory, set by the module's max_request_body_size variable. Larger bodies
+# are rejected with a 413 (see apps.core.middleware.RequestBodyLimitMiddleware).
+DATA_UPLOAD_MAX_MEMORY_SIZE = env.int('DATA_UPLOAD_MAX_MEMORY_SIZE', default=2621440)
+
 # Password validation
 # https://docs.djangoproject.com/en/5.0/ref/settings/#auth-password-validators
 AUTH_PASSWORD_VALIDATORS = [
@@ -110,6 +124,13 @@ STATIC_URL = '/static/'
 STATIC_ROOT = BASE_DIR / 'staticfiles'
 STATICFILES_STORAGE = 'whitenoise.storage.CompressedManifestStaticFilesStorage'
 
+# Cache-Control for dynamic responses (see apps.core.middleware.CacheControlMiddleware), set by the module's
+# cache_control_rules variable. The first rule whose path_prefix matches the request path applies.
+CACHE_CONTROL_RULES = json.loads(env('CACHE_CONTROL_RULES', default=json.dumps([
+    {'path_prefix': '/api/', 'cache_control': 'no-store, private'},
+    {'path_prefix': '/admin/', 'cache_control': 'no-store, private'},
+])))
+
 # Media files
 MEDIA_URL = '/media/'
 MEDIA_ROOT = BASE_DIR / 'media'
@@ -165,36 +186,50 @@ SIMPLE_JWT = {
 CORS_ALLOW_CREDENTIALS = True
 CORS_ALLOWED_ORIGINS = env.list('CORS_ALLOWED_ORIGINS', default=[])
 
+# Feature flags (JSON map of flag name -> bool, set by the module's feature_flags variable)
+FEATURE_FLAGS = json.loads(env('FEATURE_FLAGS', default='{}'))
+
+# Prometheus metrics (see apps.core.metrics), set by the module's metrics_enabled and metrics_path variables
+METRICS_ENABLED = env.bool('METRICS_ENABLED', default=False)
+METRICS_PATH = env('METRICS_PATH', default='/metrics/')
+
 # Redis configuration (if available)
 REDIS_URL = env('REDIS_URL', default=None)
 
 if REDIS_URL:
-    # Cache configuration
+    # Cache configuration. The cache is optional: if Redis is unreachable, cache operations are logged and treated as
+    # misses so requests fall through to the database instead of failing. The short timeouts bound the extra latency.
     CACHES = {
         'default': {
             'BACKEND': 'django_redis.cache.RedisCache',
             'LOCATION': REDIS_URL,
             'OPTIONS': {
                 'CLIENT_CLASS': 'django_redis.client.DefaultClient',
-                'SOCKET_CONNECT_TIMEOUT': 5,
-                'SOCKET_TIMEOUT': 5,
+                'SOCKET_CONNECT_TIMEOUT': 1,
+                'SOCKET_TIMEOUT': 1,
+                'IGNORE_EXCEPTIONS': True,
             }
         }
     }
+    DJANGO_REDIS_LOG_IGNORED_EXCEPTIONS = True
 
-    # Session backend (optional)
-    SESSION_ENGINE = 'django.contrib.sessions.backends.cache'
+    # Session backend (optional). Sessions are written through to the database so they survive a cache outage.
+    SESSION_ENGINE = 'django.contrib.sessions.backends.cached_db'
     SESSION_CACHE_ALIAS = 'default'
 
-# Celery Configuration
+# Celery Configuration (see config.celery). The worker runs alongside Gunicorn when a broker is configured.
 CELERY_BROKER_URL = env('CELERY_BROKER_URL', default=REDIS_URL)
-CELERY_RESULT_BACKEND = REDIS_URL
+CELERY_RESULT_BACKEND = env('CELERY_RESULT_BACKEND', default=REDIS_URL)
 CELERY_ACCEPT_CONTENT = ['json']
 CELERY_TASK_SERIALIZER = 'json'
 CELERY_RESULT_SERIALIZER = 'json'
 CELERY_TIMEZONE = TIME_ZONE
 CELERY_TASK_TRACK_STARTED = True
 CELERY_TASK_TIME_LIMIT = 30 * 60  # 30 minutes
+# Acknowledge tasks after they finish rather than when they start, so a task killed mid-run is redelivered. With late
+# acks a worker should only reserve the task it is running, or its prefetched tasks wait out its shutdown.
+CELERY_TASK_ACKS_LATE = env.bool('CELERY_TASK_ACKS_LATE', default=False)
+CELERY_WORKER_PREFETCH_MULTIPLIER = 1 if CELERY_TASK_ACKS_LATE else 4
 
 # Logging configuration
 LOGGING = {
@@ -1,9 +1,15 @@
 """URL configuration for Django API"""
+from django.conf import settings
 from django.contrib import admin
 from django.urls import path, include
 
+from apps.core import views as core_views
+
 urlpatterns = [
     path('admin/', admin.site.urls),
     path('api/', include('apps.core.urls')),
     path('health/', include('apps.health.urls')),
 ]
+
+if settings.METRICS_ENABLED:
+    urlpatterns.append(path(settings.METRICS_PATH.lstrip('/'), core_views.metrics, name='metrics'))
@@ -7,10 +7,17 @@ bind = "0.0.0.0:8000"
 backlog = 2048
 
 # Worker processes
+# GUNICORN_WORKERS is derived from the task CPU allocation by the module; cpu_count() reports the host's CPUs on
+# Fargate, so it is only a fallback for local development
 workers = int(os.getenv('GUNICORN_WORKERS', multiprocessing.cpu_count() * 2 + 1))
-worker_class = "sync"
+# With more than one thread per worker Gunicorn switches to the gthread worker. Each thread holds its own database
+# connection, so DB_POOL_SIZE should be no larger than this.
+threads = int(os.getenv('GUNICORN_THREADS', 1))
+worker_class = "sync" if threads == 1 else "gthread"
 worker_connections = 1000
-timeout = 30
+# Workers handling a request for longer than GUNICORN_TIMEOUT seconds are killed and restarted, so a slow endpoint
+# can't tie up the worker pool. Set by the module's request_timeout variable.
+timeout = int(os.getenv('GUNICORN_TIMEOUT', 30))
 keepalive = 2
 
 # Logging
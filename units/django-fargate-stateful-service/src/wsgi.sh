@@ -5,6 +5,11 @@ echo "[INFO] Starting Django application..."
 
 # Run database migrations
 echo "[INFO] Running database migrations..."
+# MIGRATION_DELAY_SECONDS simulates a slow migration, to test that health check grace periods cover it
+if [ "${MIGRATION_DELAY_SECONDS:-0}" -gt 0 ]; then
+    echo "[INFO] Delaying migrations by ${MIGRATION_DELAY_SECONDS} seconds..."
+    sleep "${MIGRATION_DELAY_SECONDS}"
+fi
 python manage.py migrate --noinput
 
 # Collect static files
@@ -13,8 +18,50 @@ python manage.py collectstatic --noinput --clear
 
 # Start Gunicorn
 echo "[INFO] Starting Gunicorn server..."
-exec gunicorn config.wsgi:application \
+gunicorn config.wsgi:application \
     --config gunicorn.conf.py \
     --log-file - \
     --access-logfile - \
-    --error-logfile -
+    --error-logfile - &
+PIDS=($!)
+
+# Start a Celery worker alongside Gunicorn when a broker is configured
+if [ -n "${CELERY_BROKER_URL:-}" ]; then
+    echo "[INFO] Starting Celery worker..."
+    celery --app config worker \
+        --loglevel "${CELERY_LOG_LEVEL:-info}" \
+        --concurrency "${CELERY_WORKER_CONCURRENCY:-2}" &
+    PIDS+=($!)
+fi
+
+# ECS sends SIGTERM to this script, then SIGKILL after the task's stop_timeout. Forward it so Gunicorn finishes its
+# in-flight requests and the Celery worker finishes its running tasks (warm shutdown) before both exit.
+STOPPING=""
+shutdown() {
+    STOPPING=1
+    echo "[INFO] Received SIGTERM, draining Gunicorn and Celery..."
+    kill -TERM "${PIDS[@]}" 2>/dev/null || true
+}
+trap shutdown TERM INT
+
+set +e
+
+# Returns when either process exits, or early when the trap runs
+wait -n "${PIDS[@]}"
+STATUS=$?
+
+# If a process exited on its own, stop the other and fail so ECS replaces the task
+if [ -z "${STOPPING}" ]; then
+    echo "[ERROR] A server process exited unexpectedly with status ${STATUS}, stopping the container..."
+    kill -TERM "${PIDS[@]}" 2>/dev/null
+    wait
+    exit $(( STATUS == 0 ? 1 : STATUS ))
+fi
+
+# Exit non-zero if either process failed to drain cleanly
+STATUS=0
+for pid in "${PIDS[@]}"; do
+    wait "${pid}" || STATUS=$?
+done
+echo "[INFO] Shutdown complete"
+exit "${STATUS}"
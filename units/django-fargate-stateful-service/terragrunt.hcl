@@ -64,22 +64,24 @@ inputs = {
   celery_broker_url = dependency.redis.outputs.celery_broker_url
 
   # Optional inputs
-  environment                       = try(values.environment, "prod")
-  debug                             = try(values.debug, false)
-  aws_region                        = try(values.aws_region, "us-east-1")
-  vpc_id                            = try(values.vpc_id, null)
-  private_subnet_ids                = try(values.private_subnet_ids, null)
-  public_subnet_ids                 = try(values.public_subnet_ids, null)
-  alb_port                          = try(values.alb_port, 80)
-  container_port                    = try(values.container_port, 8000)
-  health_check_path                 = try(values.health_check_path, "/health/live/")
-  health_check_interval             = try(values.health_check_interval, 30)
-  health_check_timeout              = try(values.health_check_timeout, 5)
-  health_check_healthy_threshold    = try(values.health_check_healthy_threshold, 2)
-  health_check_unhealthy_threshold  = try(values.health_check_unhealthy_threshold, 3)
-  enable_ecs_managed_tags           = try(values.enable_ecs_managed_tags, true)
-  enable_container_insights         = try(values.enable_container_insights, true)
-  cloudwatch_log_retention_days     = try(values.cloudwatch_log_retention_days, 30)
+  environment                         = try(values.environment, "prod")
+  debug                               = try(values.debug, false)
+  aws_region                          = try(values.aws_region, "us-east-1")
+  vpc_id                              = try(values.vpc_id, null)
+  private_subnet_ids                  = try(values.private_subnet_ids, null)
+  public_subnet_ids                   = try(values.public_subnet_ids, null)
+  alb_port                            = try(values.alb_port, 80)
+  container_port                      = try(values.container_port, 8000)
+  health_check_path                   = try(values.health_check_path, "/health/live/")
+  health_check_interval               = try(values.health_check_interval, 30)
+  health_check_timeout                = try(values.health_check_timeout, 5)
+  health_check_healthy_threshold      = try(values.health_check_healthy_threshold, 2)
+  health_check_unhealthy_threshold    = try(values.health_check_unhealthy_threshold, 3)
+  health_check_grace_period_seconds   = try(values.health_check_grace_period_seconds, 120)
+  container_health_check_start_period = try(values.container_health_check_start_period, 60)
+  enable_ecs_managed_tags             = try(values.enable_ecs_managed_tags, true)
+  enable_container_insights           = try(values.enable_container_insights, true)
+  cloudwatch_log_retention_days       = try(values.cloudwatch_log_retention_days, 30)
 
   # Service security group IDs
   service_security_group_id = try(values.service_security_group_id, null)
@@ -88,12 +90,51 @@ inputs = {
   # Task IAM role
   task_role_arn = try(values.task_role_arn, null)
 
+  # ECS Exec (used by in-VPC connectivity tests)
+  enable_execute_command = try(values.enable_execute_command, false)
+
+  # Error rate alerting from ERROR log lines
+  create_error_rate_alarm    = try(values.create_error_rate_alarm, false)
+  error_rate_alarm_threshold = try(values.error_rate_alarm_threshold, 10)
+  alarm_actions              = try(values.alarm_actions, [])
+
+  # Runtime feature flags (exposed at /api/flags/)
+  feature_flags = try(values.feature_flags, {})
+
+  # Gunicorn worker processes (defaults to 2 * vCPU + 1 from cpu)
+  gunicorn_workers = try(values.gunicorn_workers, null)
+
+  # Seconds a request may run before Gunicorn kills the worker
+  request_timeout = try(values.request_timeout, 30)
+
+  # Seconds a stopping task gets to finish in-flight Celery tasks before it is killed
+  stop_timeout = try(values.stop_timeout, 30)
+
+  # Largest request body Django reads into memory; larger requests receive a 413
+  max_request_body_size = try(values.max_request_body_size, 2621440)
+
+  # Database connection limits; requests that can't get a connection slot receive a 503
+  gunicorn_threads = try(values.gunicorn_threads, 1)
+  db_pool_size     = try(values.db_pool_size, null)
+  db_pool_timeout  = try(values.db_pool_timeout, 5)
+  db_conn_max_age  = try(values.db_conn_max_age, 600)
+
+  # Cache-Control for dynamic responses by path prefix; API and admin responses must never be cached
+  cache_control_rules = try(values.cache_control_rules, [
+    { path_prefix = "/api/", cache_control = "no-store, private" },
+    { path_prefix = "/admin/", cache_control = "no-store, private" },
+  ])
+
+  # Prometheus metrics, served only to scrapers inside the VPC
+  metrics_enabled        = try(values.metrics_enabled, false)
+  metrics_path           = try(values.metrics_path, "/metrics/")
+  metrics_allowed_sg_ids = try(values.metrics_allowed_sg_ids, [])
+
   # Additional environment variables
   additional_environment_variables = merge(
     try(values.additional_environment_variables, {}),
     {
       # Add any custom environment variables here
-      GUNICORN_WORKERS = try(values.gunicorn_workers, "4")
       GUNICORN_LOG_LEVEL = try(values.gunicorn_log_level, "info")
     }
   )
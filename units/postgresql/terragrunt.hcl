@@ -12,7 +12,7 @@ inputs = {
   instance_class    = values.instance_class
   allocated_storage = values.allocated_storage
   master_username   = values.master_username
-  master_password   = values.master_password
+  master_password   = try(values.master_password, null)
 
   # Optional inputs - Production defaults
   storage_type            = try(values.storage_type, "gp3")
@@ -32,7 +32,7 @@ inputs = {
 
   # Security
   storage_encrypted = try(values.storage_encrypted, true)
-  kms_key_id        = try(values.kms_key_id, null)
+  kms_key_arn       = try(values.kms_key_arn, values.kms_key_id, null)
 
   # Monitoring
   enabled_cloudwatch_logs_exports = try(values.enabled_cloudwatch_logs_exports, ["postgresql", "upgrade"])
@@ -52,6 +52,15 @@ inputs = {
   maintenance_work_mem = try(values.maintenance_work_mem, "65536")  # 64MB
   effective_cache_size = try(values.effective_cache_size, "131072") # 1GB
 
+  # Let RDS generate the master password and rotate it in Secrets Manager instead of passing master_password
+  manage_master_user_password = try(values.manage_master_user_password, false)
+
+  # Extra databases created post-provision
+  additional_databases = try(values.additional_databases, [])
+
+  # Create the instance as a point-in-time restore of another instance
+  restore_to_point_in_time = try(values.restore_to_point_in_time, null)
+
   # Networking (REQUIRED for module)
   vpc_id     = values.vpc_id
   subnet_ids = values.subnet_ids
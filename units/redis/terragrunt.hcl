@@ -14,7 +14,8 @@ inputs = {
   vpc_id     = values.vpc_id
 
   # Optional inputs - Production defaults
-  engine_version = try(values.engine_version, "7.1")
+  engine         = try(values.engine, "redis")
+  engine_version = try(values.engine_version, null) # module picks a default per engine
   port           = try(values.port, 6379)
   environment    = try(values.environment, "prod")
 
@@ -34,10 +35,11 @@ inputs = {
   snapshot_window            = try(values.snapshot_window, "03:00-04:00")
   snapshot_retention_limit   = try(values.snapshot_retention_limit, 7)
   auto_minor_version_upgrade = try(values.auto_minor_version_upgrade, true)
+  apply_immediately          = try(values.apply_immediately, false)
 
   # Parameter group
   parameter_group_name   = try(values.parameter_group_name, null)
-  parameter_group_family = try(values.parameter_group_family, "redis7")
+  parameter_group_family = try(values.parameter_group_family, null)
   maxmemory_policy       = try(values.maxmemory_policy, "allkeys-lru")
   timeout                = try(values.timeout, "300")
 
@@ -28,6 +28,10 @@ inputs = {
   website_index_document = try(values.website_index_document, "index.html")
   website_error_document = try(values.website_error_document, "404.html")
 
+  # Cloudflare proxies to the HTTP-only website endpoint, which require_https would block. Set it to true once
+  # Cloudflare fetches from the REST endpoint (bucket_regional_domain_name) over HTTPS instead.
+  require_https = try(values.require_https, false)
+
   # CORS for cross-domain asset requests
   # SECURITY: cors_allowed_origins is REQUIRED - no default to prevent accidental exposure
   # Example: ["https://example.com", "https://www.example.com"]